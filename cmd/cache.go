@@ -0,0 +1,253 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/images"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// cacheImagesPath is the path in the image cache volume where images
+// imported into clusters are stored
+const cacheImagesPath = utils.ImageVolumeMountPath + "/images"
+
+// cachedImage is an image that has been stored in the image cache volume
+type cachedImage struct {
+	Name     string
+	File     string
+	Size     uint64
+	Modified time.Time
+}
+
+func newCacheCmd(dt container.ContainerTasks, il images.ImageLog, l logger.Logger) *cobra.Command {
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the images cached by jumppad",
+		Long:  "Manage the images cached by jumppad",
+	}
+
+	cacheCmd.AddCommand(newCacheListCmd(dt, il))
+	cacheCmd.AddCommand(newCacheClearCmd(dt, il, l))
+
+	return cacheCmd
+}
+
+func newCacheListCmd(dt container.ContainerTasks, il images.ImageLog) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the images in the jumppad image cache",
+		Long: `List the images that have been imported into the cluster image cache
+and the images that have been pulled by jumppad`,
+		Example: `
+  jumppad cache list
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cached, err := listCachedImages(dt)
+			if err != nil {
+				return err
+			}
+
+			pulled, _ := il.Read(images.ImageTypeDocker)
+
+			fmt.Println(whiteText.Render("Cached images"))
+			fmt.Println()
+
+			var total uint64
+			for _, c := range cached {
+				total += c.Size
+				fmt.Printf("  %-60s %10s  %s\n", c.Name, formatBytes(c.Size), grayText.Render(c.Modified.Format(time.DateTime)))
+			}
+
+			if len(cached) == 0 {
+				fmt.Println(grayText.Render("  no images cached"))
+			}
+
+			fmt.Println()
+			fmt.Printf("  %d images, %s\n", len(cached), formatBytes(total))
+
+			fmt.Println()
+			fmt.Println(whiteText.Render("Pulled images"))
+			fmt.Println()
+
+			for _, p := range pulled {
+				fmt.Printf("  %s\n", p)
+			}
+
+			if len(pulled) == 0 {
+				fmt.Println(grayText.Render("  no images pulled"))
+			}
+
+			return nil
+		},
+	}
+}
+
+func newCacheClearCmd(dt container.ContainerTasks, il images.ImageLog, l logger.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear [image]",
+		Short: "Remove images from the jumppad image cache",
+		Long: `Remove images from the jumppad image cache, when an image is specified
+only that image is removed, otherwise all cached images are removed`,
+		Example: `
+  # Remove a single image from the cache
+  jumppad cache clear nginx:latest
+
+  # Remove all images from the cache
+  jumppad cache clear
+`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				l.Info("Removing all images from cache")
+
+				_, err := runInCacheVolume(dt, []string{"sh", "-c", fmt.Sprintf("rm -rf %s/*", cacheImagesPath)})
+				if err != nil {
+					return fmt.Errorf("unable to clear image cache: %w", err)
+				}
+
+				il.Clear()
+
+				return nil
+			}
+
+			cached, err := listCachedImages(dt)
+			if err != nil {
+				return err
+			}
+
+			files := []string{}
+			for _, c := range cached {
+				if c.Name == args[0] || strings.HasSuffix(c.Name, "/"+args[0]) {
+					files = append(files, c.File)
+				}
+			}
+
+			if len(files) > 0 {
+				l.Info("Removing image from cache", "image", args[0])
+
+				_, err = runInCacheVolume(dt, append([]string{"rm", "-f"}, files...))
+				if err != nil {
+					return fmt.Errorf("unable to remove image %s from cache: %w", args[0], err)
+				}
+			}
+
+			err = il.Remove(args[0], images.ImageTypeDocker)
+			if err != nil {
+				l.Debug("Unable to remove image from image log", "image", args[0], "error", err)
+			}
+
+			if len(files) == 0 {
+				return fmt.Errorf("image %s was not found in the cache", args[0])
+			}
+
+			return nil
+		},
+	}
+}
+
+// listCachedImages returns the images stored in the image cache volume
+func listCachedImages(dt container.ContainerTasks) ([]cachedImage, error) {
+	out, err := runInCacheVolume(dt, []string{"sh", "-c", fmt.Sprintf("stat -c '%%n %%s %%Y' %s/* 2>/dev/null || true", cacheImagesPath)})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list image cache: %w", err)
+	}
+
+	return parseCachedImages(out), nil
+}
+
+// parseCachedImages parses the output of stat for the files in the cache,
+// the file names are the base64 encoded image names
+func parseCachedImages(out string) []cachedImage {
+	cached := []cachedImage{}
+
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			continue
+		}
+
+		name, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(parts[0], cacheImagesPath+"/"))
+		if err != nil {
+			continue
+		}
+
+		size, _ := strconv.ParseUint(parts[1], 10, 64)
+		mod, _ := strconv.ParseInt(parts[2], 10, 64)
+
+		cached = append(cached, cachedImage{
+			Name:     string(name),
+			File:     parts[0],
+			Size:     size,
+			Modified: time.Unix(mod, 0),
+		})
+	}
+
+	return cached
+}
+
+// runInCacheVolume runs the command in a temporary container that has the
+// image cache volume mounted and returns the output
+func runInCacheVolume(dt container.ContainerTasks, command []string) (string, error) {
+	img := types.Image{Name: "alpine:latest"}
+	err := dt.PullImage(img, false)
+	if err != nil {
+		return "", fmt.Errorf("unable to pull 'alpine:latest' needed to access the image cache: %w", err)
+	}
+
+	// the container only reads the cache volume and does not need a network
+	cc := &types.Container{
+		Name:        fmt.Sprintf("%d-cache", time.Now().UnixNano()),
+		Image:       &img,
+		Command:     []string{"tail", "-f", "/dev/null"},
+		NetworkMode: "none",
+		Volumes: []types.Volume{
+			{
+				Source:      utils.FQDNVolumeName(utils.ImageVolumeName),
+				Destination: utils.ImageVolumeMountPath,
+				Type:        "volume",
+			},
+		},
+	}
+
+	id, err := dt.CreateContainer(cc)
+	if err != nil {
+		return "", fmt.Errorf("unable to create container for accessing the image cache: %w", err)
+	}
+	defer dt.RemoveContainer(id, true)
+
+	out := bytes.NewBufferString("")
+	_, err = dt.ExecuteCommand(id, command, nil, "/", "", "", 300, out)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.ReplaceAll(out.String(), "\r", ""), nil
+}
+
+// formatBytes returns a human readable size
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(b)/float64(div), "KMGTPE"[exp])
+}
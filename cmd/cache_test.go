@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCachedImagesDecodesNames(t *testing.T) {
+	name := base64.StdEncoding.EncodeToString([]byte("docker.io/library/nginx:latest"))
+	out := "/cache/images/" + name + " 2048 1700000000\n\n/cache/images/not-base64! 10 1700000000\n"
+
+	cached := parseCachedImages(out)
+
+	require.Len(t, cached, 1)
+	require.Equal(t, "docker.io/library/nginx:latest", cached[0].Name)
+	require.Equal(t, "/cache/images/"+name, cached[0].File)
+	require.Equal(t, uint64(2048), cached[0].Size)
+	require.Equal(t, int64(1700000000), cached[0].Modified.Unix())
+}
+
+func TestFormatBytes(t *testing.T) {
+	require.Equal(t, "512 B", formatBytes(512))
+	require.Equal(t, "1.5 KiB", formatBytes(1536))
+	require.Equal(t, "10.0 MiB", formatBytes(10*1024*1024))
+}
+
+func TestRunInCacheVolumeDisablesNetworking(t *testing.T) {
+	dt := &mocks.ContainerTasks{}
+	dt.On("PullImage", mock.Anything, false).Return(nil)
+	dt.On("CreateContainer", mock.Anything).Return("abc", nil)
+	dt.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	dt.On("RemoveContainer", "abc", true).Return(nil)
+
+	_, err := runInCacheVolume(dt, []string{"ls"})
+	require.NoError(t, err)
+
+	params := testutils.GetCalls(&dt.Mock, "CreateContainer")[0].Arguments[0].(*types.Container)
+	require.Equal(t, "none", params.NetworkMode)
+	require.Empty(t, params.Networks)
+}
@@ -1,24 +1,116 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"time"
 
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/http"
+	kclients "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/system"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
 	"github.com/spf13/cobra"
 )
 
-var checkCmd = &cobra.Command{
-	Use:   "check",
-	Short: "Checks the system to ensure required dependencies are installed",
-	Long:  `Checks the system to ensure required dependencies are installed`,
-	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Do Stuff Here
-		s := system.SystemImpl{}
-		o, _ := s.Preflight()
-
-		fmt.Println("")
-		fmt.Println("###### SYSTEM DIAGNOSTICS ######")
-		fmt.Println(o)
-	},
+func newCheckCmd(p config.Providers, hc http.HTTP, kc kclients.Kubernetes) *cobra.Command {
+	var timeout time.Duration
+
+	checkCmd := &cobra.Command{
+		Use:   "check",
+		Short: "Checks the system dependencies and the health of any running resources",
+		Long: `Checks the system to ensure required dependencies are installed
+
+When resources have been created, each resource is looked up and its health
+checks are run without changing anything, the command exits with an error
+when any resource is not healthy.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := system.SystemImpl{}
+			o, _ := s.Preflight()
+
+			fmt.Println("")
+			fmt.Println("###### SYSTEM DIAGNOSTICS ######")
+			fmt.Println(o)
+
+			// no resources have been created
+			c, err := config.LoadState()
+			if err != nil || len(c.Resources) == 0 {
+				return nil
+			}
+
+			fmt.Println("")
+			fmt.Println("###### RESOURCE HEALTH ######")
+
+			failed := 0
+			for _, r := range c.Resources {
+				if r.GetDisabled() ||
+					r.Metadata().Type == resources.TypeModule ||
+					r.Metadata().Type == resources.TypeVariable ||
+					r.Metadata().Type == resources.TypeLocal ||
+					r.Metadata().Type == resources.TypeOutput {
+					continue
+				}
+
+				err := checkResource(cmd.Context(), r, p, hc, kc, timeout)
+				if err != nil {
+					fmt.Printf("%s %s\n", redIcon.Render("✘"), r.Metadata().ID)
+					fmt.Printf("    %s %s\n", grayText.Render("└─"), whiteText.Render(err.Error()))
+					failed++
+
+					continue
+				}
+
+				fmt.Printf("%s %s\n", greenIcon.Render("✔"), r.Metadata().ID)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d resources are not healthy", failed)
+			}
+
+			return nil
+		},
+	}
+
+	checkCmd.Flags().DurationVarP(&timeout, "timeout", "", 10*time.Second, "Time to wait for each resource health check to pass")
+
+	return checkCmd
+}
+
+// checkResource ensures the resource was created and still exists, then runs
+// any health checks defined for the resource
+func checkResource(ctx context.Context, r types.Resource, p config.Providers, hc http.HTTP, kc kclients.Kubernetes, timeout time.Duration) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if s := r.Metadata().Properties[constants.PropertyStatus]; s != constants.StatusCreated {
+		return fmt.Errorf("resource status is %v", s)
+	}
+
+	prov := p.GetProvider(r)
+	if prov == nil {
+		return fmt.Errorf("unable to find provider for resource")
+	}
+
+	ids, err := prov.Lookup()
+	if err != nil {
+		return fmt.Errorf("unable to lookup resource: %s", err)
+	}
+
+	// resources that are backed by containers must have running containers
+	switch r.(type) {
+	case *container.Container, *container.Sidecar, *k8s.Cluster, *nomad.NomadCluster:
+		if len(ids) == 0 {
+			return fmt.Errorf("unable to find containers for resource")
+		}
+	}
+
+	return checkHealth(ctx, r, hc, kc, timeout)
 }
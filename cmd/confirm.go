@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/system"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// isInteractive returns true when the user can be prompted for input
+var isInteractive = func(cmd *cobra.Command) bool {
+	if ni, _ := cmd.Flags().GetBool("non-interactive"); ni {
+		return false
+	}
+
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
+
+// confirmDestroy lists the resources that will be destroyed and asks the user
+// to confirm before continuing, an error is returned when the user does not
+// confirm or when the session is not interactive and autoApprove is not set
+func confirmDestroy(cmd *cobra.Command, s system.System, message string, res []types.Resource, autoApprove bool) error {
+	if autoApprove || len(res) == 0 {
+		return nil
+	}
+
+	if !isInteractive(cmd) {
+		return fmt.Errorf("%d resources will be destroyed and the session is not interactive, use --auto-approve to continue without confirmation", len(res))
+	}
+
+	ids := []string{}
+	for _, r := range res {
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	sort.Strings(ids)
+
+	fmt.Println(whiteText.Render(message))
+	fmt.Println()
+
+	for _, id := range ids {
+		fmt.Printf("  %s%s\n", redIcon.Render("-"), id)
+	}
+
+	fmt.Println()
+
+	answer := s.PromptInput(os.Stdin, os.Stdout, "Do you want to continue? [y/N]: ")
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+
+	return fmt.Errorf("cancelled, no resources have been destroyed")
+}
+
+// recreatedResources returns the changed resources that up will destroy and
+// create again, changed resources that were created successfully are only
+// refreshed, failed and tainted resources are always recreated
+func recreatedResources(state *hclconfig.Config, changed []types.Resource) []types.Resource {
+	recreated := []types.Resource{}
+	if state == nil {
+		return recreated
+	}
+
+	for _, r := range changed {
+		sr, err := state.FindResource(r.Metadata().ID)
+		if err != nil {
+			continue
+		}
+
+		switch sr.Metadata().Properties[constants.PropertyStatus] {
+		case constants.StatusFailed, constants.StatusTainted:
+			recreated = append(recreated, r)
+		}
+	}
+
+	return recreated
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	systemmock "github.com/jumppad-labs/jumppad/pkg/clients/system/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupConfirm(t *testing.T, interactive bool, answer string) (*systemmock.System, []types.Resource) {
+	old := isInteractive
+	isInteractive = func(cmd *cobra.Command) bool { return interactive }
+	t.Cleanup(func() { isInteractive = old })
+
+	ms := &systemmock.System{}
+	ms.On("PromptInput", mock.Anything, mock.Anything, mock.Anything).Return(answer)
+
+	res := []types.Resource{
+		&container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.consul"}}},
+	}
+
+	return ms, res
+}
+
+func TestConfirmDestroyReturnsNilWhenUserConfirms(t *testing.T) {
+	ms, res := setupConfirm(t, true, "y")
+
+	err := confirmDestroy(&cobra.Command{}, ms, "destroying", res, false)
+	require.NoError(t, err)
+	ms.AssertCalled(t, "PromptInput", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmDestroyReturnsErrorWhenUserDeclines(t *testing.T) {
+	ms, res := setupConfirm(t, true, "")
+
+	err := confirmDestroy(&cobra.Command{}, ms, "destroying", res, false)
+	require.Error(t, err)
+}
+
+func TestConfirmDestroyDoesNotPromptWhenAutoApprove(t *testing.T) {
+	ms, res := setupConfirm(t, true, "")
+
+	err := confirmDestroy(&cobra.Command{}, ms, "destroying", res, true)
+	require.NoError(t, err)
+	ms.AssertNotCalled(t, "PromptInput", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfirmDestroyReturnsErrorWhenNotInteractive(t *testing.T) {
+	ms, res := setupConfirm(t, false, "y")
+
+	err := confirmDestroy(&cobra.Command{}, ms, "destroying", res, false)
+	require.ErrorContains(t, err, "--auto-approve")
+	ms.AssertNotCalled(t, "PromptInput", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecreatedResourcesReturnsFailedAndTaintedResources(t *testing.T) {
+	newResource := func(name, status string) types.Resource {
+		return &container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{
+			ID:         "resource.container." + name,
+			Name:       name,
+			Type:       container.TypeContainer,
+			Properties: map[string]interface{}{constants.PropertyStatus: status},
+		}}}
+	}
+
+	state := hclconfig.NewConfig()
+	state.Resources = []types.Resource{
+		newResource("created", constants.StatusCreated),
+		newResource("failed", constants.StatusFailed),
+		newResource("tainted", constants.StatusTainted),
+	}
+
+	changed := []types.Resource{
+		newResource("created", ""),
+		newResource("failed", ""),
+		newResource("tainted", ""),
+		newResource("new", ""),
+	}
+
+	recreated := recreatedResources(state, changed)
+
+	ids := []string{}
+	for _, r := range recreated {
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	require.Equal(t, []string{"resource.container.failed", "resource.container.tainted"}, ids)
+}
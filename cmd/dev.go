@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/jumppad-labs/jumppad/cmd/view"
@@ -85,13 +84,7 @@ func newDevCmdFunc(variables *[]string, variablesFile, interval *string, ttyFlag
 		}
 
 		// parse the vars into a map
-		vars := map[string]string{}
-		for _, v := range *variables {
-			parts := strings.Split(v, "=")
-			if len(parts) == 2 {
-				vars[parts[0]] = parts[1]
-			}
-		}
+		vars := parseVariables(*variables)
 
 		if variablesFile != nil && *variablesFile != "" {
 			if _, err := os.Stat(*variablesFile); err != nil {
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/spf13/cobra"
+)
+
+func newDocsCmd(e jumppad.Engine) *cobra.Command {
+	docsCmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Work with the documentation defined in the configuration",
+		Long:  "Work with the documentation defined in the configuration",
+	}
+
+	docsCmd.AddCommand(newDocsLintCmd(e))
+
+	return docsCmd
+}
+
+func newDocsLintCmd(e jumppad.Engine) *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint [file] | [directory]",
+		Short: "Check the content of documentation pages for errors",
+		Long: `Check the content of the pages in all chapters for errors that would
+stop the MDX from compiling, such as unclosed JSX tags or unbalanced
+expressions, without starting the documentation site`,
+		Example: `
+  jumppad docs lint ./
+`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dst := "./"
+			if len(args) == 1 && args[0] != "." {
+				dst = args[0]
+			}
+
+			_, err := e.ParseConfig(dst)
+			if err != nil {
+				return err
+			}
+
+			chapters, _ := e.Config().FindResourcesByType(docs.TypeChapter)
+			sort.Slice(chapters, func(i, j int) bool {
+				return chapters[i].Metadata().ID < chapters[j].Metadata().ID
+			})
+
+			problems := 0
+			for _, r := range chapters {
+				c := r.(*docs.Chapter)
+
+				for _, p := range c.Pages {
+					for _, le := range docs.LintPage(p.Content) {
+						problems++
+						fmt.Printf("%s %s/%s:%d: %s\n", redIcon.Render("✘"), c.Meta.ID, p.Name, le.Line, le.Message)
+					}
+				}
+			}
+
+			if problems > 0 {
+				return fmt.Errorf("found %d problems in %d chapters", problems, len(chapters))
+			}
+
+			fmt.Printf("%s %d chapters checked, no problems found\n", greenIcon.Render("✔"), len(chapters))
+
+			return nil
+		},
+	}
+}
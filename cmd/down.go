@@ -9,30 +9,45 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
 func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 	var force bool
+	var parallelism int
+	var autoApprove bool
 
 	downCmd := &cobra.Command{
-		Use:     "down",
-		Short:   "Remove all resources in the current state",
-		Long:    "Remove all resources in the current state",
-		Example: `jumppad down`,
-		Run: func(cmd *cobra.Command, args []string) {
+		Use:          "down",
+		Short:        "Remove all resources in the current state",
+		Long:         "Remove all resources in the current state",
+		Example:      `jumppad down`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			engineClients, _ := clients.GenerateClients(l)
 			engineClients.ContainerTasks.SetForce(force)
 
 			engine, err := createEngine(l, engineClients)
 			if err != nil {
 				l.Error("Unable to create engine", "error", err)
-				return
+				return nil
 			}
 
 			logger := createLogger()
 
+			// list the resources that will be destroyed and confirm
+			state, err := config.LoadState()
+			if config.IsStateRecovered(err) {
+				return err
+			}
+
+			err = confirmDestroy(cmd, engineClients.System, "The following resources will be destroyed", state.Resources, autoApprove)
+			if err != nil {
+				return err
+			}
+
 			done := make(chan os.Signal, 1)
 			signal.Notify(done, syscall.SIGINT, syscall.SIGTERM)
 
@@ -51,10 +66,12 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 				cancel()
 			}()
 
+			engine.SetParallelism(parallelism)
+
 			err = engine.Destroy(ctx, force)
 			if err != nil {
 				l.Error("Unable to destroy stack", "error", err)
-				return
+				return nil
 			}
 
 			// clean up the data folders
@@ -69,10 +86,14 @@ func newDestroyCmd(cc connector.Connector, l logger.Logger) *cobra.Command {
 					logger.Error("Unable to destroy jumppad daemon", "error", err)
 				}
 			}
+
+			return nil
 		},
 	}
 
 	downCmd.Flags().BoolVarP(&force, "force", "", false, "When set to true Jumppad will not wait for containers to exit gracefully and will ignore errors")
+	downCmd.Flags().IntVarP(&parallelism, "parallelism", "", 0, "Maximum number of resources to destroy concurrently, 0 is unlimited")
+	downCmd.Flags().BoolVarP(&autoApprove, "auto-approve", "y", false, "When set to true Jumppad will not ask for confirmation before destroying resources")
 
 	return downCmd
 }
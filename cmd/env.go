@@ -4,42 +4,71 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"regexp"
 	"runtime"
 	"strings"
 
 	"github.com/jumppad-labs/hclconfig/resources"
 	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
 	"github.com/spf13/cobra"
 )
 
+// envVar is an environment variable that will be printed by the env command
+type envVar struct {
+	name  string
+	value string
+}
+
+var invalidEnvChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
 func newEnvCmd() *cobra.Command {
 	var unset bool
+	var shell string
 
 	envCmd := &cobra.Command{
 		Use:   "env",
 		Short: "Prints environment variables defined by the blueprint",
-		Long:  "Prints environment variables defined by the blueprint",
+		Long: `Prints environment variables defined by the blueprint
+
+Environment variables are created for all outputs defined in the root
+blueprint, KUBECONFIG is set to the config files for any Kubernetes clusters
+and INGRESS_<NAME>_ADDRESS is set to the local address of any ingress.`,
 		Example: `
   # Display environment variables
   jumppad env
-  
-  VAR1=value
-  VAR2=value
-  
+
+  export VAR1='value'
+  export VAR2='value'
+
   # Set environment variables on Linux based systems
-  eval $(jumppad env)
-    
+  eval "$(jumppad env)"
+
+  # Set environment variables for the fish shell
+  jumppad env --shell fish | source
+
   # Set environment variables on Windows based systems
   Invoke-Expression "jumppad env" | ForEach-Object { Invoke-Expression $_ }
 
   # Unset environment variables on Linux based systems
-  eval $(jumppad env --unset)
+  eval "$(jumppad env --unset)"
 
   # Unset environment variables on Windows based systems
   Invoke-Expression "jumppad env --unset" | ForEach-Object { Remove-Item $_ }
 `,
 		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if shell == "" {
+				shell = "bash"
+				if runtime.GOOS == "windows" {
+					shell = "powershell"
+				}
+			}
+
+			if shell != "bash" && shell != "fish" && shell != "powershell" {
+				return fmt.Errorf("invalid shell %s, must be one of bash, fish, powershell", shell)
+			}
 
 			// load the stack
 			c, err := config.LoadState()
@@ -48,49 +77,93 @@ func newEnvCmd() *cobra.Command {
 				os.Exit(1)
 			}
 
-			prefix := "export "
-			if unset {
-				prefix = "unset "
-			}
-			if runtime.GOOS == "windows" {
-				prefix = "$Env:"
-				if unset {
-					prefix = "Env:\\"
-				}
-			}
+			vars := []envVar{}
+			outputs := map[string]bool{}
+			kubeConfigs := []string{}
 
-			// add output variables
 			for _, r := range c.Resources {
-				if r.Metadata().Type == resources.TypeOutput {
-					if r.GetDisabled() {
-						continue
-					}
+				if r.GetDisabled() {
+					continue
+				}
 
+				switch v := r.(type) {
+				case *resources.Output:
 					if r.Metadata().Module != "" {
 						continue
 					}
 
-					d, _ := json.Marshal(r.(*resources.Output).Value)
+					// strings are printed as is, any other type is printed as json
+					val, ok := v.Value.(string)
+					if !ok {
+						d, _ := json.Marshal(v.Value)
+						val = string(d)
+					}
+
+					outputs[envName(r.Metadata().Name)] = true
+					vars = append(vars, envVar{envName(r.Metadata().Name), val})
 
-					// trim any strings that wrap the output from marshaling
-					val := strings.TrimPrefix(string(d), "\"")
-					val = strings.TrimSuffix(val, "\"")
+				case *k8s.Cluster:
+					if v.KubeConfig.ConfigPath != "" {
+						kubeConfigs = append(kubeConfigs, v.KubeConfig.ConfigPath)
+					}
 
-					// escape any json
-					val = strings.ReplaceAll(val, `\`, `\\`)
-					val = strings.ReplaceAll(val, `"`, `\"`)
-					if unset {
-						fmt.Printf("%s%s\n", prefix, r.Metadata().Name)
-					} else {
-						fmt.Printf("%s%s=\"%s\"\n", prefix, r.Metadata().Name, val)
+				case *ingress.Ingress:
+					if v.LocalAddress != "" {
+						vars = append(vars, envVar{strings.ToUpper(envName(fmt.Sprintf("INGRESS_%s_ADDRESS", v.Meta.Name))), v.LocalAddress})
 					}
 				}
 			}
+
+			// explicitly defined outputs take precedence over the generated KUBECONFIG
+			if len(kubeConfigs) > 0 && !outputs["KUBECONFIG"] {
+				vars = append(vars, envVar{"KUBECONFIG", strings.Join(kubeConfigs, string(os.PathListSeparator))})
+			}
+
+			for _, v := range vars {
+				fmt.Println(formatEnv(shell, v, unset))
+			}
+
 			return nil
 		},
 		SilenceUsage: true,
 	}
 
 	envCmd.Flags().BoolVarP(&unset, "unset", "", false, "When set to true jumppad will print unset commands for environment variables defined by the blueprint")
+	envCmd.Flags().StringVarP(&shell, "shell", "", "", "Shell syntax to use for the output, one of bash, fish, powershell. Defaults to powershell on Windows and bash on all other systems")
 	return envCmd
 }
+
+// envName converts the given name into a valid environment variable name
+func envName(name string) string {
+	return invalidEnvChars.ReplaceAllString(name, "_")
+}
+
+// formatEnv returns the shell specific command to set or unset the given
+// environment variable, values are single quoted so that they are not
+// interpreted by the shell
+func formatEnv(shell string, v envVar, unset bool) string {
+	switch shell {
+	case "fish":
+		if unset {
+			return fmt.Sprintf("set -e %s", v.name)
+		}
+
+		val := strings.ReplaceAll(v.value, `\`, `\\`)
+		val = strings.ReplaceAll(val, `'`, `\'`)
+		return fmt.Sprintf("set -gx %s '%s'", v.name, val)
+
+	case "powershell":
+		if unset {
+			return fmt.Sprintf("Env:\\%s", v.name)
+		}
+
+		return fmt.Sprintf("$Env:%s = '%s'", v.name, strings.ReplaceAll(v.value, `'`, `''`))
+
+	default:
+		if unset {
+			return fmt.Sprintf("unset %s", v.name)
+		}
+
+		return fmt.Sprintf("export %s='%s'", v.name, strings.ReplaceAll(v.value, `'`, `'\''`))
+	}
+}
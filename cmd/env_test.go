@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatEnvQuotesValuesForShell(t *testing.T) {
+	tt := []struct {
+		name     string
+		shell    string
+		value    string
+		unset    bool
+		expected string
+	}{
+		{"bash simple", "bash", "value", false, `export FOO='value'`},
+		{"bash spaces", "bash", "a value with spaces", false, `export FOO='a value with spaces'`},
+		{"bash dollar", "bash", "$HOME/bin", false, `export FOO='$HOME/bin'`},
+		{"bash quotes", "bash", `it's "quoted"`, false, `export FOO='it'\''s "quoted"'`},
+		{"bash unset", "bash", "value", true, `unset FOO`},
+		{"fish simple", "fish", "value", false, `set -gx FOO 'value'`},
+		{"fish spaces", "fish", "a value with spaces", false, `set -gx FOO 'a value with spaces'`},
+		{"fish dollar", "fish", "$HOME/bin", false, `set -gx FOO '$HOME/bin'`},
+		{"fish quotes", "fish", `it's "quoted" \n`, false, `set -gx FOO 'it\'s "quoted" \\n'`},
+		{"fish unset", "fish", "value", true, `set -e FOO`},
+		{"powershell simple", "powershell", "value", false, `$Env:FOO = 'value'`},
+		{"powershell spaces", "powershell", "a value with spaces", false, `$Env:FOO = 'a value with spaces'`},
+		{"powershell dollar", "powershell", "$HOME/bin", false, `$Env:FOO = '$HOME/bin'`},
+		{"powershell quotes", "powershell", `it's "quoted"`, false, `$Env:FOO = 'it''s "quoted"'`},
+		{"powershell unset", "powershell", "value", true, `Env:\FOO`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			out := formatEnv(tc.shell, envVar{"FOO", tc.value}, tc.unset)
+			require.Equal(t, tc.expected, out)
+		})
+	}
+}
+
+func TestEnvNameReplacesInvalidCharacters(t *testing.T) {
+	require.Equal(t, "my_output_1", envName("my-output.1"))
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/http"
+	kclients "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/helm"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+)
+
+// checkHealth runs the health checks defined for the given resource. HTTP and
+// TCP checks are run for containers and sidecars, ingress are checked to ensure
+// the local address is reachable, and pod and condition checks are run for
+// Kubernetes resources when a Kubernetes client is provided
+func checkHealth(ctx context.Context, r types.Resource, hc http.HTTP, kc kclients.Kubernetes, timeout time.Duration) error {
+	var check *healthcheck.HealthCheckContainer
+	var kcheck *healthcheck.HealthCheckKubernetes
+	kubeConfig := ""
+	tcp := []string{}
+
+	switch v := r.(type) {
+	case *container.Container:
+		check = v.HealthCheck
+	case *container.Sidecar:
+		check = v.HealthCheck
+	case *ingress.Ingress:
+		// ingress with the local direction listen inside the cluster, there
+		// is no listener on the local address to check
+		if v.LocalAddress != "" && v.Direction != ingress.DirectionLocal {
+			tcp = append(tcp, v.LocalAddress)
+		}
+	case *k8s.Config:
+		kcheck = v.HealthCheck
+		kubeConfig = v.Cluster.KubeConfig.ConfigPath
+	case *helm.Helm:
+		kcheck = v.HealthCheck
+		kubeConfig = v.Cluster.KubeConfig.ConfigPath
+	}
+
+	if check != nil {
+		for _, t := range check.TCP {
+			tcp = append(tcp, t.Address)
+		}
+	}
+
+	for _, addr := range tcp {
+		if err := hc.HealthCheckTCP(addr, timeout); err != nil {
+			return err
+		}
+	}
+
+	if check != nil {
+		for _, h := range check.HTTP {
+			err := hc.HealthCheckHTTP(h.Address, h.Method, h.Headers, h.Body, h.SuccessCodes, timeout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if kcheck == nil || kc == nil {
+		return nil
+	}
+
+	kc, err := kc.SetConfig(kubeConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	conditions := []kclients.Condition{}
+	for _, c := range kcheck.Conditions {
+		conditions = append(conditions, kclients.Condition(c))
+	}
+
+	return kclients.HealthCheck(ctx, kc, kcheck.Pods, conditions, timeout)
+}
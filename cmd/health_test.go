@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	hcltypes "github.com/jumppad-labs/hclconfig/types"
+	httpmock "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupHealthIngress(direction string) *ingress.Ingress {
+	return &ingress.Ingress{
+		ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{ID: "resource.ingress.web", Name: "web", Type: ingress.TypeIngress}},
+		Port:         8080,
+		Direction:    direction,
+		LocalAddress: "172.17.0.1:8080",
+	}
+}
+
+func TestCheckHealthDialsIngressLocalAddress(t *testing.T) {
+	hc := &httpmock.HTTP{}
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	err := checkHealth(context.Background(), setupHealthIngress(ingress.DirectionRemote), hc, nil, time.Second)
+	require.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckTCP", "172.17.0.1:8080", time.Second)
+}
+
+func TestCheckHealthSkipsLocalIngress(t *testing.T) {
+	hc := &httpmock.HTTP{}
+
+	err := checkHealth(context.Background(), setupHealthIngress(ingress.DirectionLocal), hc, nil, time.Second)
+	require.NoError(t, err)
+
+	hc.AssertNotCalled(t, "HealthCheckTCP", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jumppad-labs/hclconfig/resources"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	ct "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd(dc container.Docker, l logger.Logger) *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import [resource] [container id or name]",
+		Short: "Import an existing Docker container into the state",
+		Long: `Import an existing Docker container into the state so that it is
+managed by jumppad, imported containers are tracked by 'jumppad status' and
+removed by 'jumppad down'.
+
+To keep the container when running 'jumppad up', add a container resource
+with the same name to the blueprint.`,
+		Example: `
+  # Import the container named consul as resource.container.consul
+  jumppad import container.consul consul
+`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			if !strings.HasPrefix(id, "resource.") && !strings.HasPrefix(id, "module.") {
+				id = "resource." + id
+			}
+
+			fqrn, err := resources.ParseFQRN(id)
+			if err != nil {
+				return fmt.Errorf("invalid resource %s: %s", args[0], err)
+			}
+
+			if fqrn.Type != ct.TypeContainer {
+				return fmt.Errorf("unable to import %s, only resources of type %s can be imported", args[0], ct.TypeContainer)
+			}
+
+			// the state may not exist if this is the first resource
+			cfg, err := config.LoadState()
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("unable to load state: %s", err)
+			}
+
+			if r, err := cfg.FindResource(fqrn.String()); err == nil && r != nil {
+				return fmt.Errorf("resource %s already exists in the state", fqrn.String())
+			}
+
+			info, err := dc.ContainerInspect(context.Background(), args[1])
+			if err != nil {
+				return fmt.Errorf("unable to find container %s: %s", args[1], err)
+			}
+
+			c := &ct.Container{
+				ResourceBase: types.ResourceBase{
+					Meta: types.Meta{
+						ID:     fqrn.String(),
+						Name:   fqrn.Resource,
+						Type:   ct.TypeContainer,
+						Module: fqrn.Module,
+						Properties: map[string]interface{}{
+							constants.PropertyStatus: constants.StatusCreated,
+						},
+					},
+				},
+				ContainerName: strings.TrimPrefix(info.Name, "/"),
+				Environment:   map[string]string{},
+			}
+
+			if info.Config != nil {
+				c.Image = ct.Image{Name: info.Config.Image, ID: info.Image}
+				c.Entrypoint = info.Config.Entrypoint
+				c.Command = info.Config.Cmd
+
+				for _, e := range info.Config.Env {
+					parts := strings.SplitN(e, "=", 2)
+					if len(parts) == 2 {
+						c.Environment[parts[0]] = parts[1]
+					}
+				}
+			}
+
+			err = cfg.AppendResource(c)
+			if err != nil {
+				return fmt.Errorf("unable to add resource to state: %s", err)
+			}
+
+			err = config.SaveState(cfg)
+			if err != nil {
+				return fmt.Errorf("unable to save state: %s", err)
+			}
+
+			l.Info("Imported container", "ref", c.Meta.ID, "container", c.ContainerName)
+
+			return nil
+		},
+	}
+
+	return importCmd
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	ct "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad/constants"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupImport(t *testing.T, state string) (*cobra.Command, *mocks.Docker) {
+	testutils.SetupState(t, state)
+
+	dc := &mocks.Docker{}
+	dc.On("ContainerInspect", mock.Anything, "consul").Return(
+		dcontainer.InspectResponse{
+			ContainerJSONBase: &dcontainer.ContainerJSONBase{
+				Name:  "/consul",
+				Image: "sha256:abc",
+			},
+			Config: &dcontainer.Config{
+				Image: "hashicorp/consul:1.16.2",
+				Cmd:   []string{"agent", "-dev"},
+				Env:   []string{"CONSUL_HTTP_ADDR=localhost:8500", "EMPTY="},
+			},
+		},
+		nil,
+	)
+	dc.On("ContainerInspect", mock.Anything, mock.Anything).Return(dcontainer.InspectResponse{}, fmt.Errorf("not found"))
+
+	c := newImportCmd(dc, logger.NewTestLogger(t))
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetErr(bytes.NewBuffer(nil))
+
+	return c, dc
+}
+
+func TestImportAddsContainerToState(t *testing.T) {
+	c, _ := setupImport(t, "")
+	c.SetArgs([]string{"container.consul", "consul"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	cfg, err := config.LoadState()
+	require.NoError(t, err)
+
+	r, err := cfg.FindResource("resource.container.consul")
+	require.NoError(t, err)
+
+	cont := r.(*ct.Container)
+	require.Equal(t, "consul", cont.ContainerName)
+	require.Equal(t, "hashicorp/consul:1.16.2", cont.Image.Name)
+	require.Equal(t, []string{"agent", "-dev"}, cont.Command)
+	require.Equal(t, "localhost:8500", cont.Environment["CONSUL_HTTP_ADDR"])
+	require.Equal(t, "", cont.Environment["EMPTY"])
+	require.Equal(t, constants.StatusCreated, cont.Metadata().Properties[constants.PropertyStatus])
+}
+
+func TestImportReturnsErrorForNonContainerResource(t *testing.T) {
+	c, dc := setupImport(t, "")
+	c.SetArgs([]string{"resource.network.cloud", "consul"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "only resources of type container")
+
+	dc.AssertNotCalled(t, "ContainerInspect", mock.Anything, mock.Anything)
+}
+
+func TestImportReturnsErrorWhenResourceExists(t *testing.T) {
+	c, dc := setupImport(t, pauseState)
+	c.SetArgs([]string{"container.nginx", "consul"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "already exists")
+
+	dc.AssertNotCalled(t, "ContainerInspect", mock.Anything, mock.Anything)
+}
+
+func TestImportReturnsErrorWhenStateCanNotBeLoaded(t *testing.T) {
+	c, dc := setupImport(t, "not json")
+	c.SetArgs([]string{"container.consul", "consul"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "unable to load state")
+
+	dc.AssertNotCalled(t, "ContainerInspect", mock.Anything, mock.Anything)
+}
+
+func TestImportReturnsErrorWhenContainerNotFound(t *testing.T) {
+	c, _ := setupImport(t, "")
+	c.SetArgs([]string{"container.consul", "missing"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "unable to find container missing")
+}
@@ -76,15 +76,32 @@ func newLogCmdFunc(dc container.Docker, stdout, stderr io.Writer) func(cmd *cobr
 
 		var loggable []string
 
+		// when running in a terminal without a resource allow the user to
+		// pick the resource to tail
+		resource := ""
 		if len(args) == 1 {
+			resource = args[0]
+		} else if canPick() {
+			items, err := getLoggableResources()
+			if err != nil {
+				return err
+			}
+
+			resource, err = pickItem("Select a resource to tail logs for", append([]string{allResources}, items...))
+			if err != nil {
+				return err
+			}
+		}
+
+		if resource != "" && resource != allResources {
 			cfg, err := config.LoadState()
 			if err != nil {
 				return errors.New("unable to read state file")
 			}
 
-			r, err := cfg.FindResource(args[0])
+			r, err := cfg.FindResource(resource)
 			if err != nil {
-				return fmt.Errorf("%s not found: %s", args[0], err)
+				return fmt.Errorf("%s not found: %s", resource, err)
 			}
 
 			loggable = getFQDNForResource(r)
@@ -154,11 +171,40 @@ func getLoggable() ([]string, error) {
 	return loggable, nil
 }
 
+// allResources is the picker option to tail the logs for all resources
+const allResources = "all resources"
+
+// getLoggableResources returns the ids of all resources that have logs
+func getLoggableResources() ([]string, error) {
+	cfg, err := config.LoadState()
+	if err != nil {
+		return nil, errors.New("unable to read state file")
+	}
+
+	ids := []string{}
+	for _, r := range cfg.Resources {
+		if r.GetDisabled() || len(getFQDNForResource(r)) == 0 {
+			continue
+		}
+
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	return ids, nil
+}
+
 func getFQDNForResource(r hcltypes.Resource) []string {
 	fqdns := []string{}
 
 	switch r.Metadata().Type {
 	case ct.TypeContainer:
+		// the container name differs from the FQDN when DNS is disabled or
+		// the container was imported, use the name stored in the state
+		if c, ok := r.(*ct.Container); ok && c.ContainerName != "" {
+			fqdns = append(fqdns, c.ContainerName)
+			break
+		}
+
 		fqdns = append(fqdns, utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type))
 	case k8s.TypeK8sCluster:
 		fqdns = append(fqdns, fmt.Sprintf("%s.%s", "server", utils.FQDN(r.Metadata().Name, r.Metadata().Module, r.Metadata().Type)))
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/hokaccha/go-prettyjson"
@@ -12,12 +13,27 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var outputFormat string
+
 var outputCmd = &cobra.Command{
 	Use:   "output",
 	Short: "Show the output variables",
 	Long:  `Show the output variables`,
-	Args:  cobra.MaximumNArgs(1),
+	Example: `
+  # Show all outputs as JSON
+  jumppad output
+
+  # Show all outputs as a flat JSON object of strings that can be used
+  # with the Terraform external data source
+  jumppad output --format terraform
+`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if outputFormat != "json" && outputFormat != "terraform" {
+			cmd.Println("Error: Invalid format, must be one of json, terraform")
+			os.Exit(1)
+		}
+
 		// load the stack
 		cfg, err := config.LoadState()
 		if err != nil {
@@ -48,7 +64,50 @@ var outputCmd = &cobra.Command{
 			}
 		}
 
+		if outputFormat == "terraform" {
+			flat := map[string]string{}
+			flattenOutput("", out, flat)
+
+			d, _ := json.Marshal(flat)
+			fmt.Printf("%s", string(d))
+			return
+		}
+
 		d, _ := prettyjson.Marshal(out)
 		fmt.Printf("%s", string(d))
 	},
 }
+
+func init() {
+	outputCmd.Flags().StringVarP(&outputFormat, "format", "", "json", "Output format, one of json, terraform. The terraform format prints a flat JSON object of string values, nested values are flattened using dotted keys")
+}
+
+// flattenOutput flattens the given value into a map of strings, nested maps and
+// lists are added using dotted keys e.g. kube_config.path or ports.0
+func flattenOutput(prefix string, v interface{}, out map[string]string) {
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+
+		return prefix + "." + k
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, mv := range val {
+			flattenOutput(key(k), mv, out)
+		}
+	case []interface{}:
+		for i, lv := range val {
+			flattenOutput(key(strconv.Itoa(i)), lv, out)
+		}
+	case string:
+		out[prefix] = val
+	case nil:
+		out[prefix] = ""
+	default:
+		d, _ := json.Marshal(val)
+		out[prefix] = string(d)
+	}
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenOutputFlattensNestedValues(t *testing.T) {
+	out := map[string]interface{}{
+		"name": "consul",
+		"kube_config": map[string]interface{}{
+			"path": "/home/.jumppad/config/kubeconfig.yaml",
+			"port": float64(443),
+		},
+		"ports": []interface{}{
+			float64(8500),
+			map[string]interface{}{"local": "8501", "tls": true},
+		},
+		"empty": nil,
+	}
+
+	flat := map[string]string{}
+	flattenOutput("", out, flat)
+
+	require.Equal(t, map[string]string{
+		"name":             "consul",
+		"kube_config.path": "/home/.jumppad/config/kubeconfig.yaml",
+		"kube_config.port": "443",
+		"ports.0":          "8500",
+		"ports.1.local":    "8501",
+		"ports.1.tls":      "true",
+		"empty":            "",
+	}, flat)
+}
+
+func TestFlattenOutputProducesTerraformCompatibleJSON(t *testing.T) {
+	// the Terraform external data source requires a flat object of strings
+	out := map[string]interface{}{
+		"addresses": []interface{}{"10.0.0.1", "10.0.0.2"},
+	}
+
+	flat := map[string]string{}
+	flattenOutput("", out, flat)
+
+	d, err := json.Marshal(flat)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"addresses.0": "10.0.0.1", "addresses.1": "10.0.0.2"}`, string(d))
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dcontainer "github.com/docker/docker/api/types/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newPauseCmd(dc container.Docker, dt container.ContainerTasks, l logger.Logger) *cobra.Command {
+	pauseCmd := &cobra.Command{
+		Use:   "pause [resource]",
+		Short: "Stop the containers for a resource without destroying it",
+		Long: `Stop the containers for a resource without destroying it,
+the containers and the state are kept so that the resource can be
+restarted using 'jumppad resume'`,
+		Example: `
+  # Stop the container named nginx
+  jumppad pause resource.container.nginx
+
+  # Stop all the nodes for a Nomad cluster
+  jumppad pause resource.nomad_cluster.dev
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: getResources,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := getContainerIDsForResource(dt, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				l.Debug("Stopping container", "ref", args[0], "id", id)
+
+				err := dc.ContainerStop(context.Background(), id, dcontainer.StopOptions{})
+				if err != nil {
+					return fmt.Errorf("unable to stop container %s: %s", id, err)
+				}
+			}
+
+			l.Info("Paused resource", "ref", args[0])
+
+			return nil
+		},
+	}
+
+	return pauseCmd
+}
+
+func newResumeCmd(dc container.Docker, dt container.ContainerTasks, l logger.Logger) *cobra.Command {
+	resumeCmd := &cobra.Command{
+		Use:   "resume [resource]",
+		Short: "Start the containers for a resource that has been paused",
+		Long:  `Start the containers for a resource that has been stopped with 'jumppad pause'`,
+		Example: `
+  # Start the container named nginx
+  jumppad resume resource.container.nginx
+`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: getResources,
+		SilenceUsage:      true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := getContainerIDsForResource(dt, args[0])
+			if err != nil {
+				return err
+			}
+
+			for _, id := range ids {
+				l.Debug("Starting container", "ref", args[0], "id", id)
+
+				err := dc.ContainerStart(context.Background(), id, dcontainer.StartOptions{})
+				if err != nil {
+					return fmt.Errorf("unable to start container %s: %s", id, err)
+				}
+			}
+
+			l.Info("Resumed resource", "ref", args[0])
+
+			return nil
+		},
+	}
+
+	return resumeCmd
+}
+
+// getContainerIDsForResource returns the ids of all the containers that
+// belong to the resource with the given id in the state
+func getContainerIDsForResource(dt container.ContainerTasks, resource string) ([]string, error) {
+	cfg, err := config.LoadState()
+	if err != nil {
+		return nil, errors.New("unable to read state file")
+	}
+
+	r, err := cfg.FindResource(resource)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found: %s", resource, err)
+	}
+
+	ids := []string{}
+	for _, fqdn := range getFQDNForResource(r) {
+		id, err := dt.FindContainerIDs(fqdn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find containers for %s: %s", resource, err)
+		}
+
+		ids = append(ids, id...)
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no containers found for %s", resource)
+	}
+
+	return ids, nil
+}
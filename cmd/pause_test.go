@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var pauseState = `
+{
+  "blueprint": null,
+  "resources": [
+    {
+      "meta": {
+        "id": "resource.container.nginx",
+        "name": "nginx",
+        "type": "container"
+      }
+    }
+  ]
+}`
+
+var pauseContainerNameState = `
+{
+  "blueprint": null,
+  "resources": [
+    {
+      "meta": {
+        "id": "resource.container.nginx",
+        "name": "nginx",
+        "type": "container"
+      },
+      "container_name": "nginx-container-local-jmpd-in"
+    }
+  ]
+}`
+
+func setupPause(t *testing.T, newCmd func(*mocks.Docker, *mocks.ContainerTasks) *cobra.Command) (*cobra.Command, *mocks.Docker, *mocks.ContainerTasks) {
+	testutils.SetupState(t, pauseState)
+
+	dc := &mocks.Docker{}
+	dc.On("ContainerStop", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	dc.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	dt := &mocks.ContainerTasks{}
+	dt.On("FindContainerIDs", mock.Anything).Return([]string{"abc123"}, nil)
+
+	c := newCmd(dc, dt)
+	c.SetOut(bytes.NewBuffer(nil))
+	c.SetErr(bytes.NewBuffer(nil))
+
+	return c, dc, dt
+}
+
+func pauseCmd(t *testing.T) func(*mocks.Docker, *mocks.ContainerTasks) *cobra.Command {
+	return func(dc *mocks.Docker, dt *mocks.ContainerTasks) *cobra.Command {
+		return newPauseCmd(dc, dt, logger.NewTestLogger(t))
+	}
+}
+
+func resumeCmd(t *testing.T) func(*mocks.Docker, *mocks.ContainerTasks) *cobra.Command {
+	return func(dc *mocks.Docker, dt *mocks.ContainerTasks) *cobra.Command {
+		return newResumeCmd(dc, dt, logger.NewTestLogger(t))
+	}
+}
+
+func TestPauseStopsContainersForResource(t *testing.T) {
+	c, dc, dt := setupPause(t, pauseCmd(t))
+	c.SetArgs([]string{"resource.container.nginx"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	dt.AssertCalled(t, "FindContainerIDs", "nginx.container.local.jmpd.in")
+	dc.AssertCalled(t, "ContainerStop", mock.Anything, "abc123", mock.Anything)
+}
+
+func TestPauseUsesContainerNameFromState(t *testing.T) {
+	c, dc, dt := setupPause(t, pauseCmd(t))
+	c.SetArgs([]string{"resource.container.nginx"})
+
+	testutils.SetupState(t, pauseContainerNameState)
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	dt.AssertCalled(t, "FindContainerIDs", "nginx-container-local-jmpd-in")
+	dc.AssertCalled(t, "ContainerStop", mock.Anything, "abc123", mock.Anything)
+}
+
+func TestPauseReturnsErrorWhenResourceNotFound(t *testing.T) {
+	c, dc, _ := setupPause(t, pauseCmd(t))
+	c.SetArgs([]string{"resource.container.missing"})
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "not found")
+
+	dc.AssertNotCalled(t, "ContainerStop", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPauseReturnsErrorWhenStopFails(t *testing.T) {
+	c, dc, _ := setupPause(t, pauseCmd(t))
+	c.SetArgs([]string{"resource.container.nginx"})
+
+	testutils.RemoveOn(&dc.Mock, "ContainerStop")
+	dc.On("ContainerStop", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "unable to stop container abc123")
+}
+
+func TestPauseReturnsErrorWhenNoContainers(t *testing.T) {
+	c, dc, dt := setupPause(t, pauseCmd(t))
+	c.SetArgs([]string{"resource.container.nginx"})
+
+	testutils.RemoveOn(&dt.Mock, "FindContainerIDs")
+	dt.On("FindContainerIDs", mock.Anything).Return([]string{}, nil)
+
+	err := c.Execute()
+	require.ErrorContains(t, err, "no containers found")
+
+	dc.AssertNotCalled(t, "ContainerStop", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResumeStartsContainersForResource(t *testing.T) {
+	c, dc, _ := setupPause(t, resumeCmd(t))
+	c.SetArgs([]string{"resource.container.nginx"})
+
+	err := c.Execute()
+	require.NoError(t, err)
+
+	dc.AssertCalled(t, "ContainerStart", mock.Anything, "abc123", mock.Anything)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+)
+
+var pickerTitle = lipgloss.NewStyle().Bold(true)
+var pickerSelected = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+
+// pickerModel is a bubbletea model that allows the user to select a single
+// item from a list
+type pickerModel struct {
+	title    string
+	items    []string
+	cursor   int
+	selected string
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case "down", "j":
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+		case "enter":
+			m.selected = m.items[m.cursor]
+			return m, tea.Quit
+		case "ctrl+c", "esc", "q":
+			return m, tea.Quit
+		}
+	}
+
+	return m, nil
+}
+
+func (m pickerModel) View() string {
+	sb := strings.Builder{}
+	sb.WriteString(pickerTitle.Render(m.title) + "\n\n")
+
+	for i, item := range m.items {
+		if i == m.cursor {
+			sb.WriteString(pickerSelected.Render("> "+item) + "\n")
+			continue
+		}
+
+		sb.WriteString("  " + item + "\n")
+	}
+
+	sb.WriteString(grayText.Render("\n[↑/↓] move  [enter] select  [q] quit") + "\n")
+
+	return sb.String()
+}
+
+// canPick returns true when both the input and the output are a terminal and
+// the user can select an item interactively
+func canPick() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
+
+// pickItem displays an interactive list of items and returns the item
+// selected by the user, an error is returned when the user cancels the
+// selection or the input or output is not a terminal
+func pickItem(title string, items []string) (string, error) {
+	if !canPick() {
+		return "", errors.New("unable to select a resource interactively, please specify the resource as an argument")
+	}
+
+	if len(items) == 0 {
+		return "", errors.New("no resources found")
+	}
+
+	m, err := tea.NewProgram(pickerModel{title: title, items: items}).Run()
+	if err != nil {
+		return "", fmt.Errorf("unable to display resource picker: %s", err)
+	}
+
+	selected := m.(pickerModel).selected
+	if selected == "" {
+		return "", errors.New("no resource selected")
+	}
+
+	return selected, nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPickerMovesCursorAndSelectsItem(t *testing.T) {
+	var m tea.Model = pickerModel{items: []string{"one", "two", "three"}}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	require.NotNil(t, cmd)
+	require.Equal(t, "two", m.(pickerModel).selected)
+}
+
+func TestPickerQuitDoesNotSelectItem(t *testing.T) {
+	var m tea.Model = pickerModel{items: []string{"one", "two"}}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	require.NotNil(t, cmd)
+	require.Empty(t, m.(pickerModel).selected)
+}
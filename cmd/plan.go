@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
+	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newPlanCmd(e jumppad.Engine, bp getter.Getter) *cobra.Command {
+	var variables []string
+	var variablesFile string
+
+	planCmd := &cobra.Command{
+		Use:   "plan [file] | [directory]",
+		Short: "Show the changes that will be made to the resources at the given path",
+		Long: `Compare the resources at the given path with the current state and show
+the resources that will be created, updated, or destroyed by jumppad up`,
+		Example: `
+  # Show the changes for the .hcl files in the current folder
+  jumppad plan ./
+
+  # Show the changes for a specific file
+  jumppad plan my-stack/network.hcl
+	`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// parse the vars into a map
+			vars := parseVariables(variables)
+
+			if variablesFile != "" {
+				if _, err := os.Stat(variablesFile); err != nil {
+					return fmt.Errorf("variables file %s, does not exist", variablesFile)
+				}
+			}
+
+			dst := "./"
+			if len(args) == 1 && args[0] != "." {
+				dst = args[0]
+			}
+
+			if !utils.IsLocalFolder(dst) && !utils.IsHCLFile(dst) {
+				// fetch the remote blueprint
+				err := bp.Get(dst, utils.BlueprintLocalFolder(dst))
+				if err != nil {
+					return fmt.Errorf("unable to retrieve blueprint: %s", err)
+				}
+
+				dst = utils.BlueprintLocalFolder(dst)
+			}
+
+			new, changed, removed, _, err := e.Diff(dst, vars, variablesFile)
+			if err != nil {
+				return err
+			}
+
+			printPlan(cmd.OutOrStdout(), new, changed, removed)
+
+			return nil
+		},
+	}
+
+	planCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
+	planCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+
+	return planCmd
+}
+
+// printPlan writes a summary of the resources that will be created, updated
+// and destroyed, changed resources are refreshed in place by up
+func printPlan(w io.Writer, new, changed, removed []types.Resource) {
+	if len(new) == 0 && len(changed) == 0 && len(removed) == 0 {
+		fmt.Fprintln(w, whiteText.Render("No changes, the resources match the current state"))
+		return
+	}
+
+	fmt.Fprintln(w, whiteText.Render("Jumppad will perform the following actions:"))
+	fmt.Fprintln(w)
+
+	for _, id := range planIDs(new) {
+		fmt.Fprintf(w, "  %s%s %s\n", greenIcon.Render("+"), id, grayText.Render("will be created"))
+	}
+
+	for _, id := range planIDs(changed) {
+		fmt.Fprintf(w, "  %s%s %s\n", yellowIcon.Render("~"), id, grayText.Render("will be updated"))
+	}
+
+	for _, id := range planIDs(removed) {
+		fmt.Fprintf(w, "  %s%s %s\n", redIcon.Render("-"), id, grayText.Render("will be destroyed"))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Plan: %d to create, %d to update, %d to destroy\n", len(new), len(changed), len(removed))
+}
+
+func planIDs(res []types.Resource) []string {
+	ids := []string{}
+	for _, r := range res {
+		ids = append(ids, r.Metadata().ID)
+	}
+
+	sort.Strings(ids)
+
+	return ids
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/stretchr/testify/require"
+)
+
+func planResource(id string) types.Resource {
+	return &container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: id}}}
+}
+
+func TestPrintPlanListsChanges(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	printPlan(
+		out,
+		[]types.Resource{planResource("resource.container.web")},
+		[]types.Resource{planResource("resource.container.db")},
+		[]types.Resource{planResource("resource.container.old"), planResource("resource.container.cache")},
+	)
+
+	require.Contains(t, out.String(), "resource.container.web will be created")
+	require.Contains(t, out.String(), "resource.container.db will be updated")
+	require.Contains(t, out.String(), "resource.container.old will be destroyed")
+	require.Contains(t, out.String(), "Plan: 1 to create, 1 to update, 2 to destroy")
+	require.Less(t, bytes.Index(out.Bytes(), []byte("container.cache")), bytes.Index(out.Bytes(), []byte("container.old")))
+}
+
+func TestPrintPlanReportsNoChanges(t *testing.T) {
+	out := bytes.NewBufferString("")
+
+	printPlan(out, nil, nil, nil)
+
+	require.Contains(t, out.String(), "No changes")
+	require.NotContains(t, out.String(), "Plan:")
+}
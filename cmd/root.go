@@ -10,6 +10,7 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/jumppad"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 
 	"github.com/spf13/cobra"
 )
@@ -53,25 +54,39 @@ func Execute(v, c, d string) error {
 	// setup dependencies
 	l := createLogger()
 
+	// load any external plugins before creating the engine so that
+	// custom resources are registered with the parser
+	err := jumppad.LoadPlugins(utils.PluginsDir(), l)
+	if err != nil {
+		l.Error("Unable to load plugins", "error", err)
+	}
+
 	engineClients, _ := clients.GenerateClients(l)
 
 	engine, _ := createEngine(l, engineClients)
 
-	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(newCheckCmd(config.NewProviders(engineClients), engineClients.HTTP, engineClients.Kubernetes))
 	rootCmd.AddCommand(outputCmd)
 	rootCmd.AddCommand(newDevCmd())
 	rootCmd.AddCommand(newEnvCmd())
-	rootCmd.AddCommand(newRunCmd(engine, engineClients.ContainerTasks, engineClients.Getter, engineClients.HTTP, engineClients.System, engineClients.Connector, l))
+	rootCmd.AddCommand(newRunCmd(engine, engineClients.ContainerTasks, engineClients.Getter, engineClients.HTTP, engineClients.Kubernetes, engineClients.System, engineClients.Connector, l))
 	rootCmd.AddCommand(newTestCmd())
 	rootCmd.AddCommand(newDestroyCmd(engineClients.Connector, l))
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(newStateCmd(l))
 	rootCmd.AddCommand(newPurgeCmd(engineClients.Docker, engineClients.ImageLog, l))
+	rootCmd.AddCommand(newCacheCmd(engineClients.ContainerTasks, engineClients.ImageLog, l))
+	rootCmd.AddCommand(newPlanCmd(engine, engineClients.Getter))
 	rootCmd.AddCommand(taintCmd)
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(uninstallCmd)
 	rootCmd.AddCommand(newPushCmd(engineClients.ContainerTasks, l))
+	rootCmd.AddCommand(newPauseCmd(engineClients.Docker, engineClients.ContainerTasks, l))
+	rootCmd.AddCommand(newResumeCmd(engineClients.Docker, engineClients.ContainerTasks, l))
+	rootCmd.AddCommand(newImportCmd(engineClients.Docker, l))
 	rootCmd.AddCommand(newLogCmd(engineClients.Docker, os.Stdout, os.Stderr), completionCmd)
 	rootCmd.AddCommand(changelogCmd)
+	rootCmd.AddCommand(schemaCmd)
 
 	// add the server commands
 	rootCmd.AddCommand(connectorCmd)
@@ -83,6 +98,9 @@ func Execute(v, c, d string) error {
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.AddCommand(newGenerateReadmeCommand(engine))
 
+	// add the docs commands
+	rootCmd.AddCommand(newDocsCmd(engine))
+
 	// add the plugin commands
 	rootCmd.AddCommand(pluginCmd)
 
@@ -119,7 +137,7 @@ func Execute(v, c, d string) error {
 		return nil
 	}
 
-	err := rootCmd.Execute()
+	err = rootCmd.Execute()
 
 	if err != nil {
 		showErr(err)
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Output a JSON schema describing all resource types",
+	Long: `Output a JSON schema describing all resource types
+
+The schema contains a definition for every resource type that can be used in
+a blueprint, including the attributes and blocks and whether they are optional.
+It can be used to provide autocompletion and validation in editors.`,
+	Example: `
+  # Write the schema to a file
+  jumppad schema > jumppad.schema.json
+`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to generate schema: %s", err)
+		}
+
+		fmt.Println(string(d))
+
+		return nil
+	},
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hokaccha/go-prettyjson"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newStateCmd(l logger.Logger) *cobra.Command {
+	stateCmd := &cobra.Command{
+		Use:   "state",
+		Short: "Manage the jumppad state",
+		Long:  "Manage the jumppad state and the backups taken before each up and down",
+	}
+
+	stateCmd.AddCommand(newStateListCmd())
+	stateCmd.AddCommand(newStateShowCmd())
+	stateCmd.AddCommand(newStateBackupsCmd())
+	stateCmd.AddCommand(newStateRestoreCmd(l))
+
+	return stateCmd
+}
+
+func newStateListCmd() *cobra.Command {
+	var resourceType string
+	var module string
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the resources in the state",
+		Long:  "List the resources in the state, optionally filtered by type or module",
+		Example: `
+  # List all resources
+  jumppad state list
+
+  # List all the containers in the consul module
+  jumppad state list --type container --module consul
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to read state: %s", err)
+			}
+
+			res := filterResources(cfg.Resources, resourceType, module)
+			for _, r := range res {
+				fmt.Println(r.Metadata().ID)
+			}
+
+			if len(res) == 0 {
+				fmt.Println(grayText.Render("no resources found"))
+			}
+
+			return nil
+		},
+	}
+
+	listCmd.Flags().StringVarP(&resourceType, "type", "", "", "Only list resources of the given type, e.g. --type container")
+	listCmd.Flags().StringVarP(&module, "module", "", "", "Only list resources in the given module, e.g. --module consul")
+
+	return listCmd
+}
+
+func newStateShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <resource>",
+		Short: "Show the state of a resource",
+		Long:  "Show the full state of a resource including any computed outputs",
+		Example: `
+  jumppad state show resource.container.consul
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.LoadState()
+			if err != nil {
+				return fmt.Errorf("unable to read state: %s", err)
+			}
+
+			r, err := cfg.FindResource(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to find resource %s in the state: %s", args[0], err)
+			}
+
+			d, err := prettyjson.Marshal(r)
+			if err != nil {
+				return fmt.Errorf("unable to output resource as JSON: %s", err)
+			}
+
+			fmt.Println(string(d))
+
+			return nil
+		},
+	}
+}
+
+func newStateBackupsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "backups",
+		Short: "List the state backups",
+		Long:  "List the state backups, newest first",
+		Example: `
+  jumppad state backups
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			backups, err := config.ListStateBackups()
+			if err != nil {
+				return err
+			}
+
+			if len(backups) == 0 {
+				fmt.Println(grayText.Render("no state backups found"))
+				return nil
+			}
+
+			for _, b := range backups {
+				fmt.Println(b)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newStateRestoreCmd(l logger.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "restore <backup>",
+		Short: "Restore the state from a backup",
+		Long: `Restore the state from a backup, the current state is backed up
+before it is replaced`,
+		Example: `
+  jumppad state restore state-20240101T120000.000000000.json
+`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			err := config.RestoreState(args[0])
+			if err != nil {
+				return err
+			}
+
+			l.Info("Restored state from backup", "backup", args[0])
+
+			return nil
+		},
+	}
+}
+
+// filterResources returns the resources matching the given type and module,
+// resources in nested modules are included when filtering by module
+func filterResources(res []types.Resource, resourceType, module string) []types.Resource {
+	filtered := []types.Resource{}
+
+	for _, r := range res {
+		if resourceType != "" && r.Metadata().Type != resourceType {
+			continue
+		}
+
+		m := r.Metadata().Module
+		if module != "" && m != module && !strings.HasPrefix(m, module+".") {
+			continue
+		}
+
+		filtered = append(filtered, r)
+	}
+
+	return filtered
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/network"
+	"github.com/stretchr/testify/require"
+)
+
+func setupFilterResources() []types.Resource {
+	return []types.Resource{
+		&container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.web", Type: "container"}}},
+		&container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "module.consul.resource.container.consul", Type: "container", Module: "consul"}}},
+		&container.Container{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "module.consul.module.agent.resource.container.agent", Type: "container", Module: "consul.agent"}}},
+		&network.Network{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "module.consul.resource.network.local", Type: "network", Module: "consul"}}},
+	}
+}
+
+func TestFilterResourcesReturnsAllWhenNoFilter(t *testing.T) {
+	res := filterResources(setupFilterResources(), "", "")
+	require.Len(t, res, 4)
+}
+
+func TestFilterResourcesFiltersByType(t *testing.T) {
+	res := filterResources(setupFilterResources(), "network", "")
+	require.Len(t, res, 1)
+	require.Equal(t, "module.consul.resource.network.local", res[0].Metadata().ID)
+}
+
+func TestFilterResourcesFiltersByModuleIncludingNested(t *testing.T) {
+	res := filterResources(setupFilterResources(), "container", "consul")
+	require.Len(t, res, 2)
+	require.Equal(t, "module.consul.resource.container.consul", res[0].Metadata().ID)
+	require.Equal(t, "module.consul.module.agent.resource.container.agent", res[1].Metadata().ID)
+}
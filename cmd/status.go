@@ -36,6 +36,7 @@ const (
 
 var jsonFlag bool
 var resourceType string
+var repairFlag bool
 
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -45,7 +46,14 @@ var statusCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		// load the resources from state
 
-		cfg, err := config.LoadState()
+		load := config.LoadState
+		if repairFlag {
+			// rewrite the state removing any entries that could not be recovered
+			load = config.RepairState
+		}
+
+		cfg, err := load()
+
 		if err != nil {
 			fmt.Println(err)
 			fmt.Printf("Unable to read state file")
@@ -85,6 +93,7 @@ var statusCmd = &cobra.Command{
 					if (resourceType != "" && r.Metadata().Type != resourceType) ||
 						r.Metadata().Type == resources.TypeModule ||
 						r.Metadata().Type == resources.TypeVariable ||
+						r.Metadata().Type == resources.TypeLocal ||
 						r.Metadata().Type == resources.TypeOutput {
 						continue
 					}
@@ -155,4 +164,5 @@ var statusCmd = &cobra.Command{
 func init() {
 	statusCmd.Flags().BoolVarP(&jsonFlag, "json", "", false, "Output the status as JSON")
 	statusCmd.Flags().StringVarP(&resourceType, "type", "", "", "Resource type used to filter status list")
+	statusCmd.Flags().BoolVarP(&repairFlag, "repair", "", false, "Rewrite the state file removing any resources that could not be recovered from a corrupt state")
 }
@@ -266,6 +266,7 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 	args := []string{absPath}
 
 	noOpen := true
+	autoApprove := true
 
 	// re-use the run command
 	rc := newRunCmdFunc(
@@ -273,12 +274,15 @@ func (cr *CucumberRunner) iRunApplyAtPath(path string) error {
 		cr.cli.ContainerTasks,
 		cr.cli.Getter,
 		cr.cli.HTTP,
+		cr.cli.Kubernetes,
 		cr.cli.System,
 		cr.cli.Connector,
 		&noOpen,
 		cr.force,
 		&cr.variables,
 		&cr.variablesFile,
+		nil,
+		&autoApprove,
 		cr.l,
 	)
 
@@ -13,14 +13,17 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jumppad-labs/hclconfig"
 	"github.com/jumppad-labs/hclconfig/resources"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
 	cclients "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
 	"github.com/jumppad-labs/jumppad/pkg/clients/http"
+	kclients "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/clients/system"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
@@ -33,11 +36,13 @@ import (
 	markdown "github.com/MichaelMure/go-term-markdown"
 )
 
-func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, l logger.Logger) *cobra.Command {
+func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, kc kclients.Kubernetes, bc system.System, cc connector.Connector, l logger.Logger) *cobra.Command {
 	var noOpen bool
 	var force bool
 	var variables []string
 	var variablesFile string
+	var parallelism int
+	var autoApprove bool
 
 	runCmd := &cobra.Command{
 		Use:   "up [file] | [directory]",
@@ -54,7 +59,7 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
   jumppad up github.com/jumppad-labs/blueprints/kubernetes-vault
 	`,
 		Args:         cobra.ArbitraryArgs,
-		RunE:         newRunCmdFunc(e, dt, bp, hc, bc, cc, &noOpen, &force, &variables, &variablesFile, l),
+		RunE:         newRunCmdFunc(e, dt, bp, hc, kc, bc, cc, &noOpen, &force, &variables, &variablesFile, &parallelism, &autoApprove, l),
 		SilenceUsage: true,
 	}
 
@@ -62,11 +67,13 @@ func newRunCmd(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, h
 	runCmd.Flags().BoolVarP(&force, "force-update", "", false, "When set to true Jumppad ignores cached images or files and will download all resources")
 	runCmd.Flags().StringSliceVarP(&variables, "var", "", nil, "Allows setting variables from the command line, variables are specified as a key and value, e.g --var key=value. Can be specified multiple times")
 	runCmd.Flags().StringVarP(&variablesFile, "vars-file", "", "", "Load variables from a location other than *.vars files in the blueprint folder. E.g --vars-file=./file.vars")
+	runCmd.Flags().IntVarP(&parallelism, "parallelism", "", 0, "Maximum number of resources to create concurrently, 0 is unlimited")
+	runCmd.Flags().BoolVarP(&autoApprove, "auto-approve", "y", false, "When set to true Jumppad will not ask for confirmation before destroying or recreating resources")
 
 	return runCmd
 }
 
-func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, bc system.System, cc connector.Connector, noOpen *bool, force *bool, variables *[]string, variablesFile *string, l logger.Logger) func(cmd *cobra.Command, args []string) error {
+func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Getter, hc http.HTTP, kc kclients.Kubernetes, bc system.System, cc connector.Connector, noOpen *bool, force *bool, variables *[]string, variablesFile *string, parallelism *int, autoApprove *bool, l logger.Logger) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		// create the shipyard and sub folders in the users home directory
 		utils.CreateFolders()
@@ -77,17 +84,7 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 		}
 
 		// parse the vars into a map
-		vars := map[string]string{}
-		for _, v := range *variables {
-			// if the variable is wrapped in single quotes remove them
-			v = strings.TrimPrefix(v, "'")
-			v = strings.TrimSuffix(v, "'")
-
-			parts := strings.Split(v, "=")
-			if len(parts) >= 2 {
-				vars[parts[0]] = strings.Join(parts[1:], "=")
-			}
-		}
+		vars := parseVariables(*variables)
 
 		// check the variables file exists
 		if variablesFile != nil && *variablesFile != "" {
@@ -150,6 +147,24 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			}
 		}
 
+		// resources that have been removed from the config will be destroyed
+		// and failed or tainted resources recreated, confirm with the user
+		// before continuing
+		if autoApprove == nil || !*autoApprove {
+			_, changed, removed, _, err := e.Diff(dst, vars, *variablesFile)
+			if err != nil {
+				return err
+			}
+
+			state, _ := config.LoadState()
+			recreated := recreatedResources(state, changed)
+
+			err = confirmDestroy(cmd, bc, "The following resources will be destroyed or replaced", append(removed, recreated...), false)
+			if err != nil {
+				return err
+			}
+		}
+
 		// update status every 30s to let people know we are still running
 		statusUpdate := time.NewTicker(15 * time.Second)
 		startTime := time.Now()
@@ -175,11 +190,41 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 			cancel()
 		}()
 
+		if parallelism != nil && *parallelism > 0 {
+			e.SetParallelism(*parallelism)
+		}
+
 		config, err := e.ApplyWithVariables(ctx, dst, vars, *variablesFile)
 		if err != nil {
 			return err
 		}
 
+		// if we have a blueprint in the root, use it for the header and health checks
+		var b *blueprint.Blueprint
+		bps, _ := config.FindResourcesByType(blueprint.TypeBlueprint)
+		for _, bp := range bps {
+			// pick the first blueprint in the root
+			if bp.Metadata().Module == "" {
+				b = bp.(*blueprint.Blueprint)
+				break
+			}
+		}
+
+		// ensure all resources are healthy before declaring the blueprint ready
+		if b != nil && b.HealthCheckTimeout != "" {
+			timeout, err := time.ParseDuration(b.HealthCheckTimeout)
+			if err != nil {
+				return fmt.Errorf("unable to parse duration for the blueprint health_check_timeout, please specify as a go duration i.e 30s, 1m: %s", err)
+			}
+
+			l.Info("Checking resource health", "timeout", timeout)
+
+			err = checkResourceHealth(ctx, config, hc, kc, timeout, l)
+			if err != nil {
+				return fmt.Errorf("blueprint resources did not become healthy: %s", err)
+			}
+		}
+
 		// do not open the browser windows
 		if !*noOpen {
 
@@ -210,32 +255,55 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 							port = "80"
 						}
 
-						browserList = append(browserList, buildBrowserPath(r.Metadata().Name, port, r.Metadata().Type, ""))
+						// the url contains the scheme, docs served with a
+						// certificate are opened with https
+						browserList = append(browserList, buildBrowserPath(r.Metadata().Name, port, r.Metadata().Type, v.URL+v.OpenPath))
 					}
 				}
 			}
 
-			// check the browser windows in the blueprint file
+			// add the browser windows defined in the blueprint
+			if b != nil {
+				for _, w := range b.BrowserWindows {
+					browserList = append(browserList, replaceLegacyDomain(w))
+				}
+			}
+
+			// health check the urls in parallel, windows are only opened once
+			// all checks have completed so that they open in the defined order
 			wg := sync.WaitGroup{}
 			wg.Add(len(browserList))
 
+			healthy := make([]bool, len(browserList))
+
 			l.Debug("Health check urls for browser windows", "count", len(browserList))
-			for _, b := range browserList {
-				go func(uri string) {
+			for i, b := range browserList {
+				go func(i int, uri string) {
 					// health check the URL
 					err := hc.HealthCheckHTTP(uri, "", map[string][]string{}, "", []int{200}, checkDuration)
 					if err == nil {
-						be := bc.OpenBrowser(uri)
-						if be != nil {
-							l.Error("Unable to open browser", "error", be)
-						}
+						healthy[i] = true
+					} else {
+						l.Debug("Browser window not reachable, skipping", "url", uri, "error", err)
 					}
 
 					wg.Done()
-				}(b)
+				}(i, b)
 			}
 
 			wg.Wait()
+
+			for i, uri := range browserList {
+				if !healthy[i] {
+					continue
+				}
+
+				be := bc.OpenBrowser(uri)
+				if be != nil {
+					l.Error("Unable to open browser", "error", be)
+				}
+			}
+
 			l.Debug("Browser windows open")
 		}
 
@@ -243,16 +311,6 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 		statusUpdate.Stop()
 
 		// if we have a blueprint show the header
-		var b *blueprint.Blueprint
-		bps, _ := config.FindResourcesByType(blueprint.TypeBlueprint)
-		for _, bp := range bps {
-			// pick the first blueprint in the root
-			if bp.Metadata().Module == "" {
-				b = bp.(*blueprint.Blueprint)
-				break
-			}
-		}
-
 		if b != nil {
 			cmd.Println("")
 			cmd.Println("########################################################")
@@ -309,6 +367,51 @@ func newRunCmdFunc(e jumppad.Engine, dt cclients.ContainerTasks, bp getter.Gette
 	}
 }
 
+// checkResourceHealth re-runs the health checks for all resources, an error is
+// returned if any check does not pass before the timeout expires
+func checkResourceHealth(ctx context.Context, c *hclconfig.Config, hc http.HTTP, kc kclients.Kubernetes, timeout time.Duration, l logger.Logger) error {
+	deadline := time.Now().Add(timeout)
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		l.Debug("Checking resource health", "ref", r.Metadata().ID)
+
+		// the checks share the timeout, never pass a negative remaining time
+		remaining := time.Until(deadline)
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		err := checkHealth(ctx, r, hc, kc, remaining)
+		if err != nil {
+			return fmt.Errorf("health check failed for %s: %s", r.Metadata().ID, err)
+		}
+	}
+
+	return nil
+}
+
+// replaceLegacyDomain rewrites URLs that reference the legacy shipyard.run
+// domain to use the current local domain
+func replaceLegacyDomain(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || !strings.HasSuffix(u.Hostname(), ".shipyard.run") {
+		return uri
+	}
+
+	host := strings.TrimSuffix(u.Hostname(), "shipyard.run") + "local." + utils.LocalTLD
+	if u.Port() != "" {
+		host = host + ":" + u.Port()
+	}
+
+	u.Host = host
+
+	return u.String()
+}
+
 func buildBrowserPath(n, p string, resourceType string, path string) string {
 	// if the path starts with http or https then override the default behaviour
 	if strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://") {
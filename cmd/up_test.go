@@ -16,12 +16,15 @@ import (
 	cmock "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	gettermock "github.com/jumppad-labs/jumppad/pkg/clients/getter/mocks"
 	httpmock "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
+	kclients "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	systemmock "github.com/jumppad-labs/jumppad/pkg/clients/system/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/blueprint"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/docs"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/ingress"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
 	enginemocks "github.com/jumppad-labs/jumppad/pkg/jumppad/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
@@ -38,6 +41,7 @@ type runMocks struct {
 	system    *systemmock.System
 	tasks     *cmock.ContainerTasks
 	connector *conmock.Connector
+	kube      *kclients.MockKubernetes
 }
 
 func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
@@ -76,6 +80,9 @@ func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
 		nil,
 	)
 
+	mockKube := &kclients.MockKubernetes{}
+	mockKube.On("SetConfig", mock.Anything).Return(nil)
+
 	clients := &clients.Clients{
 		HTTP:      mockHTTP,
 		Getter:    mockGetter,
@@ -89,10 +96,12 @@ func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
 	mockEngine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&hclconfig, nil)
 	mockEngine.On("GetClients", mock.Anything).Return(clients)
 	mockEngine.On("ResourceCountForType", mock.Anything).Return(0)
+	mockEngine.On("Diff", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil, nil, nil, nil)
 
 	bp := blueprint.Blueprint{}
 
 	mockEngine.On("Blueprint").Return(&bp)
+	mockEngine.On("Config").Return(&hclconfig)
 
 	rm := &runMocks{
 		engine:    mockEngine,
@@ -101,9 +110,10 @@ func setupRun(t *testing.T) (*cobra.Command, *runMocks) {
 		system:    mockSystem,
 		connector: mockConnector,
 		tasks:     mockContainer,
+		kube:      mockKube,
 	}
 
-	cmd := newRunCmd(mockEngine, mockContainer, mockGetter, mockHTTP, mockSystem, mockConnector, logger.NewTestLogger(t))
+	cmd := newRunCmd(mockEngine, mockContainer, mockGetter, mockHTTP, mockKube, mockSystem, mockConnector, logger.NewTestLogger(t))
 	cmd.SetOut(bytes.NewBuffer([]byte("")))
 
 	return cmd, rm
@@ -215,7 +225,7 @@ func TestRunSetsVariablesFromFlag(t *testing.T) {
 	err := rf.Execute()
 	require.NoError(t, err)
 
-	args := rm.engine.Calls[0].Arguments[2]
+	args := testutils.GetCalls(&rm.engine.Mock, "ApplyWithVariables")[0].Arguments[2]
 
 	require.Equal(t, map[string]string{
 		"abc":  "1234",
@@ -351,3 +361,143 @@ func TestRunDoesNotOpensBrowserWindowWhenCheckError(t *testing.T) {
 
 	rm.system.AssertNumberOfCalls(t, "OpenBrowser", 0)
 }
+
+func setupHealthCheckConfig(rm *runMocks, timeout string) *hclconfig.Config {
+	b := &blueprint.Blueprint{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "test", Type: "blueprint"}}}
+	b.HealthCheckTimeout = timeout
+
+	c := &container.Container{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{ID: "resource.container.test", Name: "test", Type: "container"}}}
+	c.HealthCheck = &healthcheck.HealthCheckContainer{
+		HTTP: []healthcheck.HealthCheckHTTP{{Address: "http://localhost:8500/v1/status/leader"}},
+		TCP:  []healthcheck.HealthCheckTCP{{Address: "localhost:8500"}},
+	}
+
+	hclconfig := hclconfig.Config{}
+	hclconfig.Resources = []hcltypes.Resource{b, c}
+
+	testutils.RemoveOn(&rm.engine.Mock, "ApplyWithVariables")
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		&hclconfig,
+		nil,
+	)
+
+	rm.http.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	return &hclconfig
+}
+
+func TestRunChecksResourceHealthWhenBlueprintTimeoutSet(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+	setupHealthCheckConfig(rm, "60s")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.http.AssertCalled(t, "HealthCheckTCP", "localhost:8500", mock.Anything)
+	rm.http.AssertCalled(t, "HealthCheckHTTP", "http://localhost:8500/v1/status/leader", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunDoesNotCheckResourceHealthWhenBlueprintTimeoutNotSet(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+	setupHealthCheckConfig(rm, "")
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.http.AssertNotCalled(t, "HealthCheckTCP", mock.Anything, mock.Anything)
+	rm.http.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunReturnsErrorWhenResourceHealthCheckFails(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+	setupHealthCheckConfig(rm, "60s")
+
+	testutils.RemoveOn(&rm.http.Mock, "HealthCheckHTTP")
+	rm.http.On("HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := rf.Execute()
+	require.ErrorContains(t, err, "resource.container.test")
+}
+
+func TestRunReturnsErrorWhenKubernetesHealthCheckFails(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+	cfg := setupHealthCheckConfig(rm, "60s")
+
+	kc := &k8s.Config{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{ID: "resource.k8s_config.test", Name: "test", Type: "k8s_config"}}}
+	kc.Cluster.KubeConfig.ConfigPath = "/tmp/kubeconfig.yaml"
+	kc.HealthCheck = &healthcheck.HealthCheckKubernetes{Pods: []string{"app=consul"}}
+
+	cfg.Resources = append(cfg.Resources, kc)
+
+	rm.kube.On("HealthCheckPods", mock.Anything, []string{"app=consul"}, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := rf.Execute()
+	require.ErrorContains(t, err, "resource.k8s_config.test")
+
+	rm.kube.AssertCalled(t, "SetConfig", "/tmp/kubeconfig.yaml")
+}
+
+func TestRunReturnsErrorWhenBlueprintTimeoutInvalid(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+	setupHealthCheckConfig(rm, "sixty")
+
+	err := rf.Execute()
+	require.Error(t, err)
+}
+
+func TestRunOpensBlueprintBrowserWindowsInOrder(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"/tmp"})
+
+	b := &blueprint.Blueprint{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "test", Type: "blueprint"}}}
+	b.BrowserWindows = []string{
+		"http://consul-http.ingress.shipyard.run:8500",
+		"http://localhost:8080",
+		"https://docs.jumppad.dev",
+	}
+
+	hclconfig := hclconfig.Config{}
+	hclconfig.Resources = []hcltypes.Resource{b}
+
+	testutils.RemoveOn(&rm.engine.Mock, "ApplyWithVariables")
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		&hclconfig,
+		nil,
+	)
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.system.AssertNumberOfCalls(t, "OpenBrowser", 3)
+	require.Equal(t, "http://consul-http.ingress.local.jmpd.in:8500", rm.system.Calls[0].Arguments[0])
+	require.Equal(t, "http://localhost:8080", rm.system.Calls[1].Arguments[0])
+	require.Equal(t, "https://docs.jumppad.dev", rm.system.Calls[2].Arguments[0])
+}
+
+func TestRunDoesNotOpenBlueprintBrowserWindowsWhenNoBrowser(t *testing.T) {
+	rf, rm := setupRun(t)
+	rf.SetArgs([]string{"--no-browser", "/tmp"})
+
+	b := &blueprint.Blueprint{ResourceBase: hcltypes.ResourceBase{Meta: hcltypes.Meta{Name: "test", Type: "blueprint"}}}
+	b.BrowserWindows = []string{"http://localhost:8080"}
+
+	hclconfig := hclconfig.Config{}
+	hclconfig.Resources = []hcltypes.Resource{b}
+
+	testutils.RemoveOn(&rm.engine.Mock, "ApplyWithVariables")
+	rm.engine.On("ApplyWithVariables", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		&hclconfig,
+		nil,
+	)
+
+	err := rf.Execute()
+	require.NoError(t, err)
+
+	rm.http.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	rm.system.AssertNumberOfCalls(t, "OpenBrowser", 0)
+}
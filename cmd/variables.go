@@ -0,0 +1,21 @@
+package cmd
+
+import "strings"
+
+// parseVariables converts the variables set with the --var flag into a map,
+// variables are specified as key=value and the value can contain =
+func parseVariables(variables []string) map[string]string {
+	vars := map[string]string{}
+	for _, v := range variables {
+		// if the variable is wrapped in single quotes remove them
+		v = strings.TrimPrefix(v, "'")
+		v = strings.TrimSuffix(v, "'")
+
+		parts := strings.Split(v, "=")
+		if len(parts) >= 2 {
+			vars[parts[0]] = strings.Join(parts[1:], "=")
+		}
+	}
+
+	return vars
+}
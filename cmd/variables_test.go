@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVariablesSplitsKeyAndValue(t *testing.T) {
+	vars := parseVariables([]string{
+		"abc=1234",
+		"'foo=bar'",
+		"nic=cool=beans",
+		"invalid",
+	})
+
+	require.Equal(t, map[string]string{
+		"abc": "1234",
+		"foo": "bar",
+		"nic": "cool=beans",
+	}, vars)
+}
@@ -35,5 +35,7 @@ resource "container" "consul" {
     os                    = system("os")
     arch                  = system("arch")
     exists                = exists("file")
+    json_log_level        = jsondecode(file("./config.json")).log_level
+    yaml_log_level        = jsondecode(yaml_to_json(file("./config.yaml"))).log_level
   }
 }
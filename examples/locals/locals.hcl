@@ -0,0 +1,39 @@
+variable "consul_version" {
+  default = "1.16.2"
+}
+
+resource "network" "onprem" {
+  subnet = "10.6.0.0/16"
+}
+
+local "consul_image" {
+  value = "hashicorp/consul:${variable.consul_version}"
+}
+
+local "consul_address" {
+  value = "http://${resource.container.consul.container_name}:8500"
+}
+
+resource "container" "consul" {
+  image {
+    name = local.consul_image
+  }
+
+  network {
+    id = resource.network.onprem.meta.id
+  }
+}
+
+resource "container" "consul_client" {
+  image {
+    name = local.consul_image
+  }
+
+  environment = {
+    CONSUL_HTTP_ADDR = local.consul_address
+  }
+
+  network {
+    id = resource.network.onprem.meta.id
+  }
+}
@@ -1,6 +1,8 @@
 package example
 
 import (
+	"context"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
@@ -20,17 +22,17 @@ func (p *ExampleProvider) Init(resource types.Resource, logger sdk.Logger) error
 	return nil
 }
 
-func (p *ExampleProvider) Create(context.Background()) error {
+func (p *ExampleProvider) Create(ctx context.Context) error {
 	p.logger.Info("Create example")
 	return nil
 }
 
-func (p *ExampleProvider) Destroy(context.Background(),false) error {
+func (p *ExampleProvider) Destroy(ctx context.Context, force bool) error {
 	p.logger.Info("Destroy example")
 	return nil
 }
 
-func (p *ExampleProvider) Refresh() error {
+func (p *ExampleProvider) Refresh(ctx context.Context) error {
 	p.logger.Info("Refresh example")
 	return nil
 }
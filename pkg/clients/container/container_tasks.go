@@ -66,8 +66,15 @@ type ContainerTasks interface {
 	// io.ReadCloser.
 	// Returns an error if the container is not running
 	ContainerLogs(id string, stdOut, stdErr bool) (io.ReadCloser, error)
-	// CopyFromContainer allows the copying of a file from a container
+	// WaitForContainerExit blocks until the container with the given id stops
+	// running and returns the exit code of the container
+	WaitForContainerExit(id string) (int, error)
+	// CopyFromContainer allows the copying of a file or directory from a container,
+	// directories are copied recursively
 	CopyFromContainer(id, src, dst string) error
+	// CopyFromContainerAndTransform copies a file or directory from a container,
+	// the contents of each file are passed through transform before they are written
+	CopyFromContainerAndTransform(id, src, dst string, transform func([]byte) []byte) error
 	// CopyToContainer allows a file to be copied into a container
 	CopyFileToContainer(id, src, dst string) error
 	// CreateFileInContainer creates a file with the given contents and name in the container containerID and
@@ -100,7 +107,8 @@ type ContainerTasks interface {
 	DetachNetwork(network, containerid string) error
 	// ListNetworks lists the networks a container is attached to
 	ListNetworks(id string) []types.NetworkAttachment
-	// FindNetwork returns a network using the unique resource id
+	// FindNetwork returns a network using the unique resource id, networks
+	// not created by jumppad are matched by their Docker id or name
 	FindNetwork(id string) (types.NetworkAttachment, error)
 
 	// CreateShell in the running container and attach
@@ -3,6 +3,7 @@ package container
 import (
 	"context"
 	"io"
+	"os"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/checkpoint"
@@ -12,6 +13,7 @@ import (
 	"github.com/docker/docker/api/types/system"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 )
 
@@ -31,6 +33,7 @@ type Docker interface {
 	ContainerList(ctx context.Context, options container.ListOptions) ([]container.Summary, error)
 	ContainerStart(context.Context, string, container.StartOptions) error
 	ContainerStop(ctx context.Context, containerID string, options container.StopOptions) error
+	ContainerWait(ctx context.Context, containerID string, condition container.WaitCondition) (<-chan container.WaitResponse, <-chan error)
 	ContainerRemove(ctx context.Context, containerID string, options container.RemoveOptions) error
 	ContainerLogs(ctx context.Context, container string, options container.LogsOptions) (io.ReadCloser, error)
 	ContainerExecCreate(ctx context.Context, container string, config container.ExecOptions) (container.ExecCreateResponse, error)
@@ -71,12 +74,29 @@ type Docker interface {
 	Info(ctx context.Context) (system.Info, error)
 }
 
-// NewDocker creates a new Docker client
+// NewDocker creates a new Docker client, the API version is negotiated with the
+// engine unless DOCKER_API_VERSION is set, in which case that version is used
 func NewDocker() (Docker, error) {
-	cli, err := client.NewClientWithOpts(client.WithHostFromEnv(), client.WithVersion("1.41"))
+	opts := []client.Opt{
+		client.WithHostFromEnv(),
+		client.WithVersionFromEnv(),
+		client.WithAPIVersionNegotiation(),
+	}
+
+	// the Docker SDK only knows the location of the Docker socket, when
+	// DOCKER_HOST is not set connect to Podman using its own socket
+	if os.Getenv("DOCKER_HOST") == "" && utils.UsePodman() {
+		opts = append(opts, client.WithHost("unix://"+utils.GetPodmanSocket()))
+	}
+
+	cli, err := client.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, err
 	}
 
+	// negotiate the version now rather than on the first request so that the
+	// version can be logged, this is a noop when DOCKER_API_VERSION is set
+	cli.NegotiateAPIVersion(context.Background())
+
 	return cli, nil
 }
@@ -12,6 +12,7 @@ import (
 	gosignal "os/signal"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,8 +27,10 @@ import (
 	"github.com/docker/docker/api/types/network"
 	registrytypes "github.com/docker/docker/api/types/registry"
 	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/docker/pkg/jsonmessage"
 	"github.com/docker/go-connections/nat"
+	"github.com/docker/go-units"
 	dtypes "github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/images"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
@@ -51,10 +54,15 @@ const (
 
 const defaultExitCode = 254
 
+// hostOS is the operating system jumppad is running on, bind mount sources
+// on Windows need converting to a path the container engine accepts
+var hostOS = runtime.GOOS
+
 // DockerTasks is a concrete implementation of ContainerTasks which uses the Docker SDK
 type DockerTasks struct {
 	engineType    string
 	storageDriver string
+	rootless      bool
 	memory        int
 	cpu           int
 	c             Docker
@@ -63,6 +71,8 @@ type DockerTasks struct {
 	tg            *ctar.TarGz
 	force         bool
 	defaultWait   time.Duration
+	copyAttempts  int
+	copyBackoff   time.Duration
 }
 
 // NewDockerTasks creates a DockerTasks with the given Docker client
@@ -74,6 +84,11 @@ func NewDockerTasks(c Docker, il images.ImageLog, tg *ctar.TarGz, l logger.Logge
 		return nil, fmt.Errorf("error checking server version, error: %s", err)
 	}
 
+	// the client version is only available from the real Docker client
+	if cv, ok := c.(interface{ ClientVersion() string }); ok {
+		l.Debug("Docker API version", "client", cv.ClientVersion(), "server", ver.APIVersion, "server_min", ver.MinAPIVersion)
+	}
+
 	t := dtypes.EngineNotFound
 
 	for _, c := range ver.Components {
@@ -91,11 +106,70 @@ func NewDockerTasks(c Docker, il images.ImageLog, tg *ctar.TarGz, l logger.Logge
 		return nil, fmt.Errorf("error checking server storage driver, error: %s", err)
 	}
 
-	return &DockerTasks{engineType: t, storageDriver: info.Driver, c: c, il: il, tg: tg, l: l, defaultWait: 1 * time.Second, cpu: info.NCPU, memory: int(info.MemTotal)}, nil
+	// rootless Docker and Podman report the rootless security option
+	rootless := false
+	for _, o := range info.SecurityOptions {
+		if o == "name=rootless" {
+			rootless = true
+		}
+	}
+
+	copyAttempts, copyBackoff := copyRetryPolicy(l)
+
+	return &DockerTasks{
+		engineType:    t,
+		storageDriver: info.Driver,
+		rootless:      rootless,
+		c:             c,
+		il:            il,
+		tg:            tg,
+		l:             l,
+		defaultWait:   1 * time.Second,
+		copyAttempts:  copyAttempts,
+		copyBackoff:   copyBackoff,
+		cpu:           info.NCPU,
+		memory:        int(info.MemTotal),
+	}, nil
+}
+
+// defaultCopyAttempts is the number of times a copy from a container is
+// attempted before failing
+var defaultCopyAttempts = 5
+
+// defaultCopyBackoff is the time to wait before retrying a failed copy from a
+// container, the wait doubles after each attempt
+var defaultCopyBackoff = 500 * time.Millisecond
+
+// copyRetryPolicy returns the number of attempts and the initial backoff for
+// copying from a container, the defaults can be overridden with the
+// JUMPPAD_COPY_ATTEMPTS and JUMPPAD_COPY_BACKOFF environment variables
+func copyRetryPolicy(l logger.Logger) (int, time.Duration) {
+	attempts := defaultCopyAttempts
+	backoff := defaultCopyBackoff
+
+	if v := os.Getenv("JUMPPAD_COPY_ATTEMPTS"); v != "" {
+		a, err := strconv.Atoi(v)
+		if err != nil || a < 1 {
+			l.Warn("Invalid value for JUMPPAD_COPY_ATTEMPTS, must be a number greater than zero, using default", "value", v, "default", attempts)
+		} else {
+			attempts = a
+		}
+	}
+
+	if v := os.Getenv("JUMPPAD_COPY_BACKOFF"); v != "" {
+		b, err := time.ParseDuration(v)
+		if err != nil || b < 0 {
+			l.Warn("Invalid value for JUMPPAD_COPY_BACKOFF, must be a duration i.e. 1s, using default", "value", v, "default", backoff)
+		} else {
+			backoff = b
+		}
+	}
+
+	return attempts, backoff
 }
 
 func (d *DockerTasks) EngineInfo() *dtypes.EngineInfo {
-	return &dtypes.EngineInfo{StorageDriver: d.storageDriver, EngineType: d.engineType, CPU: d.cpu, Memory: d.memory}
+	return &dtypes.EngineInfo{StorageDriver: d.storageDriver, EngineType: d.engineType, Rootless: d.rootless, CPU: d.cpu, Memory: d.memory}
 }
 
 // SetForce sets a global override for the DockerTasks, when set to true
@@ -146,6 +220,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		Tty:          true,
 		OpenStdin:    true,
 		User:         user,
+		StopSignal:   c.StopSignal,
 	}
 
 	// create the host and network configs
@@ -195,13 +270,35 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 				{
 					Driver:       c.Resources.GPU.Driver,
 					DeviceIDs:    c.Resources.GPU.DeviceIDs,
+					Count:        c.Resources.GPU.Count,
 					Capabilities: [][]string{{"gpu", c.Resources.GPU.Driver, "compute"}},
 				},
 			}
 		}
 	}
 
-	// Add GPU details
+	if c.ShmSize > 0 {
+		hc.ShmSize = c.ShmSize
+	}
+
+	// set any ulimits, like devices these are part of the resources
+	for _, u := range c.Ulimits {
+		hc.Ulimits = append(hc.Ulimits, &units.Ulimit{
+			Name: u.Name,
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+
+	// map any devices from the host, this is done after the resources are
+	// set as the devices are part of the resources
+	for _, dv := range c.Devices {
+		hc.Devices = append(hc.Devices, container.DeviceMapping{
+			PathOnHost:        dv.Host,
+			PathInContainer:   dv.Container,
+			CgroupPermissions: dv.Permissions,
+		})
+	}
 
 	// by default the container should NOT be attached to a network
 	nc.EndpointsConfig = make(map[string]*network.EndpointSettings)
@@ -253,6 +350,11 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 			}
 		}
 
+		source := vc.Source
+		if t == mount.TypeBind && hostOS == "windows" {
+			source = utils.DockerBindPath(vc.Source, d.engineType)
+		}
+
 		var bindOptions *mount.BindOptions
 		if t == mount.TypeBind {
 			bindOptions = &mount.BindOptions{Propagation: bp, NonRecursive: vc.BindPropagationNonRecursive}
@@ -276,11 +378,11 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 			} else if vc.SelinuxRelabel == "private" {
 				options = append(options, "Z")
 			}
-			volumes = append(volumes, fmt.Sprintf("%s:%s:%s", vc.Source, vc.Destination, strings.Join(options, ",")))
+			volumes = append(volumes, fmt.Sprintf("%s:%s:%s", source, vc.Destination, strings.Join(options, ",")))
 		} else {
 			mounts = append(mounts, mount.Mount{
 				Type:        t,
-				Source:      vc.Source,
+				Source:      source,
 				Target:      vc.Destination,
 				ReadOnly:    vc.ReadOnly,
 				BindOptions: bindOptions,
@@ -317,6 +419,15 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		hc.CgroupnsMode = "host"
 	}
 
+	// use the requested network mode instead of attaching to networks
+	if c.NetworkMode != "" {
+		d.l.Debug("Setting network mode", "ref", c.Name, "mode", c.NetworkMode)
+
+		hc.NetworkMode = container.NetworkMode(c.NetworkMode)
+		// when sharing another network namespace can not use a hostname
+		dc.Hostname = ""
+	}
+
 	// are we attaching the container to a sidecar network?
 	ipv6Enabled := false
 	for _, n := range c.Networks {
@@ -340,8 +451,9 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		}
 	}
 
-	// disable ipv6 networking
-	if !ipv6Enabled {
+	// disable ipv6 networking, sysctls can not be set when the network
+	// namespace is shared
+	if !ipv6Enabled && c.NetworkMode == "" {
 		hc.Sysctls = map[string]string{"net.ipv6.conf.all.disable_ipv6": "1"}
 	}
 
@@ -397,7 +509,7 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 		}
 	}
 
-	if len(c.Networks) == 0 {
+	if len(c.Networks) == 0 && c.NetworkMode == "" {
 		net, err := d.FindNetwork("resource.network.jumppad")
 		if err != nil {
 			return "", err
@@ -417,6 +529,12 @@ func (d *DockerTasks) CreateContainer(c *dtypes.Container) (string, error) {
 
 	err = d.c.ContainerStart(context.Background(), cont.ID, container.StartOptions{})
 	if err != nil {
+		// docker returns a generic error when the device driver for the gpu
+		// is not installed
+		if c.Resources != nil && c.Resources.GPU != nil && strings.Contains(err.Error(), "could not select device driver") {
+			return "", fmt.Errorf("unable to allocate gpu using the %s driver, ensure the %s container runtime is installed on the host: %w", c.Resources.GPU.Driver, c.Resources.GPU.Driver, err)
+		}
+
 		return "", err
 	}
 
@@ -514,7 +632,7 @@ func (d *DockerTasks) PullImage(img dtypes.Image, force bool) error {
 		}
 	}
 
-	ipo := image.PullOptions{}
+	ipo := image.PullOptions{Platform: img.Platform}
 
 	// if the username and password is not null make an authenticated
 	// image pull
@@ -522,7 +640,7 @@ func (d *DockerTasks) PullImage(img dtypes.Image, force bool) error {
 		ipo.RegistryAuth = createRegistryAuth(img.Username, img.Password)
 	}
 
-	d.l.Debug("Pulling image", "image", in)
+	d.l.Debug("Pulling image", "image", in, "platform", img.Platform)
 
 	out, err := d.c.ImagePull(context.Background(), in, ipo)
 	if err != nil {
@@ -766,11 +884,64 @@ func (d *DockerTasks) ContainerLogs(id string, stdOut, stdErr bool) (io.ReadClos
 	return d.c.ContainerLogs(context.Background(), id, container.LogsOptions{ShowStderr: stdErr, ShowStdout: stdOut})
 }
 
-// CopyFromContainer copies a file from a container
+// WaitForContainerExit blocks until the container stops running and returns
+// the exit code
+func (d *DockerTasks) WaitForContainerExit(id string) (int, error) {
+	d.l.Debug("Waiting for container to exit", "id", id)
+
+	statusCh, errCh := d.c.ContainerWait(context.Background(), id, container.WaitConditionNotRunning)
+
+	select {
+	case err := <-errCh:
+		return 0, fmt.Errorf("unable to wait for container %s to exit: %w", id, err)
+	case status := <-statusCh:
+		if status.Error != nil {
+			return int(status.StatusCode), fmt.Errorf("unable to wait for container %s to exit: %s", id, status.Error.Message)
+		}
+
+		return int(status.StatusCode), nil
+	}
+}
+
+// CopyFromContainer copies a file or directory from a container, directories
+// are copied recursively preserving their structure, transient errors are
+// retried with a backoff, an error is returned immediately when the container
+// or the source path does not exist
 func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
+	return d.CopyFromContainerAndTransform(id, src, dst, nil)
+}
+
+// CopyFromContainerAndTransform copies a file or directory from a container
+// like CopyFromContainer, the contents of every copied file are passed through
+// transform before they are written to the destination. When transform is nil
+// the files are copied unchanged
+func (d *DockerTasks) CopyFromContainerAndTransform(id, src, dst string, transform func([]byte) []byte) error {
 	d.l.Debug("Copying file from", "id", id, "src", src, "dst", dst)
 
-	reader, _, err := d.c.CopyFromContainer(context.Background(), id, src)
+	backoff := d.copyBackoff
+
+	var err error
+	for attempt := 1; attempt <= d.copyAttempts; attempt++ {
+		err = d.copyFromContainer(id, src, dst, transform)
+		if err == nil || errdefs.IsNotFound(err) {
+			return err
+		}
+
+		if attempt < d.copyAttempts {
+			d.l.Debug("Unable to copy from container, retrying", "id", id, "src", src, "attempt", attempt, "backoff", backoff, "error", err)
+
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+
+	return err
+}
+
+// copyFromContainer makes a single attempt to copy a file or directory from
+// the container
+func (d *DockerTasks) copyFromContainer(id, src, dst string, transform func([]byte) []byte) error {
+	reader, stat, err := d.c.CopyFromContainer(context.Background(), id, src)
 	if err != nil {
 		return fmt.Errorf("unable to copy '%s' from container '%s': %w", src, id, err)
 	}
@@ -793,14 +964,24 @@ func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
 		}
 	}
 
+	err = os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return fmt.Errorf("unable to create destination directory: %s", err)
+	}
+
 	err = d.tg.Extract(reader, false, dir)
 	if err != nil {
 		return fmt.Errorf("unable to extract tar file: %s", err)
 	}
 
 	// copy the source temp to the destination
-	// the source file or folder name is the last part of the src
-	filename := path.Base(src)
+	// the source file or folder name is the root of the tar, when docker does
+	// not return the name it is the last part of the src
+	filename := stat.Name
+	if filename == "" {
+		filename = path.Base(src)
+	}
+
 	tmpPath := path.Join(dir, filename)
 
 	// if tmpPath is a directory copy the directory
@@ -809,8 +990,8 @@ func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
 		return fmt.Errorf("file or directory %s does not exist inside tar", src)
 	}
 
-	if i.IsDir() {
-		err = copyDir(tmpPath, dst)
+	if stat.Mode.IsDir() || i.IsDir() {
+		err = copyDir(tmpPath, dst, transform)
 		if err != nil {
 			return fmt.Errorf("unable to copy temporary files to destination %s", err)
 		}
@@ -819,7 +1000,7 @@ func (d *DockerTasks) CopyFromContainer(id, src, dst string) error {
 	}
 
 	// else just copy the file
-	_, err = copy(tmpPath, dst)
+	err = copyFile(tmpPath, dst, i.Mode().Perm(), transform)
 	if err != nil {
 		return fmt.Errorf("unable to copy temporary files to destination %s", err)
 	}
@@ -892,16 +1073,30 @@ func (d *DockerTasks) CopyLocalDockerImagesToVolume(images []string, volume stri
 	}
 
 	// copy the images to a volume
-	return d.CopyFilesToVolume(volume, savedImages, "/images", force)
+	imported, cached, err := d.copyFilesToVolume(volume, savedImages, "/images", force)
+	if err != nil {
+		return nil, err
+	}
+
+	d.l.Info("Imported images to volume", "volume", volume, "total", len(imported), "cached", cached, "copied", len(imported)-cached)
+
+	return imported, nil
 }
 
 // CopyFileToVolume copies a file to a Docker volume
 // returns the names of the stored files
 func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, path string, force bool) ([]string, error) {
+	imported, _, err := d.copyFilesToVolume(volumeID, filenames, path, force)
+	return imported, err
+}
+
+// copyFilesToVolume copies the files to the volume and returns the names of
+// the stored files and the number of files that were already in the volume
+func (d *DockerTasks) copyFilesToVolume(volumeID string, filenames []string, path string, force bool) ([]string, int, error) {
 	// make sure we have the alpine image needed to copy
 	err := d.PullImage(dtypes.Image{Name: "alpine:latest"}, false)
 	if err != nil {
-		return nil, fmt.Errorf("unable pull 'alpine:latest' needed to copy files to volume: %w", err)
+		return nil, 0, fmt.Errorf("unable pull 'alpine:latest' needed to copy files to volume: %w", err)
 	}
 
 	// create a dummy container to import to volume
@@ -914,7 +1109,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 	cc.Volumes = []dtypes.Volume{
 		{
 			Source:      volumeID,
-			Destination: "/cache",
+			Destination: utils.ImageVolumeMountPath,
 			Type:        "volume",
 		},
 	}
@@ -922,7 +1117,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 
 	tmpID, err := d.CreateContainer(cc)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create dummy container for importing files: %w", err)
+		return nil, 0, fmt.Errorf("unable to create dummy container for importing files: %w", err)
 	}
 	defer d.RemoveContainer(tmpID, true)
 
@@ -956,7 +1151,7 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 			d.l.Error("Timeout waiting for container to start", "ref", tmpID, "error", err)
 			startError = fmt.Errorf("timeout waiting for container to start: %w", startError)
 
-			return nil, startError
+			return nil, 0, startError
 		}
 
 		// still waiting for success wait
@@ -966,14 +1161,15 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 	// container is running copy the files
 
 	// create the directory paths ensure unix paths for containers
-	destPath := filepath.ToSlash(filepath.Join("/cache", path))
+	destPath := filepath.ToSlash(filepath.Join(utils.ImageVolumeMountPath, path))
 	_, err = d.ExecuteCommand(tmpID, []string{"mkdir", "-p", destPath}, nil, "/", "", "", 300, nil)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create destination path '%s' in volume: %w", destPath, err)
+		return nil, 0, fmt.Errorf("unable to create destination path '%s' in volume: %w", destPath, err)
 	}
 
 	// add each file individually
 	imported := []string{}
+	cached := 0
 	for _, f := range filenames {
 		// get the filename part
 		name := filepath.Base(f)
@@ -986,19 +1182,21 @@ func (d *DockerTasks) CopyFilesToVolume(volumeID string, filenames []string, pat
 				// we have the image already
 				d.l.Debug("File already cached", "name", name, "path", path)
 				imported = append(imported, destFile)
+				cached++
 				continue
 			}
 		}
 
 		err = d.CopyFileToContainer(cc.Name, f, destPath)
 		if err != nil {
-			return nil, fmt.Errorf("unable to copy file %s to container: %w", f, err)
+			return nil, 0, fmt.Errorf("unable to copy file %s to container: %w", f, err)
 		}
 
+		d.l.Debug("File copied to volume", "name", name, "path", path)
 		imported = append(imported, destFile)
 	}
 
-	return imported, nil
+	return imported, cached, nil
 }
 
 // CreateFileInContainer creates a file with the given contents and name in the container containerID and
@@ -1369,7 +1567,8 @@ func (d *DockerTasks) DetachNetwork(network, containerID string) error {
 	return err
 }
 
-// FindNetwork returns a network using the unique resource id
+// FindNetwork returns a network using the unique resource id, networks
+// not created by jumppad are matched by their Docker id or name
 func (d *DockerTasks) FindNetwork(id string) (dtypes.NetworkAttachment, error) {
 	nets, err := d.c.NetworkList(context.Background(), network.ListOptions{})
 	if err != nil {
@@ -1378,18 +1577,35 @@ func (d *DockerTasks) FindNetwork(id string) (dtypes.NetworkAttachment, error) {
 
 	for _, n := range nets {
 		if n.Labels["id"] == id {
-			return dtypes.NetworkAttachment{
-				ID:          n.ID,
-				Name:        n.Name,
-				Subnet:      n.IPAM.Config[0].Subnet,
-				IPv6Enabled: n.EnableIPv6,
-			}, nil
+			return networkAttachment(n), nil
+		}
+	}
+
+	// external networks are not created by jumppad and do not have the id
+	// label
+	for _, n := range nets {
+		if id != "" && (n.ID == id || n.Name == id) {
+			return networkAttachment(n), nil
 		}
 	}
 
 	return dtypes.NetworkAttachment{}, fmt.Errorf("a network with the label id: %s, was not found", id)
 }
 
+func networkAttachment(n network.Summary) dtypes.NetworkAttachment {
+	na := dtypes.NetworkAttachment{
+		ID:          n.ID,
+		Name:        n.Name,
+		IPv6Enabled: n.EnableIPv6,
+	}
+
+	if len(n.IPAM.Config) > 0 {
+		na.Subnet = n.IPAM.Config[0].Subnet
+	}
+
+	return na
+}
+
 func (d *DockerTasks) TagImage(source, destination string) error {
 	return d.c.ImageTag(context.Background(), source, destination)
 }
@@ -1531,7 +1747,7 @@ func (d *DockerTasks) saveImageToTempFile(image, filename string) (string, error
 	return tmpFileName, nil
 }
 
-func copyDir(src string, dest string) error {
+func copyDir(src string, dest string, transform func([]byte) []byte) error {
 
 	if dest == src {
 		return fmt.Errorf("cannot copy a folder into the folder itself")
@@ -1550,7 +1766,7 @@ func copyDir(src string, dest string) error {
 		return fmt.Errorf("Source " + file.Name() + " is not a directory!")
 	}
 
-	err = os.Mkdir(dest, 0755)
+	err = os.MkdirAll(dest, file.Mode().Perm())
 	if err != nil {
 		return err
 	}
@@ -1564,7 +1780,7 @@ func copyDir(src string, dest string) error {
 
 		if f.IsDir() {
 
-			err = copyDir(src+"/"+f.Name(), dest+"/"+f.Name())
+			err = copyDir(filepath.Join(src, f.Name()), filepath.Join(dest, f.Name()), transform)
 			if err != nil {
 				return err
 			}
@@ -1573,16 +1789,16 @@ func copyDir(src string, dest string) error {
 
 		if !f.IsDir() {
 
-			content, err := os.ReadFile(src + "/" + f.Name())
+			info, err := f.Info()
 			if err != nil {
 				return err
-
 			}
 
-			err = os.WriteFile(dest+"/"+f.Name(), content, 0755)
+			// keep the permissions of the original file so that executables
+			// are still executable at the destination
+			err = copyFile(filepath.Join(src, f.Name()), filepath.Join(dest, f.Name()), info.Mode().Perm(), transform)
 			if err != nil {
 				return err
-
 			}
 
 		}
@@ -1592,27 +1808,26 @@ func copyDir(src string, dest string) error {
 	return nil
 }
 
-func copy(src, dst string) (int64, error) {
+// copyFile copies the regular file src to dst, when transform is not nil
+// the contents are transformed before they are written
+func copyFile(src, dst string, perm os.FileMode, transform func([]byte) []byte) error {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
 	if !sourceFileStat.Mode().IsRegular() {
-		return 0, fmt.Errorf("%s is not a regular file", src)
+		return fmt.Errorf("%s is not a regular file", src)
 	}
 
-	source, err := os.Open(src)
+	content, err := os.ReadFile(src)
 	if err != nil {
-		return 0, err
+		return err
 	}
-	defer source.Close()
 
-	destination, err := os.Create(dst)
-	if err != nil {
-		return 0, err
+	if transform != nil {
+		content = transform(content)
 	}
-	defer destination.Close()
-	nBytes, err := io.Copy(destination, source)
-	return nBytes, err
+
+	return os.WriteFile(dst, content, perm)
 }
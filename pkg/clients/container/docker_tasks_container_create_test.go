@@ -5,6 +5,7 @@ import (
 	"io"
 
 	"os"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -279,6 +280,25 @@ func TestContainerOnlyAttachesToDefaultNetworkWhenNil(t *testing.T) {
 	md.AssertNumberOfCalls(t, "NetworkConnect", 1)
 }
 
+func TestContainerSetsNetworkModeAndDoesNotAttachNetworks(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Networks = nil
+	cc.NetworkMode = "host"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkConnect", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	dc := params[1].(*container.Config)
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, container.NetworkMode("host"), hc.NetworkMode)
+	assert.Empty(t, dc.Hostname)
+	assert.Empty(t, hc.Sysctls)
+}
+
 func TestContainerAssignsIPToUserNetwork(t *testing.T) {
 	cc, md, mic := createContainerConfig()
 	cc.Networks[0].IPAddress = "192.168.1.123"
@@ -334,6 +354,28 @@ func TestContainerAttachesVolumeMounts(t *testing.T) {
 	assert.True(t, hc.Mounts[0].BindOptions.NonRecursive)
 }
 
+func TestContainerConvertsWindowsBindMountPaths(t *testing.T) {
+	hostOS = "windows"
+	t.Cleanup(func() { hostOS = runtime.GOOS })
+
+	// the source directory is created relative to the working directory
+	// when the tests are not running on Windows
+	wd, _ := os.Getwd()
+	os.Chdir(t.TempDir())
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	cc, md, mic := createContainerConfig()
+	cc.Volumes[0].Source = `C:\Users\me\config`
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, "/c/Users/me/config", hc.Mounts[0].Source)
+}
+
 func TestContainerIgnoresBindOptionsForVolumesTypeVolume(t *testing.T) {
 	cc, md, mic := createContainerConfig()
 	cc.Volumes[0].Type = "volume"
@@ -496,6 +538,52 @@ func TestContainerConfiguresResources(t *testing.T) {
 	assert.Equal(t, hc.Resources.CpusetCpus, "1,4")
 }
 
+func TestContainerConfiguresDevices(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Devices = []dtypes.Device{{Host: "/dev/fuse", Container: "/dev/fuse", Permissions: "rwm"}}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Len(t, hc.Devices, 1)
+	assert.Equal(t, "/dev/fuse", hc.Devices[0].PathOnHost)
+	assert.Equal(t, "/dev/fuse", hc.Devices[0].PathInContainer)
+	assert.Equal(t, "rwm", hc.Devices[0].CgroupPermissions)
+	assert.Equal(t, int64(1000000000), hc.Resources.Memory)
+}
+
+func TestContainerConfiguresShmSize(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.ShmSize = 512 * 1024 * 1024
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, int64(512*1024*1024), hc.ShmSize)
+}
+
+func TestContainerConfiguresUlimits(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Ulimits = []dtypes.Ulimit{{Name: "nofile", Soft: 65535, Hard: 65535}}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Len(t, hc.Ulimits, 1)
+	assert.Equal(t, "nofile", hc.Ulimits[0].Name)
+	assert.Equal(t, int64(65535), hc.Ulimits[0].Soft)
+	assert.Equal(t, int64(65535), hc.Ulimits[0].Hard)
+}
+
 func TestContainerConfiguresGPU(t *testing.T) {
 	cc, md, mic := createContainerConfig()
 
@@ -511,6 +599,29 @@ func TestContainerConfiguresGPU(t *testing.T) {
 	assert.Equal(t, hc.DeviceRequests[0].Capabilities, [][]string{{"gpu", "nvidia", "compute"}})
 }
 
+func TestContainerConfiguresGPUCount(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.Resources.GPU = &dtypes.GPU{Driver: "nvidia", Count: -1}
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	hc := params[2].(*container.HostConfig)
+
+	assert.Equal(t, -1, hc.DeviceRequests[0].Count)
+	assert.Empty(t, hc.DeviceRequests[0].DeviceIDs)
+}
+
+func TestContainerReturnsClearErrorWhenGPUDriverMissing(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	testutils.RemoveOn(&md.Mock, "ContainerStart")
+	md.On("ContainerStart", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf(`could not select device driver "nvidia" with capabilities: [[gpu]]`))
+
+	err := setupContainer(t, cc, md, mic)
+	assert.ErrorContains(t, err, "ensure the nvidia container runtime is installed")
+}
+
 func TestContainerConfiguresRetryWhenCountGreater0(t *testing.T) {
 	cc, md, mic := createContainerConfig()
 	cc.MaxRestartCount = 10
@@ -607,3 +718,16 @@ func TestContainerLabels(t *testing.T) {
 	assert.Contains(t, dc.Labels, "com.example.foo")
 	assert.Equal(t, "bar", dc.Labels["com.example.foo"])
 }
+
+func TestContainerConfiguresStopSignal(t *testing.T) {
+	cc, md, mic := createContainerConfig()
+	cc.StopSignal = "SIGINT"
+
+	err := setupContainer(t, cc, md, mic)
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "ContainerCreate")[0].Arguments
+	dc := params[1].(*container.Config)
+
+	assert.Equal(t, "SIGINT", dc.StopSignal)
+}
@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/errdefs"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	imocks "github.com/jumppad-labs/jumppad/pkg/clients/images/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
@@ -59,6 +61,88 @@ func TestCopyFromContainerCopiesFile(t *testing.T) {
 	require.Equal(t, "test content", string(d))
 }
 
+func TestCopyFromContainerAndTransformRewritesContents(t *testing.T) {
+	id := "abc"
+	src := "/output/kubeconfig.yaml"
+
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+
+	tmpDir := t.TempDir()
+	tgz := &tar.TarGz{}
+
+	os.WriteFile(filepath.Join(tmpDir, "kubeconfig.yaml"), []byte("server: https://127.0.0.1:6443"), 0644)
+
+	buf := bytes.NewBuffer(nil)
+	err := tgz.Create(buf, &tar.TarGzOptions{OmitRoot: true}, []string{filepath.Join(tmpDir, "kubeconfig.yaml")})
+	require.NoError(t, err)
+
+	md.On("CopyFromContainer", mock.Anything, id, src).Return(
+		io.NopCloser(bytes.NewBuffer(buf.Bytes())),
+		container.PathStat{},
+		nil,
+	)
+
+	dt, _ := NewDockerTasks(md, &imocks.ImageLog{}, &tar.TarGz{}, logger.NewTestLogger(t))
+
+	dst := filepath.Join(tmpDir, "output", "kubeconfig.yaml")
+	err = dt.CopyFromContainerAndTransform(id, src, dst, func(d []byte) []byte {
+		return bytes.ReplaceAll(d, []byte("127.0.0.1"), []byte("10.1.1.1"))
+	})
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "server: https://10.1.1.1:6443", string(d))
+}
+
+func TestCopyFromContainerCopiesDirectory(t *testing.T) {
+	id := "abc"
+	src := "/output/site"
+
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+
+	tmpDir := t.TempDir()
+	tgz := &tar.TarGz{}
+
+	// create the test tar with a nested directory
+	os.MkdirAll(filepath.Join(tmpDir, "input", "site", "css"), 0755)
+	os.WriteFile(filepath.Join(tmpDir, "input", "site", "index.html"), []byte("<html></html>"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "input", "site", "css", "app.css"), []byte("body {}"), 0644)
+	os.WriteFile(filepath.Join(tmpDir, "input", "site", "run.sh"), []byte("#!/bin/sh"), 0755)
+
+	buf := bytes.NewBuffer(nil)
+	err := tgz.Create(buf, &tar.TarGzOptions{}, []string{filepath.Join(tmpDir, "input", "site")})
+	require.NoError(t, err)
+
+	md.On("CopyFromContainer", mock.Anything, id, src).Return(
+		io.NopCloser(bytes.NewBuffer(buf.Bytes())),
+		container.PathStat{Name: "site", Mode: os.ModeDir | 0755},
+		nil,
+	)
+
+	dt, _ := NewDockerTasks(md, &imocks.ImageLog{}, &tar.TarGz{}, logger.NewTestLogger(t))
+
+	dst := filepath.Join(tmpDir, "output", "public")
+	err = dt.CopyFromContainer(id, src, dst)
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(filepath.Join(dst, "index.html"))
+	require.NoError(t, err)
+	require.Equal(t, "<html></html>", string(d))
+
+	d, err = os.ReadFile(filepath.Join(dst, "css", "app.css"))
+	require.NoError(t, err)
+	require.Equal(t, "body {}", string(d))
+
+	i, err := os.Stat(filepath.Join(dst, "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), i.Mode().Perm())
+}
+
 func TestCopyFromContainerReturnsErrorOnDockerError(t *testing.T) {
 	id := "abc"
 	src := "/output/file.hcl"
@@ -74,7 +158,79 @@ func TestCopyFromContainerReturnsErrorOnDockerError(t *testing.T) {
 		fmt.Errorf("boom"),
 	)
 	dt, _ := NewDockerTasks(md, mic, &tar.TarGz{}, logger.NewTestLogger(t))
+	dt.copyBackoff = time.Millisecond
 
 	err := dt.CopyFromContainer(id, src, "/new.hcl")
 	assert.Error(t, err)
 }
+
+func TestCopyFromContainerRetriesTransientErrors(t *testing.T) {
+	id := "abc"
+	src := "/output/file.txt"
+
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+
+	tmpDir := t.TempDir()
+	tgz := &tar.TarGz{}
+
+	os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte("test content"), 0644)
+
+	buf := bytes.NewBuffer(nil)
+	err := tgz.Create(buf, &tar.TarGzOptions{OmitRoot: true}, []string{filepath.Join(tmpDir, "file.txt")})
+	require.NoError(t, err)
+
+	md.On("CopyFromContainer", mock.Anything, id, src).Return(
+		nil,
+		container.PathStat{},
+		fmt.Errorf("connection reset by peer"),
+	).Twice()
+
+	md.On("CopyFromContainer", mock.Anything, id, src).Return(
+		io.NopCloser(bytes.NewBuffer(buf.Bytes())),
+		container.PathStat{},
+		nil,
+	)
+
+	dt, _ := NewDockerTasks(md, &imocks.ImageLog{}, &tar.TarGz{}, logger.NewTestLogger(t))
+	dt.copyBackoff = time.Millisecond
+
+	err = dt.CopyFromContainer(id, src, filepath.Join(tmpDir, "copied.txt"))
+	require.NoError(t, err)
+	md.AssertNumberOfCalls(t, "CopyFromContainer", 3)
+
+	d, err := os.ReadFile(filepath.Join(tmpDir, "copied.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "test content", string(d))
+}
+
+func TestCopyFromContainerDoesNotRetryWhenPathNotFound(t *testing.T) {
+	id := "abc"
+	src := "/output/missing.txt"
+
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("CopyFromContainer", mock.Anything, id, src).Return(
+		nil,
+		container.PathStat{},
+		errdefs.NotFound(fmt.Errorf("Could not find the file /output/missing.txt in container abc")),
+	)
+
+	dt, _ := NewDockerTasks(md, &imocks.ImageLog{}, &tar.TarGz{}, logger.NewTestLogger(t))
+	dt.copyBackoff = time.Millisecond
+
+	err := dt.CopyFromContainer(id, src, "/new.txt")
+	require.Error(t, err)
+	md.AssertNumberOfCalls(t, "CopyFromContainer", 1)
+}
+
+func TestCopyRetryPolicyReadsEnvironment(t *testing.T) {
+	t.Setenv("JUMPPAD_COPY_ATTEMPTS", "10")
+	t.Setenv("JUMPPAD_COPY_BACKOFF", "2s")
+
+	attempts, backoff := copyRetryPolicy(logger.NewTestLogger(t))
+	require.Equal(t, 10, attempts)
+	require.Equal(t, 2*time.Second, backoff)
+}
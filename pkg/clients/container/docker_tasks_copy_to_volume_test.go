@@ -297,3 +297,13 @@ func TestCopyToVolumeRemovesTempContainer(t *testing.T) {
 	assert.NoError(t, err)
 	mk.AssertCalled(t, "ContainerRemove", mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestCopyToVolumeReturnsNumberOfCachedFiles(t *testing.T) {
+	dt, _ := testSetupCopyLocal(t)
+
+	imported, cached, err := dt.copyFilesToVolume(testCopyLocalVolume, []string{"/tmp/consul.tar", "/tmp/vault.tar"}, "/images", false)
+	assert.NoError(t, err)
+
+	assert.Len(t, imported, 2)
+	assert.Equal(t, 2, cached)
+}
@@ -0,0 +1,69 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/system"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/clients/tar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupFindNetworkMocks(t *testing.T) *DockerTasks {
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay2}, nil)
+	md.On("NetworkList", mock.Anything, mock.Anything).Return(
+		[]network.Summary{
+			{ID: "abc", Name: "cloud", Labels: map[string]string{"id": "resource.network.cloud"}, IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "10.0.0.0/24"}}}},
+			{ID: "123", Name: "compose_default", IPAM: network.IPAM{Config: []network.IPAMConfig{{Subnet: "172.18.0.0/16"}}}},
+		}, nil)
+
+	dt, _ := NewDockerTasks(md, nil, &tar.TarGz{}, logger.NewTestLogger(t))
+
+	return dt
+}
+
+func TestFindNetworkReturnsNetworkWithLabel(t *testing.T) {
+	dt := setupFindNetworkMocks(t)
+
+	n, err := dt.FindNetwork("resource.network.cloud")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", n.ID)
+	assert.Equal(t, "10.0.0.0/24", n.Subnet)
+}
+
+func TestFindNetworkReturnsExternalNetworkByName(t *testing.T) {
+	dt := setupFindNetworkMocks(t)
+
+	n, err := dt.FindNetwork("compose_default")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", n.ID)
+	assert.Equal(t, "172.18.0.0/16", n.Subnet)
+}
+
+func TestFindNetworkReturnsExternalNetworkByID(t *testing.T) {
+	dt := setupFindNetworkMocks(t)
+
+	n, err := dt.FindNetwork("123")
+	assert.NoError(t, err)
+	assert.Equal(t, "compose_default", n.Name)
+}
+
+func TestFindNetworkDoesNotMatchResourceNameForExternalNetworks(t *testing.T) {
+	dt := setupFindNetworkMocks(t)
+
+	_, err := dt.FindNetwork("resource.network.compose_default")
+	assert.Error(t, err)
+}
+
+func TestFindNetworkReturnsErrorWhenNotFound(t *testing.T) {
+	dt := setupFindNetworkMocks(t)
+
+	_, err := dt.FindNetwork("resource.network.missing")
+	assert.Error(t, err)
+}
@@ -69,6 +69,15 @@ func TestPullImageWhenNOTCached(t *testing.T) {
 	mic.AssertCalled(t, "Log", mock.Anything, mock.Anything)
 }
 
+func TestPullImageWithPlatformWhenNOTCached(t *testing.T) {
+	cc, md, mic := createImagePullConfig()
+	cc.Platform = "linux/arm64"
+
+	setupImagePull(t, cc, md, mic, false)
+
+	md.AssertCalled(t, "ImagePull", mock.Anything, makeImageCanonical(cc.Name), image.PullOptions{Platform: "linux/arm64"})
+}
+
 func TestPullImageWithCredentialsWhenNOTCached(t *testing.T) {
 	cc, md, mic := createImagePullConfig()
 	cc.Username = "nicjackson"
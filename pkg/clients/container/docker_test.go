@@ -0,0 +1,46 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/system"
+	"github.com/docker/docker/client"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDockerUsesAPIVersionFromEnv(t *testing.T) {
+	t.Setenv("DOCKER_API_VERSION", "1.40")
+
+	d, err := NewDocker()
+	require.NoError(t, err)
+
+	require.Equal(t, "1.40", d.(*client.Client).ClientVersion())
+}
+
+func TestNewDockerUsesPodmanSocketWhenRuntimePodman(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("DOCKER_API_VERSION", "1.40")
+	t.Setenv("JUMPPAD_RUNTIME", "podman")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+
+	d, err := NewDocker()
+	require.NoError(t, err)
+
+	require.Equal(t, "unix:///run/user/1000/podman/podman.sock", d.(*client.Client).DaemonHost())
+}
+
+func TestNewDockerTasksDetectsRootlessEngine(t *testing.T) {
+	md := &mocks.Docker{}
+	md.On("ServerVersion", mock.Anything).Return(types.Version{Components: []types.ComponentVersion{{Name: "Podman Engine"}}}, nil)
+	md.On("Info", mock.Anything).Return(system.Info{Driver: StorageDriverOverlay, SecurityOptions: []string{"name=seccomp,profile=default", "name=rootless"}}, nil)
+
+	dt, err := NewDockerTasks(md, nil, nil, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	require.Equal(t, "podman", dt.EngineInfo().EngineType)
+	require.True(t, dt.EngineInfo().Rootless)
+}
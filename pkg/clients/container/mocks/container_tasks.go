@@ -186,6 +186,24 @@ func (_m *ContainerTasks) CopyFromContainer(id string, src string, dst string) e
 	return r0
 }
 
+// CopyFromContainerAndTransform provides a mock function with given fields: id, src, dst, transform
+func (_m *ContainerTasks) CopyFromContainerAndTransform(id string, src string, dst string, transform func([]byte) []byte) error {
+	ret := _m.Called(id, src, dst, transform)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CopyFromContainerAndTransform")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, func([]byte) []byte) error); ok {
+		r0 = rf(id, src, dst, transform)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CopyLocalDockerImagesToVolume provides a mock function with given fields: images, volume, force
 func (_m *ContainerTasks) CopyLocalDockerImagesToVolume(images []string, volume string, force bool) ([]string, error) {
 	ret := _m.Called(images, volume, force)
@@ -651,6 +669,34 @@ func (_m *ContainerTasks) TagImage(source string, destination string) error {
 	return r0
 }
 
+// WaitForContainerExit provides a mock function with given fields: id
+func (_m *ContainerTasks) WaitForContainerExit(id string) (int, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForContainerExit")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (int, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) int); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewContainerTasks creates a new instance of ContainerTasks. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewContainerTasks(t interface {
@@ -372,6 +372,38 @@ func (_m *Docker) ContainerStop(ctx context.Context, containerID string, options
 	return r0
 }
 
+// ContainerWait provides a mock function with given fields: ctx, containerID, condition
+func (_m *Docker) ContainerWait(ctx context.Context, containerID string, condition typescontainer.WaitCondition) (<-chan typescontainer.WaitResponse, <-chan error) {
+	ret := _m.Called(ctx, containerID, condition)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerWait")
+	}
+
+	var r0 <-chan typescontainer.WaitResponse
+	var r1 <-chan error
+	if rf, ok := ret.Get(0).(func(context.Context, string, typescontainer.WaitCondition) (<-chan typescontainer.WaitResponse, <-chan error)); ok {
+		return rf(ctx, containerID, condition)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, typescontainer.WaitCondition) <-chan typescontainer.WaitResponse); ok {
+		r0 = rf(ctx, containerID, condition)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan typescontainer.WaitResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, typescontainer.WaitCondition) <-chan error); ok {
+		r1 = rf(ctx, containerID, condition)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan error)
+		}
+	}
+
+	return r0, r1
+}
+
 // CopyFromContainer provides a mock function with given fields: ctx, containerID, srcPath
 func (_m *Docker) CopyFromContainer(ctx context.Context, containerID string, srcPath string) (io.ReadCloser, typescontainer.PathStat, error) {
 	ret := _m.Called(ctx, containerID, srcPath)
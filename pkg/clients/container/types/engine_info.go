@@ -7,6 +7,10 @@ type EngineInfo struct {
 	// EngineType, docker, podman, not found
 	EngineType string
 
+	// Rootless is true when the engine is running without root privileges,
+	// containers can not mount overlay filesystems even when privileged
+	Rootless bool
+
 	// EngineType, docker, podman, not found
 	CPU    int
 	Memory int
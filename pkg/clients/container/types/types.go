@@ -3,6 +3,7 @@ package types
 type Container struct {
 	Name            string
 	Networks        []NetworkAttachment
+	NetworkMode     string // network mode for the container [host, none, container:<name>], when set the container is not attached to networks
 	Image           *Image
 	Entrypoint      []string
 	Command         []string
@@ -14,7 +15,11 @@ type Container struct {
 	DNS             []string
 	Privileged      bool
 	Capabilities    *Capabilities
+	Devices         []Device
+	Ulimits         []Ulimit
 	MaxRestartCount int
+	ShmSize         int64  // size of /dev/shm in bytes, 0 uses the engine default
+	StopSignal      string // signal sent to stop the container, empty uses the image default
 
 	// resource constraints
 	Resources *Resources
@@ -40,6 +45,20 @@ type NetworkAttachment struct {
 	IPv6Enabled bool
 }
 
+// Device is a device on the host that is mapped into the container
+type Device struct {
+	Host        string // path of the device on the host
+	Container   string // path of the device in the container
+	Permissions string // cgroup permissions for the device i.e. rwm
+}
+
+// Ulimit is a resource limit set for the processes in the container
+type Ulimit struct {
+	Name string
+	Soft int64
+	Hard int64
+}
+
 type Capabilities struct {
 	Add  []string
 	Drop []string
@@ -56,6 +75,7 @@ type Resources struct {
 type GPU struct {
 	Driver    string
 	DeviceIDs []string
+	Count     int
 }
 
 // Volume defines a folder, Docker volume, or temp folder to mount to the Container
@@ -94,6 +114,8 @@ type Image struct {
 	Username string
 	// Password is the Docker registry password to use for private repositories
 	Password string
+	// Platform is the os/arch of the image to pull i.e. linux/arm64
+	Platform string
 }
 
 type Build struct {
@@ -1,13 +1,16 @@
 package helm
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"gopkg.in/yaml.v3"
 	"helm.sh/helm/v3/pkg/action"
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
@@ -16,7 +19,9 @@ import (
 	"helm.sh/helm/v3/pkg/downloader"
 	"helm.sh/helm/v3/pkg/getter"
 	"helm.sh/helm/v3/pkg/kube"
+	"helm.sh/helm/v3/pkg/registry"
 	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
 )
 
 var helmLock sync.Mutex
@@ -29,22 +34,31 @@ func init() {
 
 // Helm defines an interface for a client which can manage Helm charts
 type Helm interface {
-	// CreateFromRepository creates a Helm install from a repository
-	Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error
+	// CreateFromRepository creates a Helm install from a repository, charts
+	// can also be installed from an OCI registry using an oci:// reference,
+	// username and password are optional credentials for the registry, when
+	// atomic is set a failed install or upgrade is rolled back, timeout bounds
+	// the time Helm waits for the operation to complete, values are merged
+	// after the values file and before the string values
+	Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, atomic bool, timeout time.Duration, chart, version, username, password, valuesPath string, valuesString map[string]string, valuesObject map[string]interface{}) error
 
 	// Destroy the given chart
 	Destroy(kubeConfig, name, namespace string) error
 
-	//UpsertChartRepository configures the remote chart repository
-	UpsertChartRepository(name, url string) error
+	//UpsertChartRepository configures the remote chart repository, username,
+	// password, and the certificate files are optional and are used to
+	// authenticate with private repositories. The repository certificate is
+	// verified unless insecureSkipTLSVerify is set
+	UpsertChartRepository(name, url, username, password, certFile, keyFile, caFile string, insecureSkipTLSVerify bool) error
 }
 
 type HelmImpl struct {
-	log        logger.Logger
-	repoPath   string
-	cachePath  string
-	dataPath   string
-	configPath string
+	log            logger.Logger
+	repoPath       string
+	cachePath      string
+	dataPath       string
+	configPath     string
+	registryConfig string
 }
 
 func NewHelm(l logger.Logger) Helm {
@@ -65,6 +79,10 @@ func NewHelm(l logger.Logger) Helm {
 		os.Create(helmRepoConfig)
 	}
 
+	// use the users registry config for OCI credentials, this must be read
+	// before the Helm paths are overridden
+	helmRegistryConfig := cli.New().RegistryConfig
+
 	os.Setenv("HELM_CACHE_HOME", helmCachePath)
 	os.Setenv("HELM_CONFIG_HOME", helmConfigPath)
 	os.Setenv("HELM_DATA_HOME", helmDataPath)
@@ -72,10 +90,10 @@ func NewHelm(l logger.Logger) Helm {
 	// try to load the default config
 	helmStorage, _ = repo.LoadFile(helmRepoConfig)
 
-	return &HelmImpl{l, helmRepoConfig, helmCachePath, helmDataPath, helmConfigPath}
+	return &HelmImpl{l, helmRepoConfig, helmCachePath, helmDataPath, helmConfigPath, helmRegistryConfig}
 }
 
-func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, chart, version, valuesPath string, valuesString map[string]string) error {
+func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bool, skipCRDs bool, atomic bool, timeout time.Duration, chart, version, username, password, valuesPath string, valuesString map[string]string, valuesObject map[string]interface{}) error {
 	// set the kube client for Helm
 	s := kube.GetConfig(kubeConfig, "default", namespace)
 	cfg := &action.Configuration{}
@@ -87,11 +105,20 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 		return fmt.Errorf("unable to initialize Helm: %w", err)
 	}
 
+	// the registry client is needed to locate charts from OCI registries
+	cfg.RegistryClient, err = h.newRegistryClient(username, password)
+	if err != nil {
+		return fmt.Errorf("unable to create Helm registry client: %w", err)
+	}
+
 	client := action.NewInstall(cfg)
 	client.ReleaseName = name
 	client.Namespace = namespace
 	client.CreateNamespace = createNamespace
 	client.SkipCRDs = skipCRDs
+	client.Atomic = atomic
+	client.Wait = atomic
+	client.Timeout = timeout
 
 	settings := h.getSettings()
 	settings.Debug = true
@@ -119,6 +146,18 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 		vo.ValueFiles = []string{valuesPath}
 	}
 
+	// structured values are written to a temporary file so that they are
+	// merged in the same way as a values file
+	if len(valuesObject) > 0 {
+		vf, err := writeValuesFile(valuesObject)
+		if err != nil {
+			return fmt.Errorf("unable to write Helm values: %w", err)
+		}
+		defer os.Remove(vf)
+
+		vo.ValueFiles = append(vo.ValueFiles, vf)
+	}
+
 	vals, err := vo.MergeValues(p)
 	if err != nil {
 		return fmt.Errorf("error merging Helm values: %w", err)
@@ -149,6 +188,7 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 					Getters:          p,
 					RepositoryConfig: settings.RepositoryConfig,
 					RepositoryCache:  settings.RepositoryCache,
+					RegistryClient:   cfg.RegistryClient,
 					Debug:            h.log.IsDebug(),
 				}
 				if err := man.Update(); err != nil {
@@ -170,6 +210,30 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 		return fmt.Errorf("error validating chart: %w", err)
 	}
 
+	// if the release already exists upgrade it rather than failing the install
+	exists, err := releaseExists(cfg, name)
+	if err != nil {
+		return fmt.Errorf("unable to check for existing release: %w", err)
+	}
+
+	if exists {
+		h.log.Debug("Upgrade chart", "ref", name)
+
+		upgrade := action.NewUpgrade(cfg)
+		upgrade.Namespace = namespace
+		upgrade.SkipCRDs = skipCRDs
+		upgrade.Atomic = atomic
+		upgrade.Wait = atomic
+		upgrade.Timeout = timeout
+
+		_, err = upgrade.Run(name, chartRequested, vals)
+		if err != nil {
+			return fmt.Errorf("error upgrading chart: %w", err)
+		}
+
+		return nil
+	}
+
 	h.log.Debug("Run chart", "ref", name)
 	_, err = client.Run(chartRequested, vals)
 	if err != nil {
@@ -179,6 +243,63 @@ func (h *HelmImpl) Create(kubeConfig, name, namespace string, createNamespace bo
 	return nil
 }
 
+// writeValuesFile marshals the values to YAML in a temporary file and returns
+// the path
+func writeValuesFile(values map[string]interface{}) (string, error) {
+	d, err := yaml.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp("", "values-*.yaml")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	_, err = f.Write(d)
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// releaseExists returns true when a release with the given name has been
+// installed in the namespace of the configuration
+func releaseExists(cfg *action.Configuration, name string) (bool, error) {
+	hist := action.NewHistory(cfg)
+	hist.Max = 1
+
+	_, err := hist.Run(name)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// newRegistryClient creates a client for OCI registries, credentials are read
+// from the Helm registry config unless a username and password are given
+func (h *HelmImpl) newRegistryClient(username, password string) (*registry.Client, error) {
+	opts := []registry.ClientOption{
+		registry.ClientOptWriter(h.log.StandardWriter()),
+		registry.ClientOptCredentialsFile(h.registryConfig),
+		registry.ClientOptEnableCache(true),
+	}
+
+	if username != "" {
+		opts = append(opts, registry.ClientOptBasicAuth(username, password))
+	}
+
+	return registry.NewClient(opts...)
+}
+
 func checkIfInstallable(ch *chart.Chart) error {
 	switch ch.Metadata.Type {
 	case "", "application":
@@ -211,11 +332,16 @@ func (h *HelmImpl) Destroy(kubeConfig, name, namespace string) error {
 	return nil
 }
 
-func (h *HelmImpl) UpsertChartRepository(name, url string) error {
+func (h *HelmImpl) UpsertChartRepository(name, url, username, password, certFile, keyFile, caFile string, insecureSkipTLSVerify bool) error {
 	r := repo.Entry{
 		Name:                  name,
 		URL:                   url,
-		InsecureSkipTLSverify: true,
+		Username:              username,
+		Password:              password,
+		CertFile:              certFile,
+		KeyFile:               keyFile,
+		CAFile:                caFile,
+		InsecureSkipTLSverify: insecureSkipTLSVerify,
 	}
 
 	// ensure only a single client can operate at one time
@@ -255,6 +381,7 @@ func (h *HelmImpl) getSettings() cli.EnvSettings {
 	settings := cli.EnvSettings{}
 	settings.RepositoryConfig = h.repoPath
 	settings.RepositoryCache = h.cachePath
+	settings.RegistryConfig = h.registryConfig
 
 	return settings
 }
@@ -1,6 +1,8 @@
 package helm
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -17,6 +19,85 @@ func TestUpsertChartRepository(t *testing.T) {
 	})
 
 	hc := NewHelm(logger.NewTestLogger(t))
-	err := hc.UpsertChartRepository("hashicorp", "https://helm.releases.hashicorp.com")
+	err := hc.UpsertChartRepository("hashicorp", "https://helm.releases.hashicorp.com", "", "", "", "", "", false)
 	require.NoError(t, err)
 }
+
+func TestUpsertChartRepositoryUsesBasicAuth(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u, p, ok := r.BasicAuth()
+		if !ok || u != "admin" || p != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	t.Cleanup(ts.Close)
+
+	hc := NewHelm(logger.NewTestLogger(t))
+	err := hc.UpsertChartRepository("private-auth", ts.URL, "admin", "secret", "", "", "", false)
+	require.NoError(t, err)
+}
+
+func TestUpsertChartRepositoryErrorsWithInvalidCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(ts.Close)
+
+	hc := NewHelm(logger.NewTestLogger(t))
+	err := hc.UpsertChartRepository("private-noauth", ts.URL, "admin", "wrong", "", "", "", false)
+	require.Error(t, err)
+}
+
+func TestUpsertChartRepositoryVerifiesTLS(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	t.Cleanup(ts.Close)
+
+	hc := NewHelm(logger.NewTestLogger(t))
+	err := hc.UpsertChartRepository("private-tls", ts.URL, "", "", "", "", "", false)
+	require.Error(t, err)
+}
+
+func TestUpsertChartRepositorySkipsTLSVerification(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("apiVersion: v1\nentries: {}\n"))
+	}))
+	t.Cleanup(ts.Close)
+
+	hc := NewHelm(logger.NewTestLogger(t))
+	err := hc.UpsertChartRepository("private-insecure", ts.URL, "", "", "", "", "", true)
+	require.NoError(t, err)
+}
+
+func TestWriteValuesFileWritesYAML(t *testing.T) {
+	vf, err := writeValuesFile(map[string]interface{}{
+		"server": map[string]interface{}{
+			"replicas": 3,
+			"enabled":  true,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { os.Remove(vf) })
+
+	d, err := os.ReadFile(vf)
+	require.NoError(t, err)
+
+	require.Contains(t, string(d), "replicas: 3")
+	require.Contains(t, string(d), "enabled: true")
+}
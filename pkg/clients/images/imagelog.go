@@ -4,29 +4,52 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
 // ImageTypeDocker defines a type for a Docker image
 const ImageTypeDocker string = "Docker"
 
+const (
+	// DefaultMaxLogSize is the size in bytes at which the log is rotated, can
+	// be overridden with the JUMPPAD_IMAGE_LOG_MAX_SIZE environment variable
+	DefaultMaxLogSize int64 = 10 * 1024 * 1024
+	// DefaultMaxLogFiles is the number of rotated logs that are kept, can be
+	// overridden with the JUMPPAD_IMAGE_LOG_MAX_FILES environment variable
+	DefaultMaxLogFiles int = 3
+)
+
 // ImageLog logs machine images to make cleanup possible
 //
 //go:generate mockery --name ImageLog --filename imagelog.go
 type ImageLog interface {
 	Log(string, string) error
 	Read(string) ([]string, error)
+	Remove(string, string) error
 	Clear() error
 }
 
 type ImageFileLog struct {
-	f string
+	f        string
+	maxSize  int64
+	maxFiles int
 }
 
 // NewImageFileLog creates an ImageLog which uses a file as the underlying
-// Datastore
+// Datastore, the file is rotated when it grows larger than the max size
 func NewImageFileLog(file string) *ImageFileLog {
-	return &ImageFileLog{file}
+	i := &ImageFileLog{f: file, maxSize: DefaultMaxLogSize, maxFiles: DefaultMaxLogFiles}
+
+	if v, err := strconv.ParseInt(os.Getenv("JUMPPAD_IMAGE_LOG_MAX_SIZE"), 10, 64); err == nil && v > 0 {
+		i.maxSize = v
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("JUMPPAD_IMAGE_LOG_MAX_FILES")); err == nil && v >= 0 {
+		i.maxFiles = v
+	}
+
+	return i
 }
 
 // Log an image has been downloaded by Shypyard
@@ -42,6 +65,11 @@ func (i *ImageFileLog) Log(name, t string) error {
 		}
 	}
 
+	err := i.rotate()
+	if err != nil {
+		return fmt.Errorf("unable to rotate image log: %w", err)
+	}
+
 	f, err := os.OpenFile(i.f, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
 		return err
@@ -52,9 +80,113 @@ func (i *ImageFileLog) Log(name, t string) error {
 	return err
 }
 
-// Read a list of images which have been downloaded by Shipyard
+// Read a list of images which have been downloaded by Shipyard, entries
+// in rotated logs are included
 func (i *ImageFileLog) Read(t string) ([]string, error) {
-	f, err := os.Open(i.f)
+	output := []string{}
+	found := false
+
+	for _, fn := range i.files() {
+		entries, err := readLog(fn, t)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+		output = append(output, entries...)
+	}
+
+	if !found {
+		_, err := os.Stat(i.f)
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// Remove an image from the log, the image is removed from the current and
+// any rotated logs
+func (i *ImageFileLog) Remove(name, t string) error {
+	for _, fn := range i.files() {
+		d, err := os.ReadFile(fn)
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		lines := []string{}
+		for _, l := range strings.Split(string(d), "\n") {
+			if l == "" || l == fmt.Sprintf("%s,%s", name, t) {
+				continue
+			}
+
+			lines = append(lines, l+"\n")
+		}
+
+		err = os.WriteFile(fn, []byte(strings.Join(lines, "")), 0666)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Clear the list of images
+func (i *ImageFileLog) Clear() error {
+	for _, fn := range i.files()[:i.maxFiles] {
+		os.Remove(fn)
+	}
+
+	return os.Remove(i.f)
+}
+
+// files returns the rotated logs oldest first followed by the current log
+func (i *ImageFileLog) files() []string {
+	files := []string{}
+	for n := i.maxFiles; n > 0; n-- {
+		files = append(files, fmt.Sprintf("%s.%d", i.f, n))
+	}
+
+	return append(files, i.f)
+}
+
+// rotate moves the current log to log.1 when it exceeds the max size,
+// existing rotated logs are shifted and the oldest is removed
+func (i *ImageFileLog) rotate() error {
+	fi, err := os.Stat(i.f)
+	if err != nil || fi.Size() < i.maxSize {
+		return nil
+	}
+
+	if i.maxFiles == 0 {
+		return os.Remove(i.f)
+	}
+
+	os.Remove(fmt.Sprintf("%s.%d", i.f, i.maxFiles))
+
+	for n := i.maxFiles - 1; n > 0; n-- {
+		from := fmt.Sprintf("%s.%d", i.f, n)
+		if _, err := os.Stat(from); err == nil {
+			err := os.Rename(from, fmt.Sprintf("%s.%d", i.f, n+1))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return os.Rename(i.f, i.f+".1")
+}
+
+func readLog(file, t string) ([]string, error) {
+	f, err := os.Open(file)
 	if err != nil {
 		return nil, err
 	}
@@ -66,15 +198,10 @@ func (i *ImageFileLog) Read(t string) ([]string, error) {
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
 		parts := strings.Split(scanner.Text(), ",")
-		if parts[1] == t {
+		if len(parts) == 2 && parts[1] == t {
 			output = append(output, parts[0])
 		}
 	}
 
 	return output, nil
 }
-
-// Clear the list of images
-func (i *ImageFileLog) Clear() error {
-	return os.Remove(i.f)
-}
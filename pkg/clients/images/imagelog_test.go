@@ -65,3 +65,78 @@ func TestImageWriteDeletesCache(t *testing.T) {
 
 	assert.NoFileExists(t, fn)
 }
+
+func TestImageWriteRotatesLogWhenLargerThanMaxSize(t *testing.T) {
+	fn := setupImageLogTests(t, "consul:latest,Docker\n")
+	t.Cleanup(func() {
+		os.Remove(fn)
+		os.Remove(fn + ".1")
+	})
+
+	i := NewImageFileLog(fn)
+	i.maxSize = 10
+
+	err := i.Log("vault:latest", ImageTypeDocker)
+	assert.NoError(t, err)
+
+	assert.FileExists(t, fn+".1")
+
+	list, err := i.Read(ImageTypeDocker)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"consul:latest", "vault:latest"}, list)
+}
+
+func TestImageWriteRemovesOldestRotatedLog(t *testing.T) {
+	fn := setupImageLogTests(t, "vault:latest,Docker\n")
+	os.WriteFile(fn+".1", []byte("consul:latest,Docker\n"), 0666)
+	t.Cleanup(func() {
+		os.Remove(fn)
+		os.Remove(fn + ".1")
+	})
+
+	i := NewImageFileLog(fn)
+	i.maxSize = 10
+	i.maxFiles = 1
+
+	err := i.Log("nomad:latest", ImageTypeDocker)
+	assert.NoError(t, err)
+
+	list, err := i.Read(ImageTypeDocker)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vault:latest", "nomad:latest"}, list)
+}
+
+func TestImageLogReadsMaxSizeFromEnv(t *testing.T) {
+	t.Setenv("JUMPPAD_IMAGE_LOG_MAX_SIZE", "1024")
+	t.Setenv("JUMPPAD_IMAGE_LOG_MAX_FILES", "5")
+
+	i := NewImageFileLog("images.log")
+
+	assert.Equal(t, int64(1024), i.maxSize)
+	assert.Equal(t, 5, i.maxFiles)
+}
+
+func TestImageClearDeletesRotatedLogs(t *testing.T) {
+	fn := setupImageLogTests(t, "consul:latest,Docker\n")
+	os.WriteFile(fn+".1", []byte("vault:latest,Docker\n"), 0666)
+
+	i := NewImageFileLog(fn)
+	err := i.Clear()
+	assert.NoError(t, err)
+
+	assert.NoFileExists(t, fn)
+	assert.NoFileExists(t, fn+".1")
+}
+
+func TestImageRemoveDeletesEntry(t *testing.T) {
+	fn := setupImageLogTests(t, "consul:latest,Docker\nvault:latest,Docker\n")
+	defer os.Remove(fn)
+
+	i := NewImageFileLog(fn)
+	err := i.Remove("consul:latest", ImageTypeDocker)
+	assert.NoError(t, err)
+
+	list, err := i.Read(ImageTypeDocker)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vault:latest"}, list)
+}
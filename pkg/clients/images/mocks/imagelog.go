@@ -63,6 +63,20 @@ func (_m *ImageLog) Read(_a0 string) ([]string, error) {
 	return r0, r1
 }
 
+// Remove provides a mock function with given fields: _a0, _a1
+func (_m *ImageLog) Remove(_a0 string, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewImageLog interface {
 	mock.TestingT
 	Cleanup(func())
@@ -1,6 +1,7 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -12,19 +13,31 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"helm.sh/helm/v3/pkg/kube"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
 )
 
 // Kubernetes defines an interface for a Kuberenetes client
 type Kubernetes interface {
 	SetConfig(string) (Kubernetes, error)
 	GetPods(string) (*v1.PodList, error)
+	GetService(name, namespace string) (*v1.Service, error)
 	HealthCheckPods(ctx context.Context, selectors []string, timeout time.Duration) error
-	Apply(files []string, waitUntilReady bool) error
-	Delete(files []string) error
+	WaitForCondition(ctx context.Context, gvr schema.GroupVersionResource, name, namespace, condition string, timeout time.Duration) error
+	Apply(files []string, namespace string, waitUntilReady bool) error
+	Delete(files []string, namespace string) error
+	CreateNamespace(name string) error
+	ExecInPod(namespace, selector, container string, command []string) (string, string, int, error)
 	GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error)
 }
 
@@ -32,6 +45,7 @@ type Kubernetes interface {
 type KubernetesImpl struct {
 	clientset  *kubernetes.Clientset
 	client     corev1.CoreV1Interface
+	restConfig *rest.Config
 	configPath string
 	timeout    time.Duration
 	l          logger.Logger
@@ -87,6 +101,7 @@ func (k *KubernetesImpl) setConfig() error {
 
 	k.clientset = clientset
 	k.client = clientset.CoreV1()
+	k.restConfig = config
 
 	return nil
 }
@@ -110,21 +125,29 @@ func (k *KubernetesImpl) GetPods(selector string) (*v1.PodList, error) {
 	return pl, nil
 }
 
+// GetService returns the service with the given name in the namespace
+func (k *KubernetesImpl) GetService(name, namespace string) (*v1.Service, error) {
+	return k.client.Services(namespace).Get(context.Background(), name, metav1.GetOptions{})
+}
+
 // Apply Kubernetes YAML files at path
+// if namespace is set, all namespaced resources that do not declare a namespace
+// are created in the given namespace, resources that declare a different namespace
+// return an error.
 // if waitUntilReady is true then the client will block until all resources have been created
-func (k *KubernetesImpl) Apply(files []string, waitUntilReady bool) error {
+func (k *KubernetesImpl) Apply(files []string, namespace string, waitUntilReady bool) error {
 	allFiles, err := buildFileList(files)
 	if err != nil {
 		return err
 	}
 
-	s := kube.GetConfig(k.configPath, "default", "default")
+	s := kube.GetConfig(k.configPath, "default", namespaceOrDefault(namespace))
 	kc := kube.New(s)
 
 	// process the files
 	for _, f := range allFiles {
-		k.l.Debug("Applying Kubernetes config", "file", f)
-		err := applyFile(f, waitUntilReady, kc)
+		k.l.Debug("Applying Kubernetes config", "file", f, "namespace", namespace)
+		err := applyFile(f, namespace, waitUntilReady, kc)
 		if err != nil {
 			return err
 		}
@@ -134,13 +157,15 @@ func (k *KubernetesImpl) Apply(files []string, waitUntilReady bool) error {
 }
 
 // Delete Kuberentes YAML files at path
-func (k *KubernetesImpl) Delete(files []string) error {
+// if namespace is set, resources that do not declare a namespace are removed
+// from the given namespace
+func (k *KubernetesImpl) Delete(files []string, namespace string) error {
 	allFiles, err := buildFileList(files)
 	if err != nil {
 		return err
 	}
 
-	s := kube.GetConfig(k.configPath, "default", "default")
+	s := kube.GetConfig(k.configPath, "default", namespaceOrDefault(namespace))
 	kc := kube.New(s)
 
 	// process the files
@@ -156,6 +181,83 @@ func (k *KubernetesImpl) Delete(files []string) error {
 	return nil
 }
 
+// CreateNamespace creates the Kubernetes namespace with the given name,
+// if the namespace already exists no error is returned
+func (k *KubernetesImpl) CreateNamespace(name string) error {
+	ns := &v1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+	}
+
+	_, err := k.client.Namespaces().Create(context.Background(), ns, metav1.CreateOptions{})
+	if err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("unable to create namespace %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ExecInPod executes the given command in the first running pod that matches
+// the label selector in the given namespace. If container is empty the
+// default container for the pod is used.
+// Returns the stdout, stderr and exit code of the command, a non zero exit code
+// does not return an error.
+func (k *KubernetesImpl) ExecInPod(namespace, selector, container string, command []string) (string, string, int, error) {
+	lo := metav1.ListOptions{
+		LabelSelector: selector,
+		FieldSelector: "status.phase=Running",
+	}
+
+	pl, err := k.client.Pods(namespace).List(context.Background(), lo)
+	if err != nil {
+		return "", "", -1, fmt.Errorf("unable to list pods for selector %s: %w", selector, err)
+	}
+
+	if len(pl.Items) < 1 {
+		return "", "", -1, fmt.Errorf("no running pods found in namespace %s for selector %s", namespace, selector)
+	}
+
+	pod := pl.Items[0]
+	k.l.Debug("Executing command in pod", "pod", pod.Name, "namespace", pod.Namespace, "container", container, "command", command)
+
+	req := k.client.RESTClient().
+		Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(pod.Namespace).
+		SubResource("exec").
+		VersionedParams(&v1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(k.restConfig, "POST", req.URL())
+	if err != nil {
+		return "", "", -1, fmt.Errorf("unable to create executor for pod %s: %w", pod.Name, err)
+	}
+
+	stdout := bytes.NewBuffer(nil)
+	stderr := bytes.NewBuffer(nil)
+
+	err = exec.StreamWithContext(context.Background(), remotecommand.StreamOptions{
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+
+	if err != nil {
+		if ee, ok := err.(utilexec.ExitError); ok {
+			return stdout.String(), stderr.String(), ee.ExitStatus(), nil
+		}
+
+		return stdout.String(), stderr.String(), -1, fmt.Errorf("unable to execute command in pod %s: %w", pod.Name, err)
+	}
+
+	return stdout.String(), stderr.String(), 0, nil
+}
+
 // HealthCheckPods uses the given selector to check that all pods are started
 // and running.
 // selectors are checked sequentially
@@ -231,6 +333,121 @@ func (k *KubernetesImpl) healthCheckSingle(ctx context.Context, selector string,
 	return nil
 }
 
+// WaitForCondition blocks until the resource defined by the given group version resource,
+// name and namespace has a status condition of the given type set to "True".
+// namespace can be empty for cluster scoped resources.
+func (k *KubernetesImpl) WaitForCondition(ctx context.Context, gvr schema.GroupVersionResource, name, namespace, condition string, timeout time.Duration) error {
+	dc, err := dynamic.NewForConfig(k.restConfig)
+	if err != nil {
+		return fmt.Errorf("unable to create dynamic client: %w", err)
+	}
+
+	var ri dynamic.ResourceInterface = dc.Resource(gvr)
+	if namespace != "" {
+		ri = dc.Resource(gvr).Namespace(namespace)
+	}
+
+	k.l.Debug("Waiting for condition", "resource", gvr.String(), "name", name, "namespace", namespace, "condition", condition)
+
+	st := time.Now()
+	for {
+		if ctx.Err() != nil {
+			return fmt.Errorf("context cancelled")
+		}
+
+		if time.Since(st) > timeout {
+			return fmt.Errorf("timeout waiting for condition %s on %s %s", condition, gvr.String(), name)
+		}
+
+		obj, err := ri.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			k.l.Debug("Error getting resource, will retry", "resource", gvr.String(), "name", name, "error", err)
+		} else if hasCondition(obj.Object, condition) {
+			k.l.Debug("Condition met", "resource", gvr.String(), "name", name, "condition", condition)
+			return nil
+		} else {
+			k.l.Debug("Condition not met, will retry", "resource", gvr.String(), "name", name, "condition", condition)
+		}
+
+		// backoff
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context cancelled")
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// Condition defines a status condition that must be true on a Kubernetes
+// resource
+type Condition struct {
+	// APIVersion of the resource i.e. "apps/v1"
+	APIVersion string
+	// Resource is the plural name of the resource i.e. "deployments"
+	Resource string
+	// Name of the resource
+	Name string
+	// Namespace of the resource, empty for cluster scoped resources
+	Namespace string
+	// Type of the condition, default "Ready"
+	Type string
+}
+
+// HealthCheck waits until the pods matching the given selectors are running
+// and the given conditions are true. The timeout is shared by all the checks,
+// each check only waits for the time remaining before the deadline.
+func HealthCheck(ctx context.Context, client Kubernetes, pods []string, conditions []Condition, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	if len(pods) > 0 {
+		err := client.HealthCheckPods(ctx, pods, time.Until(deadline))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, c := range conditions {
+		gv, err := schema.ParseGroupVersion(c.APIVersion)
+		if err != nil {
+			return fmt.Errorf("unable to parse api_version %s: %w", c.APIVersion, err)
+		}
+
+		ct := c.Type
+		if ct == "" {
+			ct = "Ready"
+		}
+
+		err = client.WaitForCondition(ctx, gv.WithResource(c.Resource), c.Name, c.Namespace, ct, time.Until(deadline))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasCondition returns true when the status.conditions of the given object
+// contain the condition type with the status "True"
+func hasCondition(obj map[string]interface{}, condition string) bool {
+	conditions, found, err := unstructured.NestedSlice(obj, "status", "conditions")
+	if err != nil || !found {
+		return false
+	}
+
+	for _, c := range conditions {
+		cm, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if cm["type"] == condition && cm["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+
+	return false
+}
+
 func buildFileList(files []string) ([]string, error) {
 	allFiles := make([]string, 0)
 
@@ -263,7 +480,15 @@ func buildFileList(files []string) ([]string, error) {
 	return allFiles, nil
 }
 
-func applyFile(path string, waitUntilReady bool, kc *kube.Client) error {
+func namespaceOrDefault(namespace string) string {
+	if namespace == "" {
+		return "default"
+	}
+
+	return namespace
+}
+
+func applyFile(path string, namespace string, waitUntilReady bool, kc *kube.Client) error {
 	f, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("unable to open file: %w", err)
@@ -275,6 +500,19 @@ func applyFile(path string, waitUntilReady bool, kc *kube.Client) error {
 		return fmt.Errorf("unable to build resources for file %s: %w", path, err)
 	}
 
+	// resources without a namespace have been defaulted to the client namespace,
+	// any resource with a different namespace has explicitly set a conflicting value
+	if namespace != "" {
+		for _, i := range r {
+			if i.Namespaced() && i.Namespace != namespace {
+				return fmt.Errorf(
+					"resource %s/%s in file %s declares namespace %s which conflicts with the configured namespace %s",
+					i.Mapping.GroupVersionKind.Kind, i.Name, path, i.Namespace, namespace,
+				)
+			}
+		}
+	}
+
 	_, err = kc.Create(r)
 	if err != nil {
 		return fmt.Errorf("unable to create resources for file %s: %w", path, err)
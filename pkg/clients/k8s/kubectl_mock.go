@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/mock"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 type MockKubernetes struct {
@@ -30,20 +31,36 @@ func (m *MockKubernetes) GetPods(selector string) (*v1.PodList, error) {
 	return nil, args.Error(1)
 }
 
+func (m *MockKubernetes) GetService(name, namespace string) (*v1.Service, error) {
+	args := m.Called(name, namespace)
+
+	if s, ok := args.Get(0).(*v1.Service); ok {
+		return s, args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
 func (m *MockKubernetes) GetPodLogs(ctx context.Context, podName, nameSpace string) (io.ReadCloser, error) {
 	args := m.Called(ctx, podName, nameSpace)
 	ior := io.NopCloser(bytes.NewBufferString("Running pod ..."))
 	return ior, args.Error(1)
 }
 
-func (m *MockKubernetes) Apply(files []string, waitUntilReady bool) error {
-	args := m.Called(files, waitUntilReady)
+func (m *MockKubernetes) Apply(files []string, namespace string, waitUntilReady bool) error {
+	args := m.Called(files, namespace, waitUntilReady)
 
 	return args.Error(0)
 }
 
-func (m *MockKubernetes) Delete(files []string) error {
-	args := m.Called(files)
+func (m *MockKubernetes) Delete(files []string, namespace string) error {
+	args := m.Called(files, namespace)
+
+	return args.Error(0)
+}
+
+func (m *MockKubernetes) CreateNamespace(name string) error {
+	args := m.Called(name)
 
 	return args.Error(0)
 }
@@ -53,3 +70,15 @@ func (m *MockKubernetes) HealthCheckPods(ctx context.Context, selectors []string
 
 	return args.Error(0)
 }
+
+func (m *MockKubernetes) ExecInPod(namespace, selector, container string, command []string) (string, string, int, error) {
+	args := m.Called(namespace, selector, container, command)
+
+	return args.String(0), args.String(1), args.Int(2), args.Error(3)
+}
+
+func (m *MockKubernetes) WaitForCondition(ctx context.Context, gvr schema.GroupVersionResource, name, namespace, condition string, timeout time.Duration) error {
+	args := m.Called(ctx, gvr, name, namespace, condition, timeout)
+
+	return args.Error(0)
+}
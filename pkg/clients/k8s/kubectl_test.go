@@ -1,7 +1,13 @@
 package k8s
 
 import (
+	"context"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // TODO: implement these tests
@@ -13,3 +19,58 @@ func TestKubernetesSetConfigCompletesWithoutError(t *testing.T) {
 func TestApply(t *testing.T) {
 	t.Skip()
 }
+
+func TestHasConditionReturnsTrueWhenConditionTrue(t *testing.T) {
+	obj := map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Progressing", "status": "False"},
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		},
+	}
+
+	require.True(t, hasCondition(obj, "Ready"))
+	require.False(t, hasCondition(obj, "Progressing"))
+	require.False(t, hasCondition(obj, "Available"))
+}
+
+func TestHasConditionReturnsFalseWhenNoStatus(t *testing.T) {
+	require.False(t, hasCondition(map[string]interface{}{}, "Ready"))
+}
+
+func TestHealthCheckSharesTimeoutBetweenChecks(t *testing.T) {
+	mk := &MockKubernetes{}
+	mk.On("HealthCheckPods", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		time.Sleep(50 * time.Millisecond)
+	}).Return(nil)
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := HealthCheck(
+		context.Background(),
+		mk,
+		[]string{"app=web"},
+		[]Condition{{APIVersion: "apps/v1", Resource: "deployments", Name: "web"}},
+		time.Second,
+	)
+	require.NoError(t, err)
+
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	mk.AssertCalled(t, "WaitForCondition", mock.Anything, gvr, "web", "", "Ready", mock.Anything)
+
+	remaining := mk.Calls[1].Arguments.Get(5).(time.Duration)
+	require.LessOrEqual(t, remaining, 950*time.Millisecond)
+}
+
+func TestHealthCheckReturnsErrorForInvalidAPIVersion(t *testing.T) {
+	mk := &MockKubernetes{}
+
+	err := HealthCheck(
+		context.Background(),
+		mk,
+		nil,
+		[]Condition{{APIVersion: "apps/v1/extra", Resource: "deployments", Name: "web"}},
+		time.Second,
+	)
+	require.ErrorContains(t, err, "unable to parse api_version")
+}
@@ -197,6 +197,11 @@ func (tg *TarGz) Extract(src io.Reader, gziped bool, dst string) error {
 			}
 		// if it's a file create it (with same permission)
 		case tar.TypeReg:
+			// archives do not always contain an entry for the parent directory
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
 			fileToWrite, err := os.OpenFile(target, os.O_CREATE|os.O_RDWR, os.FileMode(header.Mode))
 			if err != nil {
 				return err
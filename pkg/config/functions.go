@@ -1,12 +1,14 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"runtime"
 	"strconv"
 
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"gopkg.in/yaml.v3"
 )
 
 func customHCLFuncJumppad() (string, error) {
@@ -58,3 +60,48 @@ func customHCLFuncExists(path string) (bool, error) {
 
 	return true, nil
 }
+
+// converts a YAML document to JSON so that it can be used with jsondecode
+// i.e. jsondecode(yaml_to_json(file("./config.yaml")))
+func customHCLFuncYAMLToJSON(doc string) (string, error) {
+	var v interface{}
+	err := yaml.Unmarshal([]byte(doc), &v)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse YAML: %s", err)
+	}
+
+	d, err := json.Marshal(normalizeYAML(v))
+	if err != nil {
+		return "", fmt.Errorf("unable to convert YAML to JSON: %s", err)
+	}
+
+	return string(d), nil
+}
+
+// normalizeYAML converts maps with non string keys, which can not be
+// serialized to JSON, into maps with string keys
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = normalizeYAML(val)
+		}
+
+		return t
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for k, val := range t {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(val)
+		}
+
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalizeYAML(val)
+		}
+
+		return t
+	}
+
+	return v
+}
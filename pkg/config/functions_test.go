@@ -22,3 +22,24 @@ func TestExistsTrue(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, true, exists)
 }
+
+func TestYAMLToJSON(t *testing.T) {
+	doc := `
+name: consul
+ports:
+  - 8500
+  - 8501
+env:
+  CONSUL_HTTP_ADDR: http://localhost:8500
+1: numeric
+`
+
+	j, err := customHCLFuncYAMLToJSON(doc)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"consul","ports":[8500,8501],"env":{"CONSUL_HTTP_ADDR":"http://localhost:8500"},"1":"numeric"}`, j)
+}
+
+func TestYAMLToJSONInvalidReturnsError(t *testing.T) {
+	_, err := customHCLFuncYAMLToJSON("name: [consul")
+	require.Error(t, err)
+}
@@ -18,4 +18,13 @@ type Blueprint struct {
 	Tags         []string `hcl:"tags,optional" json:"tags,omitempty"`
 	Summary      string   `hcl:"summary,optional" json:"summary,omitempty"`
 	Description  string   `hcl:"description,optional" json:"description,omitempty"`
+
+	// BrowserWindows is a list of URLs that are opened in the browser once the
+	// blueprint has been applied, windows are opened in the order defined
+	BrowserWindows []string `hcl:"browser_windows,optional" json:"browser_windows,omitempty"`
+
+	// HealthCheckTimeout expressed as a go duration i.e 60s, when set all
+	// resource health checks are re-run after the blueprint has been applied
+	// and `up` fails if they do not pass within the timeout
+	HealthCheckTimeout string `hcl:"health_check_timeout,optional" json:"health_check_timeout,omitempty"`
 }
@@ -59,10 +59,8 @@ func (b *Provider) Create(ctx context.Context) error {
 		"image", fmt.Sprintf("jumppad.dev/localcache/%s:%s", b.config.Meta.Name, tag),
 	)
 
-	force := false
-	if hash != b.config.BuildChecksum {
-		force = true
-	}
+	// only force a rebuild and push when the build context has changed
+	changed := hash != b.config.BuildChecksum
 
 	build := &types.Build{
 		Name:       b.config.Meta.Name,
@@ -72,14 +70,13 @@ func (b *Provider) Create(ctx context.Context) error {
 		Args:       b.config.Container.Args,
 	}
 
-	name, err := b.client.BuildContainer(build, force)
+	name, err := b.client.BuildContainer(build, changed)
 	if err != nil {
 		return fmt.Errorf("unable to build image: %w", err)
 	}
 
 	// set the image to be loaded and continue with the container creation
 	b.config.Image = name
-	b.config.BuildChecksum = hash
 
 	// do we need to copy any files?
 	err = b.copyOutputs()
@@ -102,8 +99,18 @@ func (b *Provider) Create(ctx context.Context) error {
 		}
 	}
 
-	// if we have a registry, push the image
+	// a changed build must be pushed to all registries, an unchanged build
+	// is only pushed to registries that have been added since the last push
+	if changed {
+		b.config.PushedRegistries = nil
+	}
+
 	for _, r := range b.config.Registries {
+		if b.config.hasPushed(r.Name) {
+			b.log.Debug("Build has not changed, skipping push", "ref", b.config.Meta.ID, "tag", r.Name)
+			continue
+		}
+
 		// first tag the image
 		b.log.Debug("Tag image", "ref", b.config.Meta.ID, "name", b.config.Image, "tag", r.Name)
 		err = b.client.TagImage(b.config.Image, r.Name)
@@ -117,8 +124,14 @@ func (b *Provider) Create(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("unable to push image: %w", err)
 		}
+
+		b.config.PushedRegistries = append(b.config.PushedRegistries, r.Name)
 	}
 
+	// only set the checksum once the image has been pushed so that a failed
+	// push is retried on the next run
+	b.config.BuildChecksum = hash
+
 	return nil
 }
 
@@ -180,6 +193,13 @@ func (b *Provider) hasChanged() (bool, error) {
 		return true, nil
 	}
 
+	// registries added since the last build need the image pushing
+	for _, r := range b.config.Registries {
+		if !b.config.hasPushed(r.Name) {
+			return true, nil
+		}
+	}
+
 	return false, nil
 }
 
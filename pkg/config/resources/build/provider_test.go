@@ -10,6 +10,8 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -56,3 +58,82 @@ func TestCreatePushesToRegistry(t *testing.T) {
 	mc.AssertCalled(t, "PushImage", types.Image{Name: "nicholasjackson/fake:latest", Username: "", Password: ""})
 	mc.AssertCalled(t, "PushImage", types.Image{Name: "authed/fake:latest", Username: "test", Password: "password"})
 }
+
+func TestCreateDoesNotPushWhenBuildUnchanged(t *testing.T) {
+	b := &Build{
+		ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "test"}},
+		Container:    BuildContainer{Context: "../../../../examples/build/src"},
+		Registries: []container.Image{
+			container.Image{
+				Name: "nicholasjackson/fake:latest",
+			},
+		},
+	}
+
+	hash, err := utils.HashDir(b.Container.Context)
+	require.NoError(t, err)
+	b.BuildChecksum = hash
+	b.PushedRegistries = []string{"nicholasjackson/fake:latest"}
+
+	p, mc := setupProvider(t, b)
+	mc.On("BuildContainer", mock.Anything, false).Return("buildimage:abcde", nil)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	mc.AssertNotCalled(t, "TagImage", mock.Anything, mock.Anything)
+	mc.AssertNotCalled(t, "PushImage", mock.Anything)
+}
+
+func TestCreatePushesToNewRegistryWhenBuildUnchanged(t *testing.T) {
+	b := &Build{
+		ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "test"}},
+		Container:    BuildContainer{Context: "../../../../examples/build/src"},
+		Registries: []container.Image{
+			container.Image{
+				Name: "nicholasjackson/fake:latest",
+			},
+			container.Image{
+				Name: "nicholasjackson/new:latest",
+			},
+		},
+	}
+
+	hash, err := utils.HashDir(b.Container.Context)
+	require.NoError(t, err)
+	b.BuildChecksum = hash
+	b.PushedRegistries = []string{"nicholasjackson/fake:latest"}
+
+	p, mc := setupProvider(t, b)
+	mc.On("BuildContainer", mock.Anything, false).Return("buildimage:abcde", nil)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.True(t, changed)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	mc.AssertNotCalled(t, "PushImage", types.Image{Name: "nicholasjackson/fake:latest"})
+	mc.AssertCalled(t, "PushImage", types.Image{Name: "nicholasjackson/new:latest"})
+	require.ElementsMatch(t, []string{"nicholasjackson/fake:latest", "nicholasjackson/new:latest"}, b.PushedRegistries)
+}
+
+func TestCreateDoesNotSetChecksumWhenPushFails(t *testing.T) {
+	b := &Build{
+		ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "test"}},
+		Registries: []container.Image{
+			container.Image{
+				Name: "nicholasjackson/fake:latest",
+			},
+		},
+	}
+
+	p, mc := setupProvider(t, b)
+	testutils.RemoveOn(&mc.Mock, "PushImage")
+	mc.On("PushImage", mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	require.Error(t, err)
+	require.Empty(t, b.BuildChecksum)
+}
@@ -32,6 +32,9 @@ type Build struct {
 
 	// Checksum is calculated from the Context files
 	BuildChecksum string `hcl:"build_checksum,optional" json:"build_checksum,omitempty"`
+
+	// PushedRegistries are the registries the current build has been pushed to
+	PushedRegistries []string `hcl:"pushed_registries,optional" json:"pushed_registries,omitempty"`
 }
 
 type BuildContainer struct {
@@ -44,6 +47,18 @@ type BuildContainer struct {
 type Registry struct {
 }
 
+// hasPushed returns true when the current build has been pushed to the
+// registry
+func (b *Build) hasPushed(name string) bool {
+	for _, r := range b.PushedRegistries {
+		if r == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 type Output struct {
 	Source      string `hcl:"source" json:"source"`           // Source file or directory in container
 	Destination string `hcl:"destination" json:"destination"` // Destination for copied file or directory
@@ -72,6 +87,7 @@ func (b *Build) Process() error {
 
 			// add the build checksum
 			b.BuildChecksum = kstate.BuildChecksum
+			b.PushedRegistries = kstate.PushedRegistries
 		}
 	}
 
@@ -0,0 +1,176 @@
+package cert
+
+import (
+	gocrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/jumppad-labs/hclconfig/types"
+)
+
+const (
+	keyTypeRSA   = "rsa"
+	keyTypeECDSA = "ecdsa"
+)
+
+// defaultRSAKeyBits is the size of RSA keys when key_bits is not set
+var defaultRSAKeyBits = 4096
+
+// defaultECDSAKeyBits is the size of ECDSA keys when key_bits is not set,
+// the size selects the P-256 curve
+var defaultECDSAKeyBits = 256
+
+// minRSAKeyBits is the smallest RSA key that can be generated
+var minRSAKeyBits = 2048
+
+// validateKey checks the key type and size for a resource and returns the
+// values with any defaults applied
+func validateKey(r types.Resource, keyType string, bits int) (string, int, error) {
+	switch keyType {
+	case "", keyTypeRSA:
+		if bits == 0 {
+			bits = defaultRSAKeyBits
+		}
+
+		if bits < minRSAKeyBits {
+			return "", 0, fmt.Errorf("invalid key_bits %d for resource %s, rsa keys must be at least %d bits", bits, r.Metadata().ID, minRSAKeyBits)
+		}
+
+		return keyTypeRSA, bits, nil
+	case keyTypeECDSA:
+		if bits == 0 {
+			bits = defaultECDSAKeyBits
+		}
+
+		if _, err := ecdsaCurve(bits); err != nil {
+			return "", 0, fmt.Errorf("invalid key_bits %d for resource %s: %w", bits, r.Metadata().ID, err)
+		}
+
+		return keyTypeECDSA, bits, nil
+	}
+
+	return "", 0, fmt.Errorf("invalid key_type %q for resource %s, key_type must be one of rsa, ecdsa", keyType, r.Metadata().ID)
+}
+
+// generateKey creates a new private key of the given type and size, when the
+// type or size are not set the defaults are used
+func generateKey(keyType string, bits int) (gocrypto.Signer, error) {
+	if keyType == keyTypeECDSA {
+		if bits == 0 {
+			bits = defaultECDSAKeyBits
+		}
+
+		curve, err := ecdsaCurve(bits)
+		if err != nil {
+			return nil, err
+		}
+
+		k, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("unable to generate ecdsa key: %w", err)
+		}
+
+		return k, nil
+	}
+
+	if bits == 0 {
+		bits = defaultRSAKeyBits
+	}
+
+	k, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate rsa key: %w", err)
+	}
+
+	return k, nil
+}
+
+// ecdsaCurve returns the elliptic curve for the key size
+func ecdsaCurve(bits int) (elliptic.Curve, error) {
+	switch bits {
+	case 256:
+		return elliptic.P256(), nil
+	case 384:
+		return elliptic.P384(), nil
+	case 521:
+		return elliptic.P521(), nil
+	}
+
+	return nil, fmt.Errorf("ecdsa keys must be one of 256, 384, or 521 bits")
+}
+
+// privateKeyPEM encodes the private key, RSA keys are encoded as PKCS#1 and
+// ECDSA keys as SEC 1
+func privateKeyPEM(k gocrypto.Signer) ([]byte, error) {
+	switch key := k.(type) {
+	case *rsa.PrivateKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), nil
+	case *ecdsa.PrivateKey:
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode ecdsa key: %w", err)
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), nil
+	}
+
+	return nil, fmt.Errorf("unsupported private key type %T", k)
+}
+
+// publicKeyPEM encodes the public key, RSA keys are encoded as PKCS#1 and
+// ECDSA keys as PKIX
+func publicKeyPEM(k gocrypto.PublicKey) ([]byte, error) {
+	switch key := k.(type) {
+	case *rsa.PublicKey:
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(key)}), nil
+	case *ecdsa.PublicKey:
+		der, err := x509.MarshalPKIXPublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode ecdsa public key: %w", err)
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	}
+
+	return nil, fmt.Errorf("unsupported public key type %T", k)
+}
+
+// readPrivateKey loads a PEM encoded RSA or ECDSA private key from a file
+func readPrivateKey(path string) (gocrypto.Signer, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pb, _ := pem.Decode(d)
+	if pb == nil {
+		return nil, fmt.Errorf("no PEM encoded key found in %s", path)
+	}
+
+	switch pb.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(pb.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(pb.Bytes)
+	case "PRIVATE KEY":
+		k, err := x509.ParsePKCS8PrivateKey(pb.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		s, ok := k.(gocrypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", k)
+		}
+
+		return s, nil
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block type %q", pb.Type)
+}
@@ -2,15 +2,23 @@ package cert
 
 import (
 	"context"
+	gocrypto "crypto"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"net"
+	"net/url"
 	"os"
 	"path"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/jumppad-labs/connector/crypto"
 	htypes "github.com/jumppad-labs/hclconfig/types"
@@ -18,6 +26,10 @@ import (
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/sethvargo/go-retry"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 type CAProvider struct {
@@ -69,33 +81,53 @@ func (p *CAProvider) Create(ctx context.Context) error {
 	publicSSHFile := path.Join(directory, fmt.Sprintf("%s.ssh", p.config.Meta.Name))
 	certificateFile := path.Join(directory, fmt.Sprintf("%s.cert", p.config.Meta.Name))
 
-	k, err := crypto.GenerateKeyPair()
+	k, err := generateKey(p.config.KeyType, p.config.KeyBits)
 	if err != nil {
 		return err
 	}
 
-	ca, err := crypto.GenerateCA(p.config.Meta.Name, k.Private)
+	validFor, err := parseValidFor(p.config.ValidFor)
 	if err != nil {
 		return err
 	}
 
-	err = k.Private.WriteFile(keyFile)
+	tmpl, err := caTemplate(p.config.Meta.Name, validFor)
 	if err != nil {
 		return err
 	}
 
-	err = k.Public.WriteFile(publicKeyFile)
+	ca, err := signCertificate(tmpl, nil, k.Public(), k)
+	if err != nil {
+		return fmt.Errorf("unable to create CA certificate: %w", err)
+	}
+
+	privateKey, err := privateKeyPEM(k)
 	if err != nil {
 		return err
 	}
 
+	publicKey, err := publicKeyPEM(k.Public())
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(keyFile, privateKey, 0400)
+	if err != nil {
+		return fmt.Errorf("unable to write key to path %s: %w", keyFile, err)
+	}
+
+	err = os.WriteFile(publicKeyFile, publicKey, 0400)
+	if err != nil {
+		return fmt.Errorf("unable to write key to path %s: %w", publicKeyFile, err)
+	}
+
 	err = ca.WriteFile(certificateFile)
 	if err != nil {
 		return err
 	}
 
 	// output the public ssh key
-	ssh, err := publicPEMtoOpenSSH(k.Public.PEMBlock())
+	ssh, err := publicPEMtoOpenSSH(publicKey)
 	if err != nil {
 		return err
 	}
@@ -117,14 +149,14 @@ func (p *CAProvider) Create(ctx context.Context) error {
 		Path:      keyFile,
 		Directory: directory,
 		Filename:  fmt.Sprintf("%s.key", p.config.Meta.Name),
-		Contents:  k.Private.String(),
+		Contents:  string(privateKey),
 	}
 
 	p.config.PublicKeyPEM = File{
 		Path:      publicKeyFile,
 		Directory: directory,
 		Filename:  fmt.Sprintf("%s.pub", p.config.Meta.Name),
-		Contents:  k.Public.String(),
+		Contents:  string(publicKey),
 	}
 
 	p.config.PublicKeySSH = File{
@@ -184,6 +216,8 @@ func (p *LeafProvider) Create(ctx context.Context) error {
 	pubkeyFile := path.Join(directory, fmt.Sprintf("%s-leaf.pub", p.config.Meta.Name))
 	pubsshFile := path.Join(directory, fmt.Sprintf("%s-leaf.ssh", p.config.Meta.Name))
 	certFile := path.Join(directory, fmt.Sprintf("%s-leaf.cert", p.config.Meta.Name))
+	pfxFile := path.Join(directory, fmt.Sprintf("%s-leaf.p12", p.config.Meta.Name))
+	chainFile := path.Join(directory, fmt.Sprintf("%s-leaf-chain.cert", p.config.Meta.Name))
 
 	ca := &crypto.X509{}
 	err := ca.ReadFile(p.config.CACert)
@@ -191,24 +225,43 @@ func (p *LeafProvider) Create(ctx context.Context) error {
 		return retry.RetryableError(fmt.Errorf("unable to read root certificate %s: %w", p.config.CACert, err))
 	}
 
-	rk := crypto.NewKeyPair()
-	err = rk.Private.ReadFile(p.config.CAKey)
+	rk, err := readPrivateKey(p.config.CAKey)
 	if err != nil {
 		return retry.RetryableError(fmt.Errorf("unable to read root key %s: %w", p.config.CAKey, err))
 	}
 
-	k, err := crypto.GenerateKeyPair()
+	k, err := generateKey(p.config.KeyType, p.config.KeyBits)
 	if err != nil {
 		return err
 	}
 
-	lc, err := crypto.GenerateLeaf(p.config.Meta.Name, p.config.IPAddresses, p.config.DNSNames, ca, rk.Private, k.Private)
+	validFor, err := parseValidFor(p.config.ValidFor)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := leafTemplate(p.config, validFor)
+	if err != nil {
+		return err
+	}
+
+	lc, err := signCertificate(tmpl, ca.Certificate, k.Public(), rk)
+	if err != nil {
+		return fmt.Errorf("unable to create leaf certificate: %w", err)
+	}
+
+	privateKey, err := privateKeyPEM(k)
+	if err != nil {
+		return err
+	}
+
+	publicKey, err := publicKeyPEM(k.Public())
 	if err != nil {
 		return err
 	}
 
 	// output the public ssh key
-	ssh, err := publicPEMtoOpenSSH(k.Public.PEMBlock())
+	ssh, err := publicPEMtoOpenSSH(publicKey)
 	if err != nil {
 		return err
 	}
@@ -220,14 +273,14 @@ func (p *LeafProvider) Create(ctx context.Context) error {
 	}
 
 	// Save the keys
-	err = k.Private.WriteFile(keyFile)
+	err = os.WriteFile(keyFile, privateKey, 0400)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to write key to path %s: %w", keyFile, err)
 	}
 
-	err = k.Public.WriteFile(pubkeyFile)
+	err = os.WriteFile(pubkeyFile, publicKey, 0400)
 	if err != nil {
-		return err
+		return fmt.Errorf("unable to write key to path %s: %w", pubkeyFile, err)
 	}
 
 	err = os.WriteFile(pubsshFile, []byte(ssh), os.ModePerm)
@@ -247,7 +300,7 @@ func (p *LeafProvider) Create(ctx context.Context) error {
 		Path:      pubkeyFile,
 		Directory: directory,
 		Filename:  fmt.Sprintf("%s-leaf.pub", p.config.Meta.Name),
-		Contents:  k.Public.String(),
+		Contents:  string(publicKey),
 	}
 
 	p.config.Cert = File{
@@ -261,10 +314,57 @@ func (p *LeafProvider) Create(ctx context.Context) error {
 		Path:      keyFile,
 		Directory: directory,
 		Filename:  fmt.Sprintf("%s-leaf.key", p.config.Meta.Name),
-		Contents:  k.Private.String(),
+		Contents:  string(privateKey),
+	}
+
+	// optionally write the leaf followed by the CA, the CA file is used as is
+	// so that any intermediate certificates it contains are included
+	p.config.ChainCert = File{}
+	if p.config.IncludeChain {
+		caPEM, err := os.ReadFile(p.config.CACert)
+		if err != nil {
+			return fmt.Errorf("unable to read root certificate %s: %w", p.config.CACert, err)
+		}
+
+		chain := lc.String() + string(caPEM)
+
+		err = os.WriteFile(chainFile, []byte(chain), 0400)
+		if err != nil {
+			return fmt.Errorf("unable to write certificate chain to path %s: %w", chainFile, err)
+		}
+
+		p.config.ChainCert = File{
+			Path:      chainFile,
+			Directory: directory,
+			Filename:  fmt.Sprintf("%s-leaf-chain.cert", p.config.Meta.Name),
+			Contents:  chain,
+		}
+	}
+
+	// optionally write a PKCS#12 bundle for services that need a keystore
+	p.config.PFX = File{}
+	if p.config.PFXPassword == "" {
+		return nil
+	}
+
+	pfx, err := pkcs12.Modern.Encode(k, lc.Certificate, []*x509.Certificate{ca.Certificate}, p.config.PFXPassword)
+	if err != nil {
+		return fmt.Errorf("unable to create PKCS#12 bundle: %w", err)
 	}
 
-	return err
+	err = os.WriteFile(pfxFile, pfx, 0600)
+	if err != nil {
+		return err
+	}
+
+	p.config.PFX = File{
+		Path:      pfxFile,
+		Directory: directory,
+		Filename:  fmt.Sprintf("%s-leaf.p12", p.config.Meta.Name),
+		Contents:  base64.StdEncoding.EncodeToString(pfx),
+	}
+
+	return nil
 }
 
 func (p *LeafProvider) Destroy(ctx context.Context, force bool) error {
@@ -286,6 +386,25 @@ func (p *LeafProvider) Refresh(ctx context.Context) error {
 
 	p.log.Debug("Refresh Leaf Certificate", "ref", p.config.Meta.Name)
 
+	expiring, err := p.expiring()
+	if err != nil {
+		return err
+	}
+
+	if expiring {
+		p.log.Info("Leaf Certificate is due to expire, regenerating", "ref", p.config.Meta.ID)
+
+		// the existing files are read only and must be removed before they
+		// can be written again
+		for _, f := range []File{p.config.Cert, p.config.PrivateKey, p.config.PublicKeyPEM, p.config.PublicKeySSH, p.config.PFX, p.config.ChainCert} {
+			if f.Path != "" {
+				os.Remove(f.Path)
+			}
+		}
+
+		return p.Create(ctx)
+	}
+
 	return nil
 }
 
@@ -296,14 +415,178 @@ func (p *LeafProvider) Lookup() ([]string, error) {
 func (p *LeafProvider) Changed() (bool, error) {
 	p.log.Debug("Checking changes Leaf Certificate", "ref", p.config.Meta.Name)
 
+	expiring, err := p.expiring()
+	if err != nil {
+		return false, err
+	}
+
+	if expiring {
+		p.log.Debug("Leaf Certificate is due to expire, requires refresh", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
 	return false, nil
 }
 
+// expiring returns true when the certificate has expired, expires within the
+// renew_before duration, or no longer exists
+func (p *LeafProvider) expiring() (bool, error) {
+	if p.config.Cert.Path == "" {
+		return false, nil
+	}
+
+	renewBefore := time.Duration(0)
+	if p.config.RenewBefore != "" {
+		d, err := time.ParseDuration(p.config.RenewBefore)
+		if err != nil {
+			return false, fmt.Errorf("unable to parse renew_before %q for %s: %w", p.config.RenewBefore, p.config.Meta.ID, err)
+		}
+
+		renewBefore = d
+	}
+
+	lc := &crypto.X509{}
+	err := lc.ReadFile(p.config.Cert.Path)
+	if err != nil {
+		p.log.Debug("Unable to read Leaf Certificate, requires regeneration", "ref", p.config.Meta.ID, "error", err)
+		return true, nil
+	}
+
+	return time.Now().Add(renewBefore).After(lc.NotAfter), nil
+}
+
+// defaultValidFor is the duration certificates are valid for when valid_for
+// is not set
+var defaultValidFor = 8760 * time.Hour
+
+// parseValidFor returns the duration a certificate is valid for
+func parseValidFor(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultValidFor, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse valid_for %q: %w", value, err)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("valid_for must be greater than zero, got %s", value)
+	}
+
+	return d, nil
+}
+
+// certTemplate returns a template for a certificate with a random serial
+// number that is valid from now for the given duration
+func certTemplate(name string, validFor time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Jumppad"}, CommonName: name},
+		NotBefore:             now,
+		NotAfter:              now.Add(validFor),
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+// caTemplate returns a template for a CA certificate
+func caTemplate(name string, validFor time.Duration) (*x509.Certificate, error) {
+	tmpl, err := certTemplate(name, validFor)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.IsCA = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+
+	return tmpl, nil
+}
+
+// leafTemplate returns a template for a leaf certificate that can be used
+// for server and client authentication
+func leafTemplate(c *CertificateLeaf, validFor time.Duration) (*x509.Certificate, error) {
+	tmpl, err := certTemplate(c.Meta.Name, validFor)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+
+	if len(c.Organization) > 0 {
+		tmpl.Subject.Organization = c.Organization
+	}
+
+	for _, ip := range c.IPAddresses {
+		tmpl.IPAddresses = append(tmpl.IPAddresses, net.ParseIP(ip))
+	}
+
+	tmpl.DNSNames = sanitizeDNSNames(c.DNSNames)
+	tmpl.EmailAddresses = c.EmailAddresses
+
+	// generate a random spiffe id
+	spiffe, _ := url.Parse(fmt.Sprintf("spiffe://jumppad.dev/private/%d", time.Now().UnixNano()))
+	tmpl.URIs = []*url.URL{spiffe}
+
+	for _, u := range c.URIs {
+		pu, err := url.Parse(u)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse uri %q: %w", u, err)
+		}
+
+		tmpl.URIs = append(tmpl.URIs, pu)
+	}
+
+	return tmpl, nil
+}
+
+// signCertificate creates a certificate from the template for the public key
+// signed by the signer, when parent is nil the certificate is self signed
+func signCertificate(tmpl *x509.Certificate, parent *x509.Certificate, pub gocrypto.PublicKey, signer gocrypto.Signer) (*crypto.X509, error) {
+	if parent == nil {
+		parent = tmpl
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, signer)
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crypto.X509{Certificate: cert}, nil
+}
+
+// sanitizeDNSNames removes unicode characters from DNS names
+func sanitizeDNSNames(dnsNames []string) []string {
+	names := []string{}
+
+	for _, name := range dnsNames {
+		t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+		result, _, _ := transform.String(t, name)
+		names = append(names, result)
+	}
+
+	return names
+}
+
 func destroy(module, name, output string, log logger.Logger) error {
 	keyFile := path.Join(output, fmt.Sprintf("%s.key", name))
 	pubkeyFile := path.Join(output, fmt.Sprintf("%s.pub", name))
 	pubsshFile := path.Join(output, fmt.Sprintf("%s.ssh", name))
 	certFile := path.Join(output, fmt.Sprintf("%s.cert", name))
+	pfxFile := path.Join(output, fmt.Sprintf("%s.p12", name))
+	chainFile := path.Join(output, fmt.Sprintf("%s-chain.cert", name))
 
 	err := os.Remove(keyFile)
 	if err != nil {
@@ -325,6 +608,22 @@ func destroy(module, name, output string, log logger.Logger) error {
 		log.Debug("Unable to remove certificate", "ref", name, "error", err)
 	}
 
+	// only leaf certificates with a pfx_password have a bundle
+	if _, err := os.Stat(pfxFile); err == nil {
+		err = os.Remove(pfxFile)
+		if err != nil {
+			log.Debug("Unable to remove PKCS#12 bundle", "ref", name, "error", err)
+		}
+	}
+
+	// only leaf certificates with include_chain have a chain
+	if _, err := os.Stat(chainFile); err == nil {
+		err = os.Remove(chainFile)
+		if err != nil {
+			log.Debug("Unable to remove certificate chain", "ref", name, "error", err)
+		}
+	}
+
 	// if there is a module directory and it is empty, remove it
 	if module != "" {
 		directory := strings.Replace(module, ".", "_", -1)
@@ -364,21 +663,27 @@ func publicPEMtoOpenSSH(pemBytes []byte) (string, error) {
 		return "", errors.New("PEM block contains more than just public key")
 	}
 
-	// Confirm we got the PUBLIC KEY block type
-	if pemBlock.Type != "RSA PUBLIC KEY" {
+	var key any
+	var err error
+
+	// RSA keys are encoded as PKCS#1 and ECDSA keys as PKIX
+	switch pemBlock.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(pemBlock.Bytes)
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(pemBlock.Bytes)
+	default:
 		return "", fmt.Errorf("ssh: unsupported key type %q", pemBlock.Type)
 	}
 
-	// Convert to rsa
-	rsaPubKey, err := x509.ParsePKCS1PublicKey(pemBlock.Bytes)
 	if err != nil {
 		return "", fmt.Errorf("x509.parse pki public key: %w", err)
 	}
 
 	// Generate the ssh public key
-	pub, err := ssh.NewPublicKey(rsaPubKey)
+	pub, err := ssh.NewPublicKey(key)
 	if err != nil {
-		return "", fmt.Errorf("new ssh public key from pem converted to rsa: %w", err)
+		return "", fmt.Errorf("new ssh public key from pem: %w", err)
 	}
 
 	// Encode to store to file
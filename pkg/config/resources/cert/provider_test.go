@@ -2,13 +2,23 @@ package cert
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"fmt"
+	"os"
 	"path"
 	"testing"
+	"time"
 
+	"github.com/jumppad-labs/connector/crypto"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 func setupCACert(t *testing.T) (*CertificateCA, *CAProvider) {
@@ -94,3 +104,256 @@ func TestDestroyCleansUpLeaf(t *testing.T) {
 	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.pub", c.Meta.Name)))
 	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.ssh", c.Meta.Name)))
 }
+
+func TestLeafChangedReturnsFalseWhenNotExpiring(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.RenewBefore = "24h"
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestLeafChangedReturnsTrueWhenWithinRenewBefore(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	// certificates are valid for less than 10000h
+	c.RenewBefore = "10000h"
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.True(t, changed)
+}
+
+func TestLeafRefreshRegeneratesWhenExpiring(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	old := c.Cert.Contents
+	c.RenewBefore = "10000h"
+
+	err = p.Refresh(context.Background())
+	require.NoError(t, err)
+	require.NotEqual(t, old, c.Cert.Contents)
+}
+
+func TestGeneratesCAWithValidFor(t *testing.T) {
+	c, p := setupCACert(t)
+	c.ValidFor = "2h"
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	ca := &crypto.X509{}
+	err = ca.ReadFile(c.Cert.Path)
+	require.NoError(t, err)
+
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), ca.NotAfter, time.Minute)
+	require.True(t, ca.IsCA)
+}
+
+func TestGeneratesLeafWithValidForSignedByCA(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.ValidFor = "2h"
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	lc := &crypto.X509{}
+	err = lc.ReadFile(c.Cert.Path)
+	require.NoError(t, err)
+
+	ca := &crypto.X509{}
+	err = ca.ReadFile(c.CACert)
+	require.NoError(t, err)
+
+	require.WithinDuration(t, time.Now().Add(2*time.Hour), lc.NotAfter, time.Minute)
+	require.Equal(t, []string{"localhost"}, lc.DNSNames)
+	require.NoError(t, lc.CheckSignatureFrom(ca.Certificate))
+}
+
+func TestGeneratesLeafWithURIAndEmailSANs(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.URIs = []string{"spiffe://cluster.local/ns/default/sa/web"}
+	c.EmailAddresses = []string{"admin@jumppad.dev"}
+	c.Organization = []string{"Acme"}
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	lc := &crypto.X509{}
+	err = lc.ReadFile(c.Cert.Path)
+	require.NoError(t, err)
+
+	uris := []string{}
+	for _, u := range lc.URIs {
+		uris = append(uris, u.String())
+	}
+
+	require.Contains(t, uris, "spiffe://cluster.local/ns/default/sa/web")
+	require.Equal(t, []string{"admin@jumppad.dev"}, lc.EmailAddresses)
+	require.Equal(t, []string{"Acme"}, lc.Subject.Organization)
+}
+
+func TestGeneratesLeafWithDefaultValidFor(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	lc := &crypto.X509{}
+	err = lc.ReadFile(c.Cert.Path)
+	require.NoError(t, err)
+
+	require.WithinDuration(t, time.Now().Add(defaultValidFor), lc.NotAfter, time.Minute)
+}
+
+func TestGeneratesLeafPFXWhenPasswordSet(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.PFXPassword = "secret"
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.p12", c.Meta.Name)))
+
+	d, err := os.ReadFile(c.PFX.Path)
+	require.NoError(t, err)
+
+	_, cert, cas, err := pkcs12.DecodeChain(d, "secret")
+	require.NoError(t, err)
+	require.Equal(t, "test", cert.Subject.CommonName)
+	require.Len(t, cas, 1)
+}
+
+func TestDoesNotGenerateLeafPFXWhenPasswordEmpty(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf.p12", c.Meta.Name)))
+	require.Empty(t, c.PFX.Path)
+}
+
+func TestGeneratesECDSACAAndLeaf(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := &CertificateCA{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "test"}}}
+	ca.Output = dir
+	ca.KeyType = "ecdsa"
+	ca.KeyBits = 384
+
+	p := &CAProvider{ca, logger.NewTestLogger(t)}
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	cl := &CertificateLeaf{ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "test"}}}
+	cl.Output = dir
+	cl.DNSNames = []string{"localhost"}
+	cl.CACert = ca.Cert.Path
+	cl.CAKey = ca.PrivateKey.Path
+	cl.KeyType = "ecdsa"
+
+	pl := &LeafProvider{cl, logger.NewTestLogger(t)}
+	err = pl.Create(context.Background())
+	require.NoError(t, err)
+
+	k, err := readPrivateKey(cl.PrivateKey.Path)
+	require.NoError(t, err)
+
+	ek, ok := k.(*ecdsa.PrivateKey)
+	require.True(t, ok)
+	require.Equal(t, elliptic.P256(), ek.Curve)
+
+	lc := &crypto.X509{}
+	err = lc.ReadFile(cl.Cert.Path)
+	require.NoError(t, err)
+
+	cc := &crypto.X509{}
+	err = cc.ReadFile(ca.Cert.Path)
+	require.NoError(t, err)
+
+	require.Equal(t, x509.ECDSA, lc.PublicKeyAlgorithm)
+	require.NoError(t, lc.CheckSignatureFrom(cc.Certificate))
+}
+
+func TestPublicPEMtoOpenSSHConvertsECDSAKeys(t *testing.T) {
+	k, err := generateKey("ecdsa", 256)
+	require.NoError(t, err)
+
+	pub, err := publicKeyPEM(k.Public())
+	require.NoError(t, err)
+
+	s, err := publicPEMtoOpenSSH(pub)
+	require.NoError(t, err)
+
+	key, err := ssh.ParsePublicKey(mustDecodeBase64(t, s))
+	require.NoError(t, err)
+	require.Equal(t, "ecdsa-sha2-nistp256", key.Type())
+}
+
+func mustDecodeBase64(t *testing.T, s string) []byte {
+	d, err := base64.StdEncoding.DecodeString(s)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestGeneratesLeafChainWhenIncludeChainSet(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.IncludeChain = true
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.FileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf-chain.cert", c.Meta.Name)))
+
+	d, err := os.ReadFile(c.ChainCert.Path)
+	require.NoError(t, err)
+
+	leaf, rest := pem.Decode(d)
+	require.NotNil(t, leaf)
+
+	ca, _ := pem.Decode(rest)
+	require.NotNil(t, ca)
+
+	lc, err := x509.ParseCertificate(leaf.Bytes)
+	require.NoError(t, err)
+	require.Equal(t, "test", lc.Subject.CommonName)
+
+	cc, err := x509.ParseCertificate(ca.Bytes)
+	require.NoError(t, err)
+	require.True(t, cc.IsCA)
+}
+
+func TestDoesNotGenerateLeafChainWhenIncludeChainNotSet(t *testing.T) {
+	c, p := setupLeafCert(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf-chain.cert", c.Meta.Name)))
+	require.Empty(t, c.ChainCert.Path)
+}
+
+func TestDestroyCleansUpLeafChain(t *testing.T) {
+	c, p := setupLeafCert(t)
+	c.IncludeChain = true
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	require.NoFileExists(t, path.Join(c.Output, fmt.Sprintf("%s-leaf-chain.cert", c.Meta.Name)))
+}
@@ -1,6 +1,11 @@
 package cert
 
 import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"time"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
@@ -16,6 +21,18 @@ type CertificateCA struct {
 	// Output directory to write the certificate and key too
 	Output string `hcl:"output" json:"output"`
 
+	// ValidFor is the duration the certificate is valid for i.e. 720h,
+	// defaults to 8760h
+	ValidFor string `hcl:"valid_for,optional" json:"valid_for,omitempty"`
+
+	// KeyType is the type of key to generate, either rsa or ecdsa, defaults
+	// to rsa
+	KeyType string `hcl:"key_type,optional" json:"key_type,omitempty"`
+
+	// KeyBits is the size of the key, defaults to 4096 for rsa keys and 256
+	// for ecdsa keys where the size selects the P-256, P-384, or P-521 curve
+	KeyBits int `hcl:"key_bits,optional" json:"key_bits,omitempty"`
+
 	// output parameters
 
 	// Key is the value related to the certificate key
@@ -36,6 +53,16 @@ func (c *CertificateCA) Process() error {
 	c.PublicKeyPEM = File{}
 	c.Cert = File{}
 
+	err := validateValidFor(c, c.ValidFor)
+	if err != nil {
+		return err
+	}
+
+	c.KeyType, c.KeyBits, err = validateKey(c, c.KeyType, c.KeyBits)
+	if err != nil {
+		return err
+	}
+
 	// do we have an existing resource in the state?
 	// if so we need to set any computed resources for dependents
 	cfg, err := config.LoadState()
@@ -67,8 +94,45 @@ type CertificateLeaf struct {
 	IPAddresses []string `hcl:"ip_addresses,optional" json:"ip_addresses,omitempty"` // ip addresses to add to the cert
 	DNSNames    []string `hcl:"dns_names,optional" json:"dns_names,omitempty"`       // DNS names to add to the cert
 
+	// URIs are added to the subject alternative names of the certificate
+	// i.e. spiffe://cluster.local/ns/default/sa/web
+	URIs []string `hcl:"uris,optional" json:"uris,omitempty"`
+
+	// EmailAddresses are added to the subject alternative names of the
+	// certificate
+	EmailAddresses []string `hcl:"email_addresses,optional" json:"email_addresses,omitempty"`
+
+	// Organization sets the organization of the certificate subject, defaults
+	// to Jumppad
+	Organization []string `hcl:"organization,optional" json:"organization,omitempty"`
+
 	Output string `hcl:"output" json:"output"` // output location for the certificate
 
+	// ValidFor is the duration the certificate is valid for i.e. 720h,
+	// defaults to 8760h
+	ValidFor string `hcl:"valid_for,optional" json:"valid_for,omitempty"`
+
+	// RenewBefore regenerates the certificate when it expires within the given
+	// duration i.e. 72h, when not set the certificate is regenerated once it
+	// has expired
+	RenewBefore string `hcl:"renew_before,optional" json:"renew_before,omitempty"`
+
+	// PFXPassword is the password for a PKCS#12 bundle containing the
+	// certificate, key, and CA, the bundle is only written when set
+	PFXPassword string `hcl:"pfx_password,optional" json:"-"`
+
+	// IncludeChain writes a certificate chain containing the leaf certificate
+	// followed by the CA certificate
+	IncludeChain bool `hcl:"include_chain,optional" json:"include_chain,omitempty"`
+
+	// KeyType is the type of key to generate, either rsa or ecdsa, defaults
+	// to rsa
+	KeyType string `hcl:"key_type,optional" json:"key_type,omitempty"`
+
+	// KeyBits is the size of the key, defaults to 4096 for rsa keys and 256
+	// for ecdsa keys where the size selects the P-256, P-384, or P-521 curve
+	KeyBits int `hcl:"key_bits,optional" json:"key_bits,omitempty"`
+
 	// output parameters
 
 	// Key is the value related to the certificate key
@@ -80,6 +144,13 @@ type CertificateLeaf struct {
 
 	// Cert is the value related to the certificate
 	Cert File `hcl:"certificate,optional" json:"certificate"`
+
+	// PFX is the PKCS#12 bundle, the contents are base64 encoded
+	PFX File `hcl:"pfx,optional" json:"pfx"`
+
+	// ChainCert is the leaf certificate followed by the CA certificate, only
+	// set when include_chain is true
+	ChainCert File `hcl:"chain_certificate,optional" json:"chain_certificate"`
 }
 
 func (c *CertificateLeaf) Process() error {
@@ -90,6 +161,35 @@ func (c *CertificateLeaf) Process() error {
 	c.PublicKeySSH = File{}
 	c.PublicKeyPEM = File{}
 	c.Cert = File{}
+	c.PFX = File{}
+	c.ChainCert = File{}
+
+	err := config.ValidateDuration(c, "renew_before", c.RenewBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range c.URIs {
+		if pu, err := url.Parse(u); err != nil || pu.Scheme == "" {
+			return fmt.Errorf("invalid uri %q for resource %s, uris must be absolute i.e. spiffe://cluster.local/web", u, c.Meta.ID)
+		}
+	}
+
+	for _, e := range c.EmailAddresses {
+		if _, err := mail.ParseAddress(e); err != nil {
+			return fmt.Errorf("invalid email address %q for resource %s: %w", e, c.Meta.ID, err)
+		}
+	}
+
+	err = validateValidFor(c, c.ValidFor)
+	if err != nil {
+		return err
+	}
+
+	c.KeyType, c.KeyBits, err = validateKey(c, c.KeyType, c.KeyBits)
+	if err != nil {
+		return err
+	}
 
 	// do we have an existing resource in the state?
 	// if so we need to set any computed resources for dependents
@@ -103,12 +203,28 @@ func (c *CertificateLeaf) Process() error {
 			c.PublicKeySSH = kstate.PublicKeySSH
 			c.PublicKeyPEM = kstate.PublicKeyPEM
 			c.Cert = kstate.Cert
+			c.PFX = kstate.PFX
+			c.ChainCert = kstate.ChainCert
 		}
 	}
 
 	return nil
 }
 
+// validateValidFor checks that valid_for is a positive duration
+func validateValidFor(r types.Resource, value string) error {
+	err := config.ValidateDuration(r, "valid_for", value)
+	if err != nil {
+		return err
+	}
+
+	if d, _ := time.ParseDuration(value); value != "" && d <= 0 {
+		return fmt.Errorf(`invalid duration "%s" for valid_for in resource %s, the duration must be greater than zero`, value, r.Metadata().ID)
+	}
+
+	return nil
+}
+
 type File struct {
 	Filename  string `hcl:"filename,optional" json:"filename"`
 	Directory string `hcl:"directory,optional" json:"directory"`
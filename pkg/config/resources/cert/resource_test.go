@@ -139,3 +139,86 @@ func TestCertLeafLoadsValuesFromState(t *testing.T) {
 	require.Equal(t, "private.key", ca.PrivateKey.Filename)
 	require.Equal(t, "cert.pem", ca.Cert.Filename)
 }
+
+func TestCertLeafProcessReturnsErrorWhenValidForNotPositive(t *testing.T) {
+	c := &CertificateLeaf{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_leaf.test", File: "./"}},
+		ValidFor:     "-1h",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "must be greater than zero")
+}
+
+func TestCertCAProcessReturnsErrorWhenValidForInvalid(t *testing.T) {
+	c := &CertificateCA{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_ca.test", File: "./"}},
+		ValidFor:     "a year",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid duration")
+}
+
+func TestCertCAProcessSetsDefaultKey(t *testing.T) {
+	c := &CertificateCA{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_ca.test", File: "./"}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, "rsa", c.KeyType)
+	require.Equal(t, 4096, c.KeyBits)
+}
+
+func TestCertLeafProcessSetsDefaultECDSAKeyBits(t *testing.T) {
+	c := &CertificateLeaf{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_leaf.test", File: "./"}},
+		KeyType:      "ecdsa",
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, 256, c.KeyBits)
+}
+
+func TestCertCAProcessReturnsErrorWhenKeyTypeInvalid(t *testing.T) {
+	c := &CertificateCA{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_ca.test", File: "./"}},
+		KeyType:      "dsa",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid key_type")
+}
+
+func TestCertLeafProcessReturnsErrorWhenKeyBitsInvalid(t *testing.T) {
+	c := &CertificateLeaf{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_leaf.test", File: "./"}},
+		KeyType:      "ecdsa",
+		KeyBits:      2048,
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid key_bits")
+}
+
+func TestCertLeafProcessReturnsErrorWhenURIInvalid(t *testing.T) {
+	c := &CertificateLeaf{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_leaf.test", File: "./"}},
+		URIs:         []string{"cluster.local/web"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid uri")
+}
+
+func TestCertLeafProcessReturnsErrorWhenEmailInvalid(t *testing.T) {
+	c := &CertificateLeaf{
+		ResourceBase:   types.ResourceBase{Meta: types.Meta{ID: "resource.certificate_leaf.test", File: "./"}},
+		EmailAddresses: []string{"not an email"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid email address")
+}
@@ -8,6 +8,7 @@ func (i Image) ToClientImage() types.Image {
 		Name:     i.Name,
 		Username: i.Username,
 		Password: i.Password,
+		Platform: i.Platform,
 	}
 }
 
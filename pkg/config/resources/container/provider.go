@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/docker/go-units"
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
@@ -19,6 +22,30 @@ import (
 	sdk "github.com/jumppad-labs/plugin-sdk"
 )
 
+// livenessTimeout is the timeout for health checks that are run against
+// existing containers when restart_on_failure is enabled
+var livenessTimeout = 5 * time.Second
+
+// exitOutputLines is the number of lines of output that are returned when a
+// task container exits with a non-zero exit code
+var exitOutputLines = 20
+
+// defaultWaitForTimeout is the time to wait for a dependency when the
+// wait_for block does not set a timeout
+var defaultWaitForTimeout = 60 * time.Second
+
+// postCreateTimeout is the maximum time in seconds that each post_create
+// command can run for
+var postCreateTimeout = 300
+
+// preDestroyTimeout is the maximum time in seconds that each pre_destroy
+// command can run for, a hung command must not block the teardown
+var preDestroyTimeout = 30
+
+// redacted replaces the values of secret environment variables in the output
+// written to the log
+const redacted = "********"
+
 // Container is a provider for creating and destroying Docker containers
 type Provider struct {
 	config     *Container
@@ -44,8 +71,13 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 		co.ResourceBase = cs.ResourceBase
 		co.ContainerName = cs.ContainerName
 
+		shared, err := cs.sharedVolumes()
+		if err != nil {
+			return err
+		}
+
 		co.Networks = []NetworkAttachment{{ID: cs.Target.ContainerName}}
-		co.Volumes = cs.Volumes
+		co.Volumes = append(shared, cs.Volumes...)
 		co.Command = cs.Command
 		co.Entrypoint = cs.Entrypoint
 		co.Labels = cs.Labels
@@ -148,22 +180,36 @@ func (c *Provider) Changed() (bool, error) {
 		return true, nil
 	}
 
+	// when restart on failure is enabled, a failing health check marks the
+	// container as changed so that it is recreated
+	if c.config.HealthCheck != nil && c.config.HealthCheck.RestartOnFailure && !c.checkLiveness() {
+		c.log.Info("Container is unhealthy, restarting", "ref", c.config.Meta.ID)
+		return true, nil
+	}
+
 	return false, nil
 }
 
 func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	// set the fqdn
 	fqdn := utils.FQDN(c.config.Meta.Name, c.config.Meta.Module, c.config.Meta.Type)
-	c.config.ContainerName = fqdn
+	c.config.ContainerName = containerName(fqdn, c.config.DisableDNS)
+
+	// check that any devices exist on the host before creating the container
+	err := checkDevices(c.config.Devices)
+	if err != nil {
+		return err
+	}
 
 	// pull any images needed for this container
 	img := types.Image{
 		Name:     c.config.Image.Name,
 		Username: c.config.Image.Username,
 		Password: c.config.Image.Password,
+		Platform: c.config.Image.Platform,
 	}
 
-	err := c.client.PullImage(img, false)
+	err = c.client.PullImage(img, false)
 	if err != nil {
 		c.log.Error("Error pulling container image", "ref", c.config.Meta.ID, "image", c.config.Image.Name)
 
@@ -181,15 +227,16 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	c.config.Image.ID = id
 
 	new := types.Container{
-		Name:            fqdn,
+		Name:            c.config.ContainerName,
 		Image:           &img,
 		Entrypoint:      c.config.Entrypoint,
 		Command:         c.config.Command,
-		Environment:     c.config.Environment,
+		Environment:     c.environment(),
 		Labels:          c.config.Labels,
 		DNS:             c.config.DNS,
 		Privileged:      c.config.Privileged,
 		MaxRestartCount: c.config.MaxRestartCount,
+		NetworkMode:     c.config.NetworkMode,
 	}
 
 	for _, v := range c.config.Networks {
@@ -214,6 +261,13 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		})
 	}
 
+	secrets, err := c.writeSecrets(fqdn)
+	if err != nil {
+		return fmt.Errorf("unable to write secrets for container: %w", err)
+	}
+
+	new.Volumes = append(new.Volumes, secrets...)
+
 	for _, p := range c.config.Ports {
 		new.Ports = append(new.Ports, types.Port{
 			Local:         p.Local,
@@ -250,10 +304,38 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 			new.Resources.GPU = &types.GPU{
 				Driver:    c.config.Resources.GPU.Driver,
 				DeviceIDs: c.config.Resources.GPU.DeviceIDs,
+				Count:     c.config.Resources.GPU.Count,
 			}
 		}
 	}
 
+	for _, d := range c.config.Devices {
+		new.Devices = append(new.Devices, types.Device{
+			Host:        d.Host,
+			Container:   d.Container,
+			Permissions: d.Permissions,
+		})
+	}
+
+	for _, u := range c.config.Ulimits {
+		new.Ulimits = append(new.Ulimits, types.Ulimit{
+			Name: u.Name,
+			Soft: u.Soft,
+			Hard: u.Hard,
+		})
+	}
+
+	if c.config.ShmSize != "" {
+		size, err := units.RAMInBytes(c.config.ShmSize)
+		if err != nil {
+			return fmt.Errorf("invalid shm_size %q: %w", c.config.ShmSize, err)
+		}
+
+		new.ShmSize = size
+	}
+
+	new.StopSignal = c.config.StopSignal
+
 	if c.config.RunAs != nil {
 		new.RunAs = &types.User{
 			User:  c.config.RunAs.User,
@@ -261,6 +343,12 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		}
 	}
 
+	// wait for any dependencies to be ready before starting
+	err = c.waitForDependencies()
+	if err != nil {
+		return err
+	}
+
 	id, err = c.client.CreateContainer(&new)
 	if err != nil {
 		c.log.Error("Unable to create container", "ref", c.config.Meta.ID, "error", err)
@@ -271,7 +359,8 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	dc := c.client.ListNetworks(id)
 	for _, n := range dc {
 		for i, net := range c.config.Networks {
-			if net.ID == n.ID {
+			// external networks are referenced by name and have no id
+			if net.ID == n.ID || net.ID == n.Name {
 				// remove the netmask
 				ip, _, _ := strings.Cut(n.IPAddress, "/")
 
@@ -282,19 +371,176 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 		}
 	}
 
-	if c.config.HealthCheck == nil {
-		return nil
+	// task containers are not long running, wait for the container to
+	// complete rather than running health checks
+	if !sidecar && c.config.WaitForExit {
+		return c.waitForExit(id)
+	}
+
+	if c.config.HealthCheck != nil {
+		if c.config.HealthCheck.Timeout == "" {
+			c.config.HealthCheck.Timeout = "30s"
+		}
+
+		timeout, err := time.ParseDuration(c.config.HealthCheck.Timeout)
+		if err != nil {
+			return fmt.Errorf("unable to parse duration for the health check timeout, please specify as a go duration i.e 30s, 1m: %s", err)
+		}
+
+		err = c.runHealthChecks(ctx, id, timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	// run any setup commands now that the container is healthy
+	return c.runPostCreate(ctx, id)
+}
+
+// waitForDependencies blocks until the addresses in the wait_for blocks are
+// accepting tcp connections
+func (c *Provider) waitForDependencies() error {
+	for _, w := range c.config.WaitFor {
+		timeout := defaultWaitForTimeout
+		if w.Timeout != "" {
+			d, err := time.ParseDuration(w.Timeout)
+			if err != nil {
+				return fmt.Errorf("unable to parse wait_for timeout %q for %s: %w", w.Timeout, c.config.Meta.ID, err)
+			}
+
+			timeout = d
+		}
+
+		c.log.Info("Waiting for dependency", "ref", c.config.Meta.ID, "address", w.Address)
+
+		err := c.httpClient.HealthCheckTCP(w.Address, timeout)
+		if err != nil {
+			return fmt.Errorf("timeout waiting for %s to accept connections before creating %s: %w", w.Address, c.config.Meta.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// runPostCreate runs the post_create commands in the container in order, the
+// output is written to the log and an error is returned when a command exits
+// with a non-zero exit code
+func (c *Provider) runPostCreate(ctx context.Context, id string) error {
+	for _, command := range c.config.PostCreate {
+		if ctx.Err() != nil {
+			c.log.Debug("Context cancelled, skipping post create commands", "ref", c.config.Meta.ID)
+			return nil
+		}
+
+		c.log.Info("Running post create command", "ref", c.config.Meta.ID, "command", c.redact(command))
+
+		code, err := c.client.ExecuteCommand(id, []string{"sh", "-c", command}, nil, "", "", "", postCreateTimeout, c.redactWriter(c.log.StandardWriter()))
+		if err != nil {
+			return fmt.Errorf("post_create command %q for %s failed with exit code %d: %w", c.redact(command), c.config.Meta.ID, code, err)
+		}
+	}
+
+	return nil
+}
+
+// runPreDestroy runs the pre_destroy commands in the container in order before
+// it is removed, failures are logged and do not stop the container from
+// being destroyed
+func (c *Provider) runPreDestroy(id string) {
+	for _, command := range c.config.PreDestroy {
+		c.log.Info("Running pre destroy command", "ref", c.config.Meta.ID, "command", c.redact(command))
+
+		code, err := c.client.ExecuteCommand(id, []string{"sh", "-c", command}, nil, "", "", "", preDestroyTimeout, c.redactWriter(c.log.StandardWriter()))
+		if err != nil {
+			c.log.Warn("Pre destroy command failed", "ref", c.config.Meta.ID, "command", c.redact(command), "exit_code", code, "error", err)
+		}
+	}
+}
+
+// environment returns the environment variables for the container including
+// any secret environment variables
+func (c *Provider) environment() map[string]string {
+	if len(c.config.SecretEnvironment) == 0 {
+		return c.config.Environment
+	}
+
+	env := map[string]string{}
+	for k, v := range c.config.Environment {
+		env[k] = v
+	}
+
+	for k, v := range c.config.SecretEnvironment {
+		env[k] = v
+	}
+
+	return env
+}
+
+// redact replaces the values of any secret environment variables in s
+func (c *Provider) redact(s string) string {
+	for _, v := range c.config.SecretEnvironment {
+		if v != "" {
+			s = strings.ReplaceAll(s, v, redacted)
+		}
 	}
 
-	if c.config.HealthCheck.Timeout == "" {
-		c.config.HealthCheck.Timeout = "30s"
+	return s
+}
+
+// redactWriter returns a writer that redacts the values of any secret
+// environment variables before writing to w
+func (c *Provider) redactWriter(w io.Writer) io.Writer {
+	if len(c.config.SecretEnvironment) == 0 {
+		return w
 	}
 
-	timeout, err := time.ParseDuration(c.config.HealthCheck.Timeout)
+	return &redactingWriter{w: w, redact: c.redact}
+}
+
+type redactingWriter struct {
+	w      io.Writer
+	redact func(string) string
+}
+
+func (r *redactingWriter) Write(p []byte) (int, error) {
+	_, err := r.w.Write([]byte(r.redact(string(p))))
 	if err != nil {
-		return fmt.Errorf("unable to parse duration for the health check timeout, please specify as a go duration i.e 30s, 1m: %s", err)
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// waitForExit waits for a task container to exit, when the exit code is not
+// zero an error containing the tail of the container output is returned
+func (c *Provider) waitForExit(id string) error {
+	c.log.Debug("Waiting for container to exit", "ref", c.config.Meta.ID)
+
+	code, err := c.client.WaitForContainerExit(id)
+	if err != nil {
+		return err
+	}
+
+	c.config.ExitCode = code
+
+	if code == 0 {
+		return nil
+	}
+
+	output := ""
+	rc, err := c.client.ContainerLogs(id, true, true)
+	if err == nil {
+		defer rc.Close()
+
+		d, _ := io.ReadAll(rc)
+		output = c.redact(utils.TailLines(string(d), exitOutputLines))
 	}
 
+	return fmt.Errorf("container %s exited with code %d, output:\n%s", c.config.Meta.ID, code, output)
+}
+
+// runHealthChecks executes all the health checks defined for the container
+func (c *Provider) runHealthChecks(ctx context.Context, id string, timeout time.Duration) error {
 	// execute tcp health checks
 	for _, hc := range c.config.HealthCheck.TCP {
 		err := c.httpClient.HealthCheckTCP(
@@ -333,6 +579,24 @@ func (c *Provider) internalCreate(ctx context.Context, sidecar bool) error {
 	return nil
 }
 
+// checkLiveness runs the health checks for a container that has already been created,
+// returns false when the container does not exist or any of the checks fail
+func (c *Provider) checkLiveness() bool {
+	ids, err := c.Lookup()
+	if err != nil || len(ids) == 0 {
+		c.log.Warn("Unable to find container for health check", "ref", c.config.Meta.ID)
+		return false
+	}
+
+	err = c.runHealthChecks(context.Background(), ids[0], livenessTimeout)
+	if err != nil {
+		c.log.Warn("Container health check failed", "ref", c.config.Meta.ID, "error", err)
+		return false
+	}
+
+	return true
+}
+
 func (c *Provider) runExecHealthCheck(ctx context.Context, id string, command []string, script string, exitCode int, timeout time.Duration) error {
 	if len(script) > 0 {
 		// write the script to a temp file
@@ -399,6 +663,8 @@ func (c *Provider) internalDestroy(ctx context.Context, force bool) error {
 
 	if len(ids) > 0 {
 		for _, id := range ids {
+			c.runPreDestroy(id)
+
 			err := c.client.RemoveContainer(id, force)
 
 			if err != nil {
@@ -407,5 +673,122 @@ func (c *Provider) internalDestroy(ctx context.Context, force bool) error {
 		}
 	}
 
+	if len(c.config.Secrets) > 0 {
+		err = removeSecrets(utils.SecretsDir(utils.FQDN(c.config.Meta.Name, c.config.Meta.Module, c.config.Meta.Type)))
+		if err != nil {
+			c.log.Warn("Unable to remove secrets for container", "ref", c.config.Meta.ID, "error", err)
+		}
+	}
+
 	return nil
 }
+
+// writeSecrets writes the secrets for the container to files with the
+// permissions of the secret, by default only readable by the current user,
+// and returns read only volumes for the files.
+// secret values are never logged.
+func (c *Provider) writeSecrets(fqdn string) ([]types.Volume, error) {
+	if len(c.config.Secrets) == 0 {
+		return nil, nil
+	}
+
+	dir := utils.SecretsDir(fqdn)
+	volumes := []types.Volume{}
+
+	for i, s := range c.config.Secrets {
+		data := []byte(s.Value)
+		if s.Source != "" {
+			var err error
+			data, err = os.ReadFile(s.Source)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read secret %s: %w", s.Source, err)
+			}
+		}
+
+		perms := s.Permissions
+		if perms == "" {
+			perms = defaultSecretPermissions
+		}
+
+		mode, err := strconv.ParseUint(perms, 8, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid permissions %s for secret %s: %w", perms, s.Destination, err)
+		}
+
+		fn := path.Join(dir, fmt.Sprintf("secret_%d", i))
+
+		// remove any existing file as it will be read only
+		os.Remove(fn)
+
+		err = os.WriteFile(fn, data, 0600)
+		if err != nil {
+			return nil, fmt.Errorf("unable to write secret for %s: %w", s.Destination, err)
+		}
+
+		// set the mode after writing so it is not affected by the umask
+		err = os.Chmod(fn, os.FileMode(mode))
+		if err != nil {
+			return nil, fmt.Errorf("unable to set permissions for secret %s: %w", s.Destination, err)
+		}
+
+		c.log.Debug("Written secret", "ref", c.config.Meta.ID, "destination", s.Destination)
+
+		volumes = append(volumes, types.Volume{
+			Source:      fn,
+			Destination: s.Destination,
+			Type:        "bind",
+			ReadOnly:    true,
+		})
+	}
+
+	return volumes, nil
+}
+
+// removeSecrets overwrites the contents of all secrets in the given
+// folder before removing them
+func removeSecrets(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		fn := path.Join(dir, f.Name())
+
+		fi, err := f.Info()
+		if err == nil && os.Chmod(fn, 0600) == nil {
+			os.WriteFile(fn, make([]byte, fi.Size()), 0600)
+		}
+
+		err = os.Remove(fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// checkDevices returns an error when any of the devices do not exist on the
+// host
+func checkDevices(devices []Device) error {
+	for _, d := range devices {
+		_, err := os.Stat(d.Host)
+		if err != nil {
+			return fmt.Errorf("device %s does not exist on the host: %w", d.Host, err)
+		}
+	}
+
+	return nil
+}
+
+// containerName returns the name for the docker container, the fqdn is
+// resolvable by other containers on the same network so when dns is disabled
+// a name outside of the jumppad.dev domain is used
+func containerName(fqdn string, disableDNS bool) string {
+	if !disableDNS {
+		return fqdn
+	}
+
+	return strings.ReplaceAll(fqdn, ".", "-")
+}
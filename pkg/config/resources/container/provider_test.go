@@ -1,8 +1,13 @@
 package container
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"runtime"
 	"testing"
 	"time"
 
@@ -12,6 +17,7 @@ import (
 	hmocks "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/mock"
 	assert "github.com/stretchr/testify/require"
@@ -53,6 +59,127 @@ func TestContainerCreatesSuccessfully(t *testing.T) {
 	hc.AssertNotCalled(t, "HealthCheckHTTP", mock.Anything, mock.Anything)
 }
 
+func TestContainerCreatePassesDevices(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+
+	dev := t.TempDir() + "/device"
+	os.WriteFile(dev, []byte(""), 0644)
+	cc.Devices = []Device{{Host: dev, Container: "/dev/fuse", Permissions: "rw"}}
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, []ctypes.Device{{Host: dev, Container: "/dev/fuse", Permissions: "rw"}}, params.Devices)
+}
+
+func TestContainerCreateReturnsErrorWhenDeviceMissing(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Devices = []Device{{Host: "/dev/does-not-exist", Container: "/dev/fuse", Permissions: "rwm"}}
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.ErrorContains(t, err, "device /dev/does-not-exist does not exist on the host")
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
+func TestContainerRunsPostCreateCommands(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.PostCreate = []string{"createdb app", "psql -f /fixtures.sql"}
+
+	md.On("ExecuteCommand", "12345", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	calls := testutils.GetCalls(&md.Mock, "ExecuteCommand")
+	assert.Len(t, calls, 2)
+	assert.Equal(t, []string{"sh", "-c", "createdb app"}, calls[0].Arguments[1])
+	assert.Equal(t, []string{"sh", "-c", "psql -f /fixtures.sql"}, calls[1].Arguments[1])
+}
+
+func TestContainerReturnsErrorWhenPostCreateCommandFails(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.PostCreate = []string{"createdb app", "psql -f /fixtures.sql"}
+
+	md.On("ExecuteCommand", "12345", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, fmt.Errorf("container exec failed with exit code 1"))
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.ErrorContains(t, err, "exit code 1")
+	md.AssertNumberOfCalls(t, "ExecuteCommand", 1)
+}
+
+func TestContainerSetsSecretEnvironment(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Environment = map[string]string{"USER": "admin"}
+	cc.SecretEnvironment = map[string]string{"PASSWORD": "s3cr3t"}
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, map[string]string{"USER": "admin", "PASSWORD": "s3cr3t"}, ac.Environment)
+	assert.Equal(t, map[string]string{"USER": "admin"}, cc.Environment)
+
+	d, err := json.Marshal(cc)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(d), "s3cr3t")
+}
+
+func TestContainerRedactsSecretEnvironmentFromPostCreateErrors(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.SecretEnvironment = map[string]string{"PASSWORD": "s3cr3t"}
+	cc.PostCreate = []string{"createdb -p s3cr3t app"}
+
+	md.On("ExecuteCommand", "12345", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, fmt.Errorf("container exec failed with exit code 1"))
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.ErrorContains(t, err, "createdb -p ******** app")
+	assert.NotContains(t, err.Error(), "s3cr3t")
+}
+
+func TestContainerWaitsForDependenciesBeforeCreating(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.WaitFor = []WaitFor{
+		{Address: "db.container.local.jmpd.in:5432"},
+		{Address: "cache.container.local.jmpd.in:6379", Timeout: "10s"},
+	}
+
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	hc.AssertCalled(t, "HealthCheckTCP", "db.container.local.jmpd.in:5432", defaultWaitForTimeout)
+	hc.AssertCalled(t, "HealthCheckTCP", "cache.container.local.jmpd.in:6379", 10*time.Second)
+}
+
+func TestContainerDoesNotCreateWhenDependencyNotReady(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.WaitFor = []WaitFor{{Address: "db.container.local.jmpd.in:5432"}}
+
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("timeout"))
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.ErrorContains(t, err, "db.container.local.jmpd.in:5432")
+	md.AssertNotCalled(t, "CreateContainer", mock.Anything)
+}
+
 func TestContainerSidecarCreatesContainerSuccessfully(t *testing.T) {
 	c, md, hc := setupContainerTests(t)
 	testutils.RemoveOn(&md.Mock, "CreateContainer")
@@ -187,6 +314,48 @@ func TestContainerRunsExecChecksWithScript(t *testing.T) {
 	md.AssertNumberOfCalls(t, "ExecuteCommand", 1)
 }
 
+func TestContainerWaitsForExitWhenTask(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.WaitForExit = true
+
+	md.On("WaitForContainerExit", "12345").Once().Return(0, nil)
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertCalled(t, "WaitForContainerExit", "12345")
+	md.AssertNotCalled(t, "ContainerLogs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContainerTaskReturnsErrorWithOutputWhenExitCodeNotZero(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.WaitForExit = true
+
+	md.On("WaitForContainerExit", "12345").Once().Return(3, nil)
+	md.On("ContainerLogs", "12345", true, true).Once().Return(
+		io.NopCloser(bytes.NewBufferString("starting\nmigration failed\n")),
+		nil,
+	)
+
+	c := Provider{cc, nil, md, hc, logger.NewTestLogger(t)}
+
+	err := c.Create(context.Background())
+	assert.ErrorContains(t, err, "exited with code 3")
+	assert.ErrorContains(t, err, "migration failed")
+	assert.Equal(t, 3, cc.ExitCode)
+}
+
+func TestContainerTaskWithRestartCountReturnsError(t *testing.T) {
+	cc, _, _ := setupContainerTests(t)
+	cc.WaitForExit = true
+	cc.MaxRestartCount = 3
+
+	err := cc.Process()
+	assert.Error(t, err)
+}
+
 func TestContainerDoesNOTCreateWhenPullImageFail(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
@@ -216,6 +385,39 @@ func TestContainerDestroysCorrectlyWhenContainerExists(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestContainerRunsPreDestroyCommandsBeforeRemoving(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.PreDestroy = []string{"consul leave", "redis-cli save"}
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, preDestroyTimeout, mock.Anything).Return(0, nil)
+	md.On("RemoveContainer", "abc", false).Return(nil)
+
+	err := p.Destroy(context.Background(), false)
+	assert.NoError(t, err)
+
+	calls := testutils.GetCalls(&md.Mock, "ExecuteCommand")
+	assert.Len(t, calls, 2)
+	assert.Equal(t, []string{"sh", "-c", "consul leave"}, calls[0].Arguments[1])
+	assert.Equal(t, []string{"sh", "-c", "redis-cli save"}, calls[1].Arguments[1])
+	md.AssertCalled(t, "RemoveContainer", "abc", false)
+}
+
+func TestContainerDestroysWhenPreDestroyCommandFails(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.PreDestroy = []string{"consul leave"}
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, fmt.Errorf("container exec failed with exit code 1"))
+	md.On("RemoveContainer", "abc", false).Return(nil)
+
+	err := p.Destroy(context.Background(), false)
+	assert.NoError(t, err)
+	md.AssertCalled(t, "RemoveContainer", "abc", false)
+}
+
 func TestContainerDoesNotDestroysWhenNotExists(t *testing.T) {
 	cc, md, hc := setupContainerTests(t)
 	cc.Networks = []NetworkAttachment{NetworkAttachment{Name: "cloud"}}
@@ -275,3 +477,185 @@ func TestContainerAddsResources(t *testing.T) {
 	assert.Equal(t, "nvidia", ac.Resources.GPU.Driver)
 	assert.Equal(t, []string{"1"}, ac.Resources.GPU.DeviceIDs)
 }
+
+func TestContainerSetsShmSize(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.ShmSize = "1g"
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+	p.Create(context.Background())
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, int64(1024*1024*1024), ac.ShmSize)
+}
+
+func TestContainerSetsStopSignal(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.StopSignal = "SIGQUIT"
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+	p.Create(context.Background())
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, "SIGQUIT", ac.StopSignal)
+}
+
+func TestContainerUsesFQDNForContainerName(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, "tests.container.local.jmpd.in", ac.Name)
+	assert.Equal(t, "tests.container.local.jmpd.in", cc.ContainerName)
+}
+
+func TestContainerDoesNotUseFQDNForContainerNameWhenDNSDisabled(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.DisableDNS = true
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Equal(t, "tests-container-local-jmpd-in", ac.Name)
+	assert.Equal(t, "tests-container-local-jmpd-in", cc.ContainerName)
+}
+
+func TestContainerChangedWhenRestartOnFailureAndHealthCheckFails(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Image.ID = "myimage"
+	cc.ContainerName = "tests.container.local.jmpd.in"
+	cc.HealthCheck = &healthcheck.HealthCheckContainer{
+		Timeout:          "30s",
+		RestartOnFailure: true,
+		TCP: []healthcheck.HealthCheckTCP{{
+			Address: "localhost:8500",
+		}},
+	}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	changed, err := p.Changed()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	hc.AssertCalled(t, "HealthCheckTCP", "localhost:8500", livenessTimeout)
+}
+
+func TestContainerNotChangedWhenRestartOnFailureAndHealthCheckPasses(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Image.ID = "myimage"
+	cc.ContainerName = "tests.container.local.jmpd.in"
+	cc.HealthCheck = &healthcheck.HealthCheckContainer{
+		Timeout:          "30s",
+		RestartOnFailure: true,
+		TCP: []healthcheck.HealthCheckTCP{{
+			Address: "localhost:8500",
+		}},
+	}
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	hc.On("HealthCheckTCP", mock.Anything, mock.Anything).Return(nil)
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	changed, err := p.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestContainerChangedDoesNotRunHealthChecksWithoutRestartOnFailure(t *testing.T) {
+	cc, md, hc := setupContainerTests(t)
+	cc.Image.ID = "myimage"
+	cc.HealthCheck = &healthcheck.HealthCheckContainer{
+		Timeout: "30s",
+		TCP: []healthcheck.HealthCheckTCP{{
+			Address: "localhost:8500",
+		}},
+	}
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	changed, err := p.Changed()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	hc.AssertNotCalled(t, "HealthCheckTCP", mock.Anything, mock.Anything)
+}
+
+func TestContainerWritesSecretsAndMountsReadOnly(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	cc, md, hc := setupContainerTests(t)
+	cc.Secrets = []Secret{{Value: "supersecret", Destination: "/run/secrets/token"}}
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.Len(t, ac.Volumes, 1)
+	assert.Equal(t, "/run/secrets/token", ac.Volumes[0].Destination)
+	assert.True(t, ac.Volumes[0].ReadOnly)
+
+	d, err := os.ReadFile(ac.Volumes[0].Source)
+	assert.NoError(t, err)
+	assert.Equal(t, "supersecret", string(d))
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(ac.Volumes[0].Source)
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0400), fi.Mode().Perm())
+	}
+}
+
+func TestContainerWritesSecretsWithPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	cc, md, hc := setupContainerTests(t)
+	cc.Secrets = []Secret{{Value: "supersecret", Destination: "/run/secrets/token", Permissions: "0444"}}
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+
+	fi, err := os.Stat(ac.Volumes[0].Source)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0444), fi.Mode().Perm())
+}
+
+func TestContainerDestroyRemovesSecrets(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	cc, md, hc := setupContainerTests(t)
+	cc.Secrets = []Secret{{Value: "supersecret", Destination: "/run/secrets/token"}}
+
+	p := Provider{config: cc, client: md, httpClient: hc, log: logger.NewTestLogger(t)}
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.On("FindContainerIDs", cc.ContainerName).Return([]string{"abc"}, nil)
+	md.On("RemoveContainer", "abc", false).Return(nil)
+
+	err = p.Destroy(context.Background(), false)
+	assert.NoError(t, err)
+
+	ac := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*ctypes.Container)
+	assert.NoFileExists(t, ac.Volumes[0].Source)
+}
@@ -1,12 +1,16 @@
 package container
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/docker/go-units"
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/moby/sys/signal"
 )
 
 // TypeContainer is the resource string for a Container resource
@@ -24,13 +28,60 @@ type Container struct {
 	Environment     map[string]string   `hcl:"environment,optional" json:"environment,omitempty"` // Environment variables to set when starting the container
 	Labels          map[string]string   `hcl:"labels,optional" json:"labels,omitempty"`           // Labels to set on the container
 	Volumes         []Volume            `hcl:"volume,block" json:"volumes,omitempty"`             // Volumes to attach to the container
+	Secrets         []Secret            `hcl:"secret,block" json:"secrets,omitempty"`             // Secrets to mount into the container
 	Ports           []Port              `hcl:"port,block" json:"ports,omitempty"`                 // Ports to expose
 	PortRanges      []PortRange         `hcl:"port_range,block" json:"port_ranges,omitempty"`     // Range of ports to expose
 	DNS             []string            `hcl:"dns,optional" json:"dns,omitempty"`                 // Add custom DNS servers to the container
 	Privileged      bool                `hcl:"privileged,optional" json:"privileged,omitempty"`   // Run the container in privileged mode?
 	Capabilities    *Capabilities       `hcl:"capabilities,block" json:"capabilities,omitempty"`  // Capabilities to add or drop from the container
+	Devices         []Device            `hcl:"device,block" json:"devices,omitempty"`             // Devices on the host to map into the container
+	Ulimits         []Ulimit            `hcl:"ulimit,block" json:"ulimits,omitempty"`             // Resource limits for the processes in the container
 	MaxRestartCount int                 `hcl:"max_restart_count,optional" json:"max_restart_count,omitempty"`
 
+	// SecretEnvironment are environment variables that are set in the
+	// container but are not written to the state, values are redacted from
+	// the output jumppad logs, i.e. a generated password
+	SecretEnvironment map[string]string `hcl:"secret_environment,optional" json:"-"`
+
+	// ShmSize is the size of /dev/shm in the container i.e. 512m, 1g, when
+	// not set the Docker default of 64m is used
+	ShmSize string `hcl:"shm_size,optional" json:"shm_size,omitempty"`
+
+	// StopSignal is the signal sent to the container when it is stopped i.e.
+	// SIGINT, SIGQUIT, when not set the image default, usually SIGTERM, is used
+	StopSignal string `hcl:"stop_signal,optional" json:"stop_signal,omitempty"`
+
+	// WaitForExit runs the container as a one-shot task, creation waits for the
+	// container to exit and fails when the exit code is not zero
+	WaitForExit bool `hcl:"wait_for_exit,optional" json:"wait_for_exit,omitempty"`
+
+	// WaitFor blocks the creation of the container until the addresses of its
+	// dependencies are accepting connections
+	WaitFor []WaitFor `hcl:"wait_for,block" json:"wait_for,omitempty"`
+
+	// PostCreate is a list of commands that are run in the container once it
+	// has started and any health checks have passed, creation fails when a
+	// command exits with a non-zero exit code
+	PostCreate []string `hcl:"post_create,optional" json:"post_create,omitempty"`
+
+	// PreDestroy is a list of commands that are run in the container before
+	// it is stopped, failures are logged but do not prevent the container
+	// from being destroyed
+	PreDestroy []string `hcl:"pre_destroy,optional" json:"pre_destroy,omitempty"`
+
+	// DisableDNS stops the fully qualified domain name being used as the
+	// container name, other containers can no longer resolve the container
+	// using <name>.container.local.jmpd.in and must use the ip address or a
+	// network alias. Ingress, checks and anything else that targets the
+	// container by its fully qualified domain name will not be able to reach it
+	DisableDNS bool `hcl:"disable_dns,optional" json:"disable_dns,omitempty"`
+
+	// NetworkMode sets the network mode for the container, host shares the
+	// network of the host, none disables networking and container:<name>
+	// shares the network of another container. Can not be used with network
+	// blocks
+	NetworkMode string `hcl:"network_mode,optional" json:"network_mode,omitempty"`
+
 	// resource constraints
 	Resources *Resources `hcl:"resources,block" json:"resources,omitempty"` // resource constraints for the container
 
@@ -45,6 +96,9 @@ type Container struct {
 	// ContainerName is the fully qualified domain name for the container, this can be used
 	// to access the container from other sources
 	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty"`
+
+	// ExitCode is the exit code of the container when wait_for_exit is set
+	ExitCode int `hcl:"exit_code,optional" json:"exit_code,omitempty"`
 }
 
 type User struct {
@@ -80,8 +134,36 @@ type Resources struct {
 }
 
 type GPU struct {
-	Driver    string   `hcl:"driver" json:"driver"`         // driver to use for the GPU
-	DeviceIDs []string `hcl:"device_ids" json:"device_ids"` // device ids to use for the GPU
+	Driver    string   `hcl:"driver,optional" json:"driver"`                   // driver to use for the GPU, defaults to nvidia
+	DeviceIDs []string `hcl:"device_ids,optional" json:"device_ids,omitempty"` // device ids to use for the GPU
+	Count     int      `hcl:"count,optional" json:"count,omitempty"`           // number of GPUs to allocate, -1 allocates all GPUs
+}
+
+// defaultGPUDriver is the device driver used when a GPU does not set a driver
+const defaultGPUDriver = "nvidia"
+
+// WaitFor defines a tcp address that must accept connections before the
+// container is created
+type WaitFor struct {
+	Address string `hcl:"address" json:"address"`                    // address to dial i.e. db.container.local.jmpd.in:5432
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"` // maximum time to wait, defaults to 60s
+}
+
+// Device defines a device on the host that is mapped into the container, this
+// allows access to hardware such as /dev/fuse without running the container
+// in privileged mode
+type Device struct {
+	Host        string `hcl:"host" json:"host"`                                  // path of the device on the host
+	Container   string `hcl:"container,optional" json:"container,omitempty"`     // path of the device in the container, defaults to the host path
+	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"` // cgroup permissions for the device, any combination of r, w, and m, defaults to rwm
+}
+
+// Ulimit sets a resource limit such as nofile or memlock for the processes in
+// the container, a value of -1 is unlimited
+type Ulimit struct {
+	Name string `hcl:"name" json:"name"`                    // name of the limit i.e. nofile
+	Soft int64  `hcl:"soft" json:"soft"`                    // soft limit
+	Hard int64  `hcl:"hard,optional" json:"hard,omitempty"` // hard limit, defaults to the soft limit
 }
 
 type Capabilities struct {
@@ -102,12 +184,142 @@ type Volume struct {
 
 type Volumes []Volume
 
+// Secret defines a secret that is written to a file with restricted permissions
+// and mounted read only into the container. The secret can either be read from
+// a file on the local machine or set from a value. Values are never written to
+// the state.
+type Secret struct {
+	Source      string `hcl:"source,optional" json:"source,omitempty"` // path to a file on the local machine containing the secret
+	Value       string `hcl:"value,optional" json:"-"`                 // value of the secret
+	Destination string `hcl:"destination" json:"destination"`          // path to mount the secret inside the container
+	// Permissions for the secret file, e.g. 0444, defaults to 0400. The file is
+	// owned by the user running jumppad, when the container runs as a
+	// different user the permissions must allow the user to read the file.
+	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"`
+}
+
+// defaultSecretPermissions are the file permissions for secrets that do not
+// set permissions
+const defaultSecretPermissions = "0400"
+
 func (c *Container) Process() error {
+	if c.HealthCheck != nil {
+		if err := config.ValidateDuration(c, "health_check.timeout", c.HealthCheck.Timeout); err != nil {
+			return err
+		}
+	}
+
+	for _, w := range c.WaitFor {
+		if err := config.ValidateDuration(c, "wait_for.timeout", w.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if c.WaitForExit && c.MaxRestartCount != 0 {
+		return fmt.Errorf("max_restart_count can not be set for resource %s when wait_for_exit is enabled", c.Meta.ID)
+	}
+
+	if c.WaitForExit && len(c.PostCreate) > 0 {
+		return fmt.Errorf("post_create can not be set for resource %s when wait_for_exit is enabled", c.Meta.ID)
+	}
+
+	if c.WaitForExit && len(c.PreDestroy) > 0 {
+		return fmt.Errorf("pre_destroy can not be set for resource %s when wait_for_exit is enabled", c.Meta.ID)
+	}
+
+	if c.NetworkMode != "" {
+		if len(c.Networks) > 0 {
+			return fmt.Errorf("network_mode can not be set for resource %s when network blocks are specified", c.Meta.ID)
+		}
+
+		if c.NetworkMode != "host" && c.NetworkMode != "none" && !strings.HasPrefix(c.NetworkMode, "container:") {
+			return fmt.Errorf("invalid network_mode %q for resource %s, valid values are host, none, or container:<name>", c.NetworkMode, c.Meta.ID)
+		}
+	}
+
+	for i, d := range c.Devices {
+		if d.Container == "" {
+			c.Devices[i].Container = d.Host
+		}
+
+		if d.Permissions == "" {
+			c.Devices[i].Permissions = "rwm"
+		}
+
+		if strings.Trim(c.Devices[i].Permissions, "rwm") != "" {
+			return fmt.Errorf("invalid permissions %q for device %s in resource %s, permissions must be a combination of r, w, and m", d.Permissions, d.Host, c.Meta.ID)
+		}
+	}
+
+	for k := range c.SecretEnvironment {
+		if _, ok := c.Environment[k]; ok {
+			return fmt.Errorf("environment variable %s for resource %s can not be set in both environment and secret_environment", k, c.Meta.ID)
+		}
+	}
+
+	if c.ShmSize != "" {
+		if _, err := units.RAMInBytes(c.ShmSize); err != nil {
+			return fmt.Errorf("invalid shm_size %q for resource %s: %w", c.ShmSize, c.Meta.ID, err)
+		}
+	}
+
+	if c.StopSignal != "" {
+		if _, err := signal.ParseSignal(c.StopSignal); err != nil {
+			return fmt.Errorf("invalid stop_signal %q for resource %s: %w", c.StopSignal, c.Meta.ID, err)
+		}
+	}
+
+	for i, u := range c.Ulimits {
+		if u.Hard == 0 {
+			c.Ulimits[i].Hard = u.Soft
+		}
+
+		// -1 is unlimited so is greater than any other value
+		hard := c.Ulimits[i].Hard
+		if hard != -1 && (u.Soft == -1 || u.Soft > hard) {
+			return fmt.Errorf("invalid ulimit %s for resource %s, the soft limit must not be greater than the hard limit", u.Name, c.Meta.ID)
+		}
+	}
+
 	// process volumes
 	for i, v := range c.Volumes {
 		// make sure mount paths are absolute when type is bind, unless this is the docker sock
 		if v.Type == "" || v.Type == "bind" {
-			c.Volumes[i].Source = utils.EnsureAbsolute(v.Source, c.Meta.File)
+			c.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), c.Meta.File)
+		}
+	}
+
+	if c.Resources != nil && c.Resources.GPU != nil {
+		gpu := c.Resources.GPU
+		if gpu.Driver == "" {
+			gpu.Driver = defaultGPUDriver
+		}
+
+		if gpu.Count != 0 && len(gpu.DeviceIDs) > 0 {
+			return fmt.Errorf("gpu for container %s can set either count or device_ids, not both", c.Meta.ID)
+		}
+
+		// when no devices are specified allocate all the GPUs
+		if gpu.Count == 0 && len(gpu.DeviceIDs) == 0 {
+			gpu.Count = -1
+		}
+	}
+
+	for i, s := range c.Secrets {
+		if (s.Source == "" && s.Value == "") || (s.Source != "" && s.Value != "") {
+			return fmt.Errorf("secret %s must specify either a source or a value", s.Destination)
+		}
+
+		if s.Source != "" {
+			c.Secrets[i].Source = utils.EnsureAbsolute(s.Source, c.Meta.File)
+		}
+
+		if s.Permissions == "" {
+			c.Secrets[i].Permissions = defaultSecretPermissions
+		}
+
+		if _, err := strconv.ParseUint(c.Secrets[i].Permissions, 8, 32); err != nil {
+			return fmt.Errorf("invalid permissions %s for secret %s: %w", s.Permissions, s.Destination, err)
 		}
 	}
 
@@ -127,6 +339,7 @@ func (c *Container) Process() error {
 		if r != nil {
 			kstate := r.(*Container)
 			c.ContainerName = kstate.ContainerName
+			c.ExitCode = kstate.ExitCode
 
 			// add the image id from state
 			c.Image.ID = kstate.Image.ID
@@ -8,6 +8,9 @@ type Image struct {
 	Username string `hcl:"username,optional" json:"username,omitempty"`
 	// Password is the Docker registry password to use for private repositories
 	Password string `hcl:"password,optional" json:"password,omitempty"`
+	// Platform is the os/arch variant of the image to pull i.e. linux/arm64,
+	// when not set the platform of the Docker engine is used
+	Platform string `hcl:"platform,optional" json:"platform,omitempty"`
 
 	// output
 
@@ -1,6 +1,8 @@
 package container
 
 import (
+	"fmt"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
@@ -24,6 +26,10 @@ type Sidecar struct {
 	Labels      map[string]string `hcl:"labels,optional" json:"labels,omitempty"`           // labels to set on the container
 	Volumes     []Volume          `hcl:"volume,block" json:"volumes,omitempty"`             // volumes to attach to the container
 
+	// SharedVolumes are the destinations of volumes on the target container
+	// that are also mounted in the sidecar at the same destination
+	SharedVolumes []string `hcl:"shared_volumes,optional" json:"shared_volumes,omitempty"`
+
 	Privileged bool `hcl:"privileged,optional" json:"privileged,omitempty"` // run the container in privileged mode?
 
 	// resource constraints
@@ -42,11 +48,17 @@ type Sidecar struct {
 }
 
 func (c *Sidecar) Process() error {
+	if c.HealthCheck != nil {
+		if err := config.ValidateDuration(c, "health_check.timeout", c.HealthCheck.Timeout); err != nil {
+			return err
+		}
+	}
+
 	// process volumes
 	for i, v := range c.Volumes {
 		// make sure mount paths are absolute when type is bind
 		if v.Type == "" || v.Type == "bind" {
-			c.Volumes[i].Source = utils.EnsureAbsolute(v.Source, c.Meta.File)
+			c.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), c.Meta.File)
 		}
 	}
 
@@ -67,3 +79,26 @@ func (c *Sidecar) Process() error {
 
 	return nil
 }
+
+// sharedVolumes returns the volumes of the target container that are shared
+// with the sidecar
+func (c *Sidecar) sharedVolumes() ([]Volume, error) {
+	vols := []Volume{}
+
+	for _, d := range c.SharedVolumes {
+		found := false
+		for _, v := range c.Target.Volumes {
+			if v.Destination == d {
+				vols = append(vols, v)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unable to share volume %s with sidecar %s, the target container does not have a volume with the destination", d, c.Meta.ID)
+		}
+	}
+
+	return vols, nil
+}
@@ -2,9 +2,11 @@ package container
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
 	"github.com/stretchr/testify/require"
 )
 
@@ -26,3 +28,217 @@ func TestContainerProcessSetsAbsolute(t *testing.T) {
 
 	require.Equal(t, wd, c.Volumes[0].Source)
 }
+
+func TestContainerProcessSetsDefaultSecretPermissions(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Secrets:      []Secret{{Value: "secret", Destination: "/run/secrets/token"}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "0400", c.Secrets[0].Permissions)
+}
+
+func TestContainerProcessReturnsErrorForInvalidSecretPermissions(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Secrets:      []Secret{{Value: "secret", Destination: "/run/secrets/token", Permissions: "rw"}},
+	}
+
+	err := c.Process()
+	require.Error(t, err)
+}
+
+func TestContainerProcessResolvesVolumesRelativeToFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.hcl")
+	require.NoError(t, os.WriteFile(file, []byte(""), os.ModePerm))
+
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: file}},
+		Volumes: []Volume{
+			{
+				Source:      "./config",
+				Destination: "/config",
+			},
+			{
+				Source:      "data",
+				Destination: "/data",
+				Type:        "volume",
+			},
+		},
+	}
+
+	c.Process()
+
+	require.Equal(t, filepath.Join(dir, "config"), c.Volumes[0].Source)
+	require.Equal(t, "data", c.Volumes[1].Source)
+}
+
+func TestContainerProcessExpandsHomeInVolumeSource(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Volumes: []Volume{
+			{
+				Source:      "~/config",
+				Destination: "/config",
+			},
+		},
+	}
+
+	c.Process()
+
+	require.Equal(t, filepath.Join(utils.HomeFolder(), "config"), c.Volumes[0].Source)
+}
+
+func TestContainerProcessSetsGPUDefaults(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Resources:    &Resources{GPU: &GPU{}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "nvidia", c.Resources.GPU.Driver)
+	require.Equal(t, -1, c.Resources.GPU.Count)
+}
+
+func TestContainerProcessReturnsErrorWhenGPUCountAndDeviceIDsSet(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Resources:    &Resources{GPU: &GPU{Count: 1, DeviceIDs: []string{"0"}}},
+	}
+
+	err := c.Process()
+	require.Error(t, err)
+}
+
+func TestContainerProcessReturnsErrorForInvalidShmSize(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		ShmSize:      "lots",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "shm_size")
+}
+
+func TestContainerProcessReturnsErrorForInvalidStopSignal(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		StopSignal:   "SIGNOPE",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "stop_signal")
+}
+
+func TestContainerProcessReturnsErrorWhenSecretEnvironmentDuplicated(t *testing.T) {
+	c := &Container{
+		ResourceBase:      types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		Environment:       map[string]string{"PASSWORD": "plain"},
+		SecretEnvironment: map[string]string{"PASSWORD": "s3cr3t"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "secret_environment")
+}
+
+func TestContainerProcessReturnsErrorWhenNetworkModeAndNetworksSet(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		NetworkMode:  "host",
+		Networks:     []NetworkAttachment{{ID: "resource.network.test"}},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "network_mode can not be set")
+}
+
+func TestContainerProcessReturnsErrorForInvalidNetworkMode(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		NetworkMode:  "bridge",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid network_mode")
+}
+
+func TestContainerProcessAllowsContainerNetworkMode(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		NetworkMode:  "container:app.container.local.jmpd.in",
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+}
+
+func TestContainerProcessSetsDeviceDefaults(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		Devices:      []Device{{Host: "/dev/fuse"}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, "/dev/fuse", c.Devices[0].Container)
+	require.Equal(t, "rwm", c.Devices[0].Permissions)
+}
+
+func TestContainerProcessReturnsErrorForInvalidDevicePermissions(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		Devices:      []Device{{Host: "/dev/fuse", Permissions: "rwx"}},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid permissions")
+}
+
+func TestContainerProcessDefaultsUlimitHardToSoft(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		Ulimits:      []Ulimit{{Name: "nofile", Soft: 65535}},
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, int64(65535), c.Ulimits[0].Hard)
+}
+
+func TestContainerProcessReturnsErrorWhenUlimitSoftGreaterThanHard(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		Ulimits:      []Ulimit{{Name: "nofile", Soft: 65535, Hard: 1024}},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid ulimit nofile")
+}
+
+func TestContainerProcessReturnsErrorWhenPostCreateAndWaitForExit(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		WaitForExit:  true,
+		PostCreate:   []string{"echo hello"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "post_create can not be set")
+}
+
+func TestContainerProcessReturnsErrorWhenPreDestroyAndWaitForExit(t *testing.T) {
+	c := &Container{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container.test", File: "./"}},
+		WaitForExit:  true,
+		PreDestroy:   []string{"echo goodbye"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "pre_destroy can not be set")
+}
@@ -63,3 +63,30 @@ func TestSidecarLoadsValuesFromState(t *testing.T) {
 
 	require.Equal(t, "fqdn.mine", docs.ContainerName)
 }
+
+func TestSidecarSharedVolumesReturnsTargetVolumes(t *testing.T) {
+	c := &Sidecar{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.sidecar.test"}},
+		Target: Container{
+			Volumes: []Volume{
+				{Source: "/tmp/data", Destination: "/data"},
+				{Source: "/tmp/config", Destination: "/config"},
+			},
+		},
+		SharedVolumes: []string{"/data"},
+	}
+
+	vols, err := c.sharedVolumes()
+	require.NoError(t, err)
+	require.Equal(t, []Volume{{Source: "/tmp/data", Destination: "/data"}}, vols)
+}
+
+func TestSidecarSharedVolumesReturnsErrorWhenTargetVolumeMissing(t *testing.T) {
+	c := &Sidecar{
+		ResourceBase:  types.ResourceBase{Meta: types.Meta{ID: "resource.sidecar.test"}},
+		SharedVolumes: []string{"/data"},
+	}
+
+	_, err := c.sharedVolumes()
+	require.Error(t, err)
+}
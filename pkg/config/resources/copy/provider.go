@@ -5,12 +5,14 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
+	cclients "github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
@@ -21,6 +23,7 @@ type Provider struct {
 	log    sdk.Logger
 	config *Copy
 	getter getter.Getter
+	client cclients.ContainerTasks
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
@@ -35,6 +38,7 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 	}
 
 	p.getter = cli.Getter
+	p.client = cli.ContainerTasks
 	p.config = c
 	p.log = l
 
@@ -80,6 +84,25 @@ func (p *Provider) Create(ctx context.Context) error {
 		srcPath = tempPath
 	}
 
+	if p.config.IsContainerCopy() {
+		err = p.copyToContainer(srcPath)
+	} else {
+		err = p.copyToHost(srcPath)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// store the checksum of local sources so changes can be detected
+	p.config.Checksum, _ = sourceChecksum(p.config.Source)
+
+	return nil
+}
+
+// copyToHost copies the files from the source path to the destination
+// folder on the local machine
+func (p *Provider) copyToHost(srcPath string) error {
 	// Check the dest exists, if so grab the existing perms
 	// so we can set them back after copy
 	// copy changes the permissions of the destination for some reason
@@ -89,8 +112,12 @@ func (p *Provider) Create(ctx context.Context) error {
 		originalPerms = d.Mode()
 	}
 
+	// directories are copied recursively and the permissions of the
+	// source files are preserved
 	opts := cp.Options{}
 	opts.Sync = true
+	opts.PermissionControl = cp.PerservePermission
+	opts.OnSymlink = p.onSymlink(srcPath)
 
 	// keep track of
 	files := []string{}
@@ -120,6 +147,13 @@ func (p *Provider) Create(ctx context.Context) error {
 
 		for _, f := range p.config.CopiedFiles {
 			fn := strings.Replace(f, srcPath, p.config.Destination, -1)
+
+			// only set the permissions for files, setting directories to a
+			// mode without the execute bit would make them unreadable
+			if fi, err := os.Lstat(fn); err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+
 			p.log.Debug("Setting permissions for file", "ref", p.config.Meta.Name, "file", fn, "permissions", p.config.Permissions)
 
 			os.Chmod(fn, os.FileMode(perms))
@@ -134,6 +168,162 @@ func (p *Provider) Create(ctx context.Context) error {
 	return nil
 }
 
+// onSymlink returns a function that determines how symlinks are copied.
+// Relative links that point to a location inside the source are recreated as
+// links, absolute links to a file inside the source are replaced with a copy of
+// the file. Links that point outside of the source, links to directories that
+// would need to be copied, and broken links are skipped so that files outside
+// of the source are never copied.
+func (p *Provider) onSymlink(srcPath string) func(string) cp.SymlinkAction {
+	root, err := filepath.EvalSymlinks(srcPath)
+	if err != nil {
+		root = srcPath
+	}
+
+	return func(src string) cp.SymlinkAction {
+		target, err := filepath.EvalSymlinks(src)
+		if err != nil {
+			p.log.Warn("Skipping broken symlink", "ref", p.config.Meta.ID, "file", src)
+			return cp.Skip
+		}
+
+		rel, err := filepath.Rel(root, target)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			p.log.Warn("Skipping symlink that points outside of the source", "ref", p.config.Meta.ID, "file", src, "target", target)
+			return cp.Skip
+		}
+
+		link, err := os.Readlink(src)
+		if err == nil && !filepath.IsAbs(link) {
+			return cp.Shallow
+		}
+
+		if fi, err := os.Stat(target); err == nil && fi.IsDir() {
+			p.log.Warn("Skipping absolute symlink to a directory", "ref", p.config.Meta.ID, "file", src, "target", target)
+			return cp.Skip
+		}
+
+		return cp.Deep
+	}
+}
+
+// copyToContainer copies the files from the source path to the destination
+// folder in the container, sub folders are created as required
+func (p *Provider) copyToContainer(srcPath string) error {
+	id, err := p.findContainer()
+	if err != nil {
+		return err
+	}
+
+	files := []string{}
+	dirs := map[string]bool{}
+	onSymlink := p.onSymlink(srcPath)
+
+	err = filepath.WalkDir(srcPath, func(f string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		// symlinks follow the same rules as a copy to the host, links that
+		// point outside of the source are never followed
+		symlink := cp.Deep
+		if d.Type()&fs.ModeSymlink != 0 {
+			symlink = onSymlink(f)
+			if symlink == cp.Skip {
+				return nil
+			}
+		}
+
+		rel, err := filepath.Rel(srcPath, f)
+		if err != nil {
+			return err
+		}
+
+		dir := path.Join(p.config.Destination, path.Dir(filepath.ToSlash(rel)))
+		dest := path.Join(dir, filepath.Base(f))
+
+		if !dirs[dir] {
+			err := p.execInContainer(id, "mkdir", "-p", dir)
+			if err != nil {
+				return fmt.Errorf("unable to create directory %s in container: %w", dir, err)
+			}
+
+			dirs[dir] = true
+		}
+
+		// relative links inside the source are recreated as links
+		if symlink == cp.Shallow {
+			link, err := os.Readlink(f)
+			if err != nil {
+				return err
+			}
+
+			p.log.Debug("Create symlink in container", "ref", p.config.Meta.ID, "file", f, "destination", dest, "link", link)
+
+			err = p.execInContainer(id, "ln", "-sfn", link, dest)
+			if err != nil {
+				return fmt.Errorf("unable to create symlink %s in container: %w", dest, err)
+			}
+
+			files = append(files, dest)
+
+			return nil
+		}
+
+		p.log.Debug("Copy file to container", "ref", p.config.Meta.ID, "file", f, "destination", dest)
+
+		err = p.client.CopyFileToContainer(id, f, dir)
+		if err != nil {
+			return err
+		}
+
+		if p.config.Permissions != "" {
+			err := p.execInContainer(id, "chmod", p.config.Permissions, dest)
+			if err != nil {
+				return fmt.Errorf("unable to set permissions for %s in container: %w", dest, err)
+			}
+		}
+
+		files = append(files, dest)
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("unable to copy files to container, ref=%s: %w", p.config.Meta.ID, err)
+	}
+
+	p.config.CopiedFiles = files
+
+	return nil
+}
+
+func (p *Provider) findContainer() (string, error) {
+	ids, err := p.client.FindContainerIDs(p.config.Container.ContainerName)
+	if err != nil {
+		return "", fmt.Errorf("unable to find container %s: %w", p.config.Container.ContainerName, err)
+	}
+
+	if len(ids) == 0 {
+		return "", fmt.Errorf("container %s is not running", p.config.Container.ContainerName)
+	}
+
+	return ids[0], nil
+}
+
+func (p *Provider) execInContainer(id string, command ...string) error {
+	code, err := p.client.ExecuteCommand(id, command, nil, "/", "", "", 30, nil)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("command %s exited with code %d", strings.Join(command, " "), code)
+	}
+
+	return nil
+}
+
 func (p *Provider) Destroy(ctx context.Context, force bool) error {
 	if ctx.Err() != nil {
 		p.log.Debug("Context is cacncelled, skipping destroy", "ref", p.config.Meta.ID)
@@ -142,6 +332,24 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 
 	p.log.Info("Destroy Copy", "ref", p.config.Meta.Name)
 
+	if p.config.IsContainerCopy() {
+		id, err := p.findContainer()
+		if err != nil {
+			// nothing to remove if the container no longer exists
+			p.log.Debug("Unable to find container, skipping file removal", "ref", p.config.Meta.ID, "error", err)
+			return nil
+		}
+
+		for _, f := range p.config.CopiedFiles {
+			err := p.execInContainer(id, "rm", "-f", f)
+			if err != nil {
+				p.log.Debug("Unable to remove file from container", "ref", p.config.Meta.ID, "file", f, "error", err)
+			}
+		}
+
+		return nil
+	}
+
 	for _, f := range p.config.CopiedFiles {
 		fn := strings.Replace(f, p.config.Source, p.config.Destination, -1)
 		p.log.Debug("Remove file", "ref", p.config.Meta.Name, "file", fn, "source", p.config.Source, "destination", p.config.Destination)
@@ -163,11 +371,50 @@ func (p *Provider) Lookup() ([]string, error) {
 }
 
 func (p *Provider) Refresh(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Context is cancelled, skipping refresh", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	changed, _ := p.Changed()
+	if !changed {
+		return nil
+	}
+
 	p.log.Debug("Refresh Copied files", "ref", p.config.Meta.Name)
-	return nil
+
+	return p.Create(ctx)
 }
 
+// Changed returns true when the checksum of a local source differs from the
+// checksum of the source when it was last copied
 func (p *Provider) Changed() (bool, error) {
 	p.log.Debug("Checking changes", "ref", p.config.Meta.Name)
+
+	// remote sources are not checked for changes
+	cs, err := sourceChecksum(p.config.Source)
+	if err != nil || p.config.Checksum == "" {
+		return false, nil
+	}
+
+	if cs != p.config.Checksum {
+		p.log.Debug("Source files have changed, needs refresh", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
 	return false, nil
 }
+
+// sourceChecksum returns the checksum of a local source file or directory
+func sourceChecksum(src string) (string, error) {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return "", err
+	}
+
+	if fi.IsDir() {
+		return utils.HashDir(src)
+	}
+
+	return utils.HashFile(src)
+}
@@ -4,11 +4,15 @@ import (
 	"context"
 	"os"
 	"path"
+	"runtime"
 	"testing"
 
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
 	"github.com/jumppad-labs/jumppad/pkg/clients/getter"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,7 +33,7 @@ func setupCopy(t *testing.T) (*Copy, *Provider) {
 	cc.Source = inDir
 	cc.Destination = outDir
 
-	p := &Provider{logger.NewTestLogger(t), cc, getter.NewGetter(true)}
+	p := &Provider{logger.NewTestLogger(t), cc, getter.NewGetter(true), &mocks.ContainerTasks{}}
 
 	return cc, p
 }
@@ -106,3 +110,181 @@ func TestFetchesRemoteFiles(t *testing.T) {
 
 	require.FileExists(t, path.Join(c.Destination, "README.md"))
 }
+
+func setupContainerCopy(t *testing.T) (*Copy, *Provider, *mocks.ContainerTasks) {
+	c, p := setupCopy(t)
+
+	// add a nested file
+	err := os.Mkdir(path.Join(c.Source, "sub"), 0775)
+	require.NoError(t, err)
+	os.WriteFile(path.Join(c.Source, "sub", "file3.txt"), []byte("file3"), 0755)
+
+	c.Container = &container.Container{ContainerName: "test.container.local.jmpd.in"}
+	c.Container.Meta.ID = "resource.container.test"
+	c.Destination = "/config"
+
+	md := &mocks.ContainerTasks{}
+	md.On("FindContainerIDs", "test.container.local.jmpd.in").Return([]string{"abc"}, nil)
+	md.On("ExecuteCommand", "abc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(0, nil)
+	md.On("CopyFileToContainer", "abc", mock.Anything, mock.Anything).Return(nil)
+
+	p.client = md
+
+	return c, p, md
+}
+
+func TestCopiesADirectoryToAContainer(t *testing.T) {
+	c, p, md := setupContainerCopy(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "ExecuteCommand", "abc", []string{"mkdir", "-p", "/config"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertCalled(t, "ExecuteCommand", "abc", []string{"mkdir", "-p", "/config/sub"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	md.AssertCalled(t, "CopyFileToContainer", "abc", path.Join(c.Source, "file1.txt"), "/config")
+	md.AssertCalled(t, "CopyFileToContainer", "abc", path.Join(c.Source, "sub", "file3.txt"), "/config/sub")
+
+	require.ElementsMatch(t, []string{"/config/file1.txt", "/config/file2.txt", "/config/sub/file3.txt"}, c.CopiedFiles)
+}
+
+func TestCopyToContainerSkipsSymlinksOutsideSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks are not supported on windows")
+	}
+
+	c, p, md := setupContainerCopy(t)
+
+	outside := path.Join(t.TempDir(), "secret.txt")
+	os.WriteFile(outside, []byte("secret"), 0644)
+
+	err := os.Symlink(outside, path.Join(c.Source, "outside.txt"))
+	require.NoError(t, err)
+
+	err = os.Symlink("file1.txt", path.Join(c.Source, "inside.txt"))
+	require.NoError(t, err)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	md.AssertNotCalled(t, "CopyFileToContainer", "abc", path.Join(c.Source, "outside.txt"), mock.Anything)
+	md.AssertNotCalled(t, "CopyFileToContainer", "abc", path.Join(c.Source, "inside.txt"), mock.Anything)
+	md.AssertCalled(t, "ExecuteCommand", "abc", []string{"ln", "-sfn", "file1.txt", "/config/inside.txt"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	require.NotContains(t, c.CopiedFiles, "/config/outside.txt")
+	require.Contains(t, c.CopiedFiles, "/config/inside.txt")
+}
+
+func TestCopyToContainerReturnsErrorWhenContainerNotRunning(t *testing.T) {
+	_, p, md := setupContainerCopy(t)
+
+	md.ExpectedCalls = nil
+	md.On("FindContainerIDs", mock.Anything).Return([]string{}, nil)
+
+	err := p.Create(context.Background())
+	require.Error(t, err)
+}
+
+func TestRemovesFilesFromContainer(t *testing.T) {
+	_, p, md := setupContainerCopy(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	err = p.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	md.AssertCalled(t, "ExecuteCommand", "abc", []string{"rm", "-f", "/config/sub/file3.txt"}, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestChangedReturnsTrueWhenSourceChanges(t *testing.T) {
+	c, p := setupCopy(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	os.WriteFile(path.Join(c.Source, "file1.txt"), []byte("updated"), 0755)
+
+	changed, err = p.Changed()
+	require.NoError(t, err)
+	require.True(t, changed)
+}
+
+func TestCopiesNestedDirectoriesPreservingPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+
+	c, p := setupCopy(t)
+
+	err := os.MkdirAll(path.Join(c.Source, "sub", "nested"), 0755)
+	require.NoError(t, err)
+	os.WriteFile(path.Join(c.Source, "sub", "nested", "run.sh"), []byte("#!/bin/sh"), 0755)
+	os.WriteFile(path.Join(c.Source, "sub", "config.txt"), []byte("config"), 0640)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	fs, err := os.Stat(path.Join(c.Destination, "sub", "nested", "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0755), fs.Mode())
+
+	fs, err = os.Stat(path.Join(c.Destination, "sub", "config.txt"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0640), fs.Mode())
+}
+
+func TestCopiesNestedDirectoriesWithUniformPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+
+	c, p := setupCopy(t)
+	c.Permissions = "0600"
+
+	err := os.MkdirAll(path.Join(c.Source, "sub"), 0755)
+	require.NoError(t, err)
+	os.WriteFile(path.Join(c.Source, "sub", "run.sh"), []byte("#!/bin/sh"), 0755)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	fs, err := os.Stat(path.Join(c.Destination, "sub", "run.sh"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fs.Mode())
+
+	// directories must remain accessible
+	fs, err = os.Stat(path.Join(c.Destination, "sub"))
+	require.NoError(t, err)
+	require.True(t, fs.IsDir())
+	require.Equal(t, os.FileMode(0755), fs.Mode().Perm())
+}
+
+func TestCopySkipsSymlinksOutsideSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require elevated permissions on windows")
+	}
+
+	c, p := setupCopy(t)
+
+	outside := path.Join(t.TempDir(), "secret.txt")
+	os.WriteFile(outside, []byte("secret"), 0644)
+
+	err := os.Symlink(outside, path.Join(c.Source, "outside.txt"))
+	require.NoError(t, err)
+
+	err = os.Symlink("file1.txt", path.Join(c.Source, "inside.txt"))
+	require.NoError(t, err)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.NoFileExists(t, path.Join(c.Destination, "outside.txt"))
+
+	link, err := os.Readlink(path.Join(c.Destination, "inside.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "file1.txt", link)
+}
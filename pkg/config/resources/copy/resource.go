@@ -1,10 +1,13 @@
 package copy
 
 import (
+	"fmt"
 	"os"
+	"path"
 
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
@@ -23,8 +26,20 @@ type Copy struct {
 	Destination string `hcl:"destination" json:"destination"`                    // Destination to write file or files to
 	Permissions string `hcl:"permissions,optional" json:"permissions,omitempty"` // Permissions 0777 to set for written file
 
+	// Container is an optional container to copy the files to, when set the
+	// destination is the absolute path of a directory inside the container
+	Container *container.Container `hcl:"container,optional" json:"container,omitempty"`
+
 	// outputs
 	CopiedFiles []string `hcl:"copied_files,optional" json:"copied_files"`
+
+	// Checksum of the local source files, used to detect changes
+	Checksum string `hcl:"checksum,optional" json:"checksum,omitempty"`
+}
+
+// IsContainerCopy returns true when the files are copied to a container
+func (t *Copy) IsContainerCopy() bool {
+	return t.Container != nil
 }
 
 func (t *Copy) Process() error {
@@ -34,7 +49,14 @@ func (t *Copy) Process() error {
 		t.Source = tempSource
 	}
 
-	t.Destination = utils.EnsureAbsolute(t.Destination, t.Meta.File)
+	if t.IsContainerCopy() {
+		// the destination is a path inside the container
+		if !path.IsAbs(t.Destination) {
+			return fmt.Errorf("destination %s must be an absolute path when copying to a container", t.Destination)
+		}
+	} else {
+		t.Destination = utils.EnsureAbsolute(t.Destination, t.Meta.File)
+	}
 
 	cfg, err := config.LoadState()
 	if err == nil {
@@ -43,6 +65,7 @@ func (t *Copy) Process() error {
 		if r != nil {
 			kstate := r.(*Copy)
 			t.CopiedFiles = kstate.CopiedFiles
+			t.Checksum = kstate.Checksum
 		}
 	}
 
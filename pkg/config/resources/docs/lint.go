@@ -0,0 +1,152 @@
+package docs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// LintError is a problem in the content of a page that would stop the MDX
+// from compiling
+type LintError struct {
+	Line    int
+	Message string
+}
+
+func (e LintError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// jsxTagRegex matches opening, closing and self closing JSX tags, markdown
+// autolinks such as <https://jumppad.dev> do not match as the name must be
+// followed by whitespace, / or >
+var jsxTagRegex = regexp.MustCompile(`<(/?)([A-Za-z][A-Za-z0-9.\-]*)((?:\s[^<>]*?)?)(/?)>`)
+
+type openTag struct {
+	name string
+	line int
+}
+
+// LintPage checks the content of a page for unclosed or mismatched JSX tags
+// and unbalanced expressions, content in code blocks and inline code is
+// ignored
+func LintPage(content string) []LintError {
+	content = strings.Replace(content, "\r\n", "\n", -1)
+	content = maskCode(content)
+
+	errs := []LintError{}
+	stack := []openTag{}
+
+	for _, m := range jsxTagRegex.FindAllStringSubmatchIndex(content, -1) {
+		line := lineAt(content, m[0])
+		closing := content[m[2]:m[3]] == "/"
+		name := content[m[4]:m[5]]
+		selfClosing := content[m[8]:m[9]] == "/"
+
+		switch {
+		case selfClosing:
+			continue
+
+		case closing:
+			if len(stack) == 0 {
+				errs = append(errs, LintError{line, fmt.Sprintf("closing tag </%s> has no matching opening tag", name)})
+				continue
+			}
+
+			top := stack[len(stack)-1]
+			if top.name != name {
+				errs = append(errs, LintError{line, fmt.Sprintf("closing tag </%s> does not match <%s> opened on line %d", name, top.name, top.line)})
+			}
+
+			stack = stack[:len(stack)-1]
+
+		default:
+			stack = append(stack, openTag{name, line})
+		}
+	}
+
+	for _, t := range stack {
+		errs = append(errs, LintError{t.line, fmt.Sprintf("tag <%s> is not closed", t.name)})
+	}
+
+	// check that expressions are balanced
+	opened := []int{}
+	for i, c := range content {
+		switch c {
+		case '{':
+			opened = append(opened, i)
+		case '}':
+			if len(opened) == 0 {
+				errs = append(errs, LintError{lineAt(content, i), "unexpected } without a matching {"})
+				continue
+			}
+
+			opened = opened[:len(opened)-1]
+		}
+	}
+
+	for _, i := range opened {
+		errs = append(errs, LintError{lineAt(content, i), "expression { is not closed"})
+	}
+
+	return errs
+}
+
+// maskCode replaces the content of fenced code blocks and inline code with
+// spaces so that it is not checked, new lines are kept so that line numbers
+// do not change
+func maskCode(content string) string {
+	lines := strings.Split(content, "\n")
+
+	fence := ""
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+
+			lines[i] = strings.Repeat(" ", len(l))
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			lines[i] = strings.Repeat(" ", len(l))
+			continue
+		}
+
+		lines[i] = maskInlineCode(l)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func maskInlineCode(line string) string {
+	b := []byte(line)
+
+	start := -1
+	for i, c := range b {
+		if c != '`' {
+			continue
+		}
+
+		if start < 0 {
+			start = i
+			continue
+		}
+
+		for j := start; j <= i; j++ {
+			b[j] = ' '
+		}
+
+		start = -1
+	}
+
+	return string(b)
+}
+
+func lineAt(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
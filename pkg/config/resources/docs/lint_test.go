@@ -0,0 +1,34 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintPageReturnsNoErrorsForValidContent(t *testing.T) {
+	content := "# Intro\n\n<Task id=\"install\">\n\nInstall <b>consul</b><br/>\n\n</Task>\n\nSee <https://jumppad.dev> and `<Unclosed>`\n\n```\n<Broken\n{\n```\n"
+
+	errs := LintPage(content)
+	require.Empty(t, errs)
+}
+
+func TestLintPageReturnsErrorForUnclosedTag(t *testing.T) {
+	errs := LintPage("# Intro\n\n<Task id=\"install\">\n\ncontent\n")
+	require.Len(t, errs, 1)
+	require.Equal(t, 3, errs[0].Line)
+	require.Contains(t, errs[0].Message, "<Task> is not closed")
+}
+
+func TestLintPageReturnsErrorForMismatchedTag(t *testing.T) {
+	errs := LintPage("<Tabs>\n<Tab>\n</Tabs>\n")
+	require.NotEmpty(t, errs)
+	require.Equal(t, 3, errs[0].Line)
+	require.Contains(t, errs[0].Message, "does not match <Tab> opened on line 2")
+}
+
+func TestLintPageReturnsErrorForUnbalancedExpression(t *testing.T) {
+	errs := LintPage("# Intro\n\nvalue {props.name\n")
+	require.Len(t, errs, 1)
+	require.Equal(t, 3, errs[0].Line)
+}
@@ -4,15 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/http"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"github.com/mohae/deepcopy"
@@ -21,6 +25,37 @@ import (
 const docsImageName = "ghcr.io/jumppad-labs/docs"
 const docsVersion = "v0.5.1"
 
+// tlsProxyImage is the image used to terminate TLS for the docs when a
+// certificate is set
+const tlsProxyImage = "nginx:1.27-alpine"
+
+// tlsProxyConfig proxies HTTPS requests to the docs container, the proxy
+// shares the network namespace of the docs container
+const tlsProxyConfig = `server {
+  listen 443 ssl;
+
+  ssl_certificate     /etc/nginx/certs/tls.crt;
+  ssl_certificate_key /etc/nginx/certs/tls.key;
+
+  location / {
+    proxy_pass http://127.0.0.1:80;
+    proxy_http_version 1.1;
+    proxy_set_header Upgrade $http_upgrade;
+    proxy_set_header Connection "upgrade";
+    proxy_set_header Host $host;
+    proxy_set_header X-Forwarded-Proto https;
+  }
+}
+`
+
+// docsStaticPath is the directory in the docs container that static files
+// are served from
+const docsStaticPath = "/jumppad/public"
+
+// readinessLogLines is the number of lines of the container logs returned
+// when the docs do not become ready
+var readinessLogLines = 20
+
 type DocsConfig struct {
 	DefaultPath string `json:"defaultPath"`
 	Logo        Logo   `json:"logo"`
@@ -64,7 +99,9 @@ type ProgressCondition struct {
 }
 
 type BookIndex struct {
+	Name     string         `hcl:"name,optional" json:"name"`
 	Title    string         `hcl:"title,optional" json:"title"`
+	URI      string         `hcl:"uri,optional" json:"uri"`
 	Chapters []ChapterIndex `hcl:"chapters,optional" json:"chapters"`
 }
 
@@ -80,9 +117,10 @@ type ChapterIndexPage struct {
 
 // Docs defines a provider for creating documentation containers
 type DocsProvider struct {
-	config *Docs
-	client container.ContainerTasks
-	log    sdk.Logger
+	config     *Docs
+	client     container.ContainerTasks
+	httpClient http.HTTP
+	log        sdk.Logger
 }
 
 func (p *DocsProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
@@ -98,6 +136,7 @@ func (p *DocsProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
 
 	p.config = c
 	p.client = cli.ContainerTasks
+	p.httpClient = cli.HTTP
 	p.log = l
 
 	return nil
@@ -129,8 +168,8 @@ func (p *DocsProvider) Destroy(ctx context.Context, force bool) error {
 
 	p.log.Info("Destroy Documentation", "ref", p.config.Meta.ID)
 
-	// remove the docs
-	ids, err := p.client.FindContainerIDs(p.config.ContainerName)
+	// remove the docs and the tls proxy
+	ids, err := p.Lookup()
 	if err != nil {
 		return err
 	}
@@ -149,9 +188,25 @@ func (p *DocsProvider) Destroy(ctx context.Context, force bool) error {
 	return nil
 }
 
-// Lookup the ID of the documentation container
+// Lookup the IDs of the documentation container and the tls proxy
 func (p *DocsProvider) Lookup() ([]string, error) {
-	return p.client.FindContainerIDs(p.config.ContainerName)
+	ids, err := p.client.FindContainerIDs(p.config.ContainerName)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.config.Certificate == nil {
+		return ids, nil
+	}
+
+	pids, err := p.client.FindContainerIDs(tlsProxyName(p.config.ContainerName))
+	if err != nil {
+		return nil, err
+	}
+
+	// the proxy is listed first as it shares the network of the docs and
+	// must be removed before the docs container
+	return append(pids, ids...), nil
 }
 
 func (p *DocsProvider) Refresh(ctx context.Context) error {
@@ -247,11 +302,17 @@ func (p *DocsProvider) createDocsContainer() error {
 		return err
 	}
 
-	// add the ports
+	// add the ports, when serving over https the port is served by the
+	// tls proxy which shares the network of the docs container
+	local := "80"
+	if p.config.Certificate != nil {
+		local = "443"
+	}
+
 	cc.Ports = []types.Port{
 		{
-			Local:  "80",
-			Remote: "80",
+			Local:  local,
+			Remote: local,
 			Host:   fmt.Sprintf("%d", p.config.Port),
 		},
 	}
@@ -300,7 +361,7 @@ func (p *DocsProvider) createDocsContainer() error {
 
 	// mount the assets
 	if p.config.Assets != "" {
-		assetsDestination := "/jumppad/public/assets"
+		assetsDestination := path.Join(docsStaticPath, "assets")
 		cc.Volumes = append(
 			cc.Volumes,
 			types.Volume{
@@ -310,10 +371,117 @@ func (p *DocsProvider) createDocsContainer() error {
 		)
 	}
 
+	// mount any additional static content
+	cc.Volumes = append(cc.Volumes, p.staticVolumes()...)
+
+	id, err := p.client.CreateContainer(cc)
+	if err != nil {
+		return err
+	}
+
+	if p.config.Certificate != nil {
+		err := p.createTLSProxy(id)
+		if err != nil {
+			return fmt.Errorf("unable to create tls proxy for docs: %w", err)
+		}
+	}
+
+	return p.waitForReady(id)
+}
+
+// createTLSProxy creates a container that terminates TLS using the docs
+// certificate and proxies requests to the docs container
+func (p *DocsProvider) createTLSProxy(id string) error {
+	p.log.Debug("Creating TLS proxy", "ref", p.config.Meta.ID, "certificate", p.config.Certificate.Cert.Path)
+
+	// each docs resource has its own proxy config
+	configPath := filepath.Join(utils.LibraryFolder(filepath.Join("tls", p.config.ContainerName), 0775), "nginx.conf")
+	err := os.WriteFile(configPath, []byte(tlsProxyConfig), 0644)
+	if err != nil {
+		return fmt.Errorf("unable to write proxy config: %w", err)
+	}
+
+	cc := &types.Container{
+		Name:            tlsProxyName(p.config.ContainerName),
+		Image:           &types.Image{Name: tlsProxyImage},
+		MaxRestartCount: -1,
+		Networks:        []types.NetworkAttachment{{ID: id, IsContainer: true}},
+		Volumes: []types.Volume{
+			{
+				Source:      configPath,
+				Destination: "/etc/nginx/conf.d/default.conf",
+				ReadOnly:    true,
+			},
+			{
+				Source:      p.config.Certificate.Cert.Path,
+				Destination: "/etc/nginx/certs/tls.crt",
+				ReadOnly:    true,
+			},
+			{
+				Source:      p.config.Certificate.PrivateKey.Path,
+				Destination: "/etc/nginx/certs/tls.key",
+				ReadOnly:    true,
+			},
+		},
+	}
+
+	err = p.client.PullImage(*cc.Image, false)
+	if err != nil {
+		return err
+	}
+
 	_, err = p.client.CreateContainer(cc)
+
 	return err
 }
 
+// tlsProxyName returns the name of the tls proxy container for the docs
+func tlsProxyName(fqdn string) string {
+	return fmt.Sprintf("tls.%s", fqdn)
+}
+
+// staticVolumes returns the additional volumes with the destination set
+// relative to the static directory of the docs
+func (p *DocsProvider) staticVolumes() []types.Volume {
+	vols := p.config.Volumes.ToClientVolumes()
+	for i := range vols {
+		// clean the destination as an absolute path so that it can not
+		// escape the static directory
+		vols[i].Destination = path.Join(docsStaticPath, path.Clean("/"+vols[i].Destination))
+	}
+
+	return vols
+}
+
+// waitForReady waits for the docs to be served, when the docs do not become
+// ready the last lines of the container logs are returned in the error
+func (p *DocsProvider) waitForReady(id string) error {
+	timeout, err := time.ParseDuration(p.config.HealthCheck.Timeout)
+	if err != nil {
+		return fmt.Errorf("unable to parse health check timeout: %w", err)
+	}
+
+	address := fmt.Sprintf("%s://%s:%d%s", p.config.scheme(), utils.GetDockerIP(), p.config.Port, p.config.HealthCheck.Path)
+
+	p.log.Debug("Waiting for docs to be ready", "ref", p.config.Meta.ID, "address", address, "timeout", timeout)
+
+	err = p.httpClient.HealthCheckHTTP(address, "GET", nil, "", []int{200}, timeout)
+	if err == nil {
+		return nil
+	}
+
+	output := ""
+	rc, lerr := p.client.ContainerLogs(id, true, true)
+	if lerr == nil {
+		defer rc.Close()
+
+		d, _ := io.ReadAll(rc)
+		output = utils.TailLines(string(d), readinessLogLines)
+	}
+
+	return fmt.Errorf("docs %s did not become ready within %s: %w, logs:\n%s", p.config.Meta.ID, timeout, err, output)
+}
+
 func (p *DocsProvider) generateDocs() error {
 	p.log.Info("Refresh Docs", "ref", p.config.Meta.ID)
 
@@ -348,13 +516,18 @@ func (p *DocsProvider) generateDocs() error {
 	for _, book := range p.config.Content {
 		bookPath := filepath.Join(contentPath, book.Meta.Name)
 
-		for _, chapter := range book.Chapters {
+		for c, chapter := range sortedChapters(book.Chapters) {
 			chapterPath := filepath.Join(bookPath, chapter.Meta.Name)
 			os.MkdirAll(chapterPath, 0755)
 			os.Chmod(chapterPath, 0755)
 
-			for _, page := range chapter.Pages {
-				err := p.processPage(chapterPath, chapter, page)
+			err := p.writeCategory(chapterPath, chapter, c+1)
+			if err != nil {
+				return err
+			}
+
+			for i, page := range sortedPages(chapter.Pages) {
+				err := p.processPage(chapterPath, chapter, page, i+1)
 				if err != nil {
 					return err
 				}
@@ -375,18 +548,41 @@ func (p *DocsProvider) generateDocs() error {
 	return nil
 }
 
-func (p *DocsProvider) processPage(chapterPath string, chapter Chapter, page Page) error {
-	content := strings.Replace(page.Content, "\r\n", "\n", -1)
+// writeCategory writes the category metadata for the chapter so that the
+// sidebar position of the chapter is deterministic
+func (p *DocsProvider) writeCategory(chapterPath string, chapter Chapter, position int) error {
+	category := map[string]interface{}{
+		"position": position,
+	}
 
-	// replace task ids
-	taskRegex, _ := regexp.Compile("<Task id=\"(?P<id>.*)\">")
-	taskMatch := taskRegex.FindAllStringSubmatch(page.Content, -1)
-	for _, match := range taskMatch {
-		taskID := match[1]
-		resourceID := fmt.Sprintf("<Task id=\"%s\">", chapter.Tasks[taskID].Meta.ID)
-		content = taskRegex.ReplaceAllString(page.Content, resourceID)
+	if chapter.Title != "" {
+		category["label"] = chapter.Title
 	}
 
+	d, err := json.MarshalIndent(category, "", " ")
+	if err != nil {
+		return err
+	}
+
+	categoryPath := filepath.Join(chapterPath, "_category_.json")
+	err = os.WriteFile(categoryPath, d, 0755)
+	if err != nil {
+		return fmt.Errorf("unable to write category for chapter %s to disk at %s", chapter.Meta.Name, categoryPath)
+	}
+
+	return nil
+}
+
+func (p *DocsProvider) processPage(chapterPath string, chapter Chapter, page Page, position int) error {
+	content := strings.Replace(page.Content, "\r\n", "\n", -1)
+	content = withSidebarPosition(content, position)
+
+	// replace the task names with the resource ids
+	content = taskRegex.ReplaceAllStringFunc(content, func(m string) string {
+		taskID := taskRegex.FindStringSubmatch(m)[1]
+		return fmt.Sprintf("<Task id=\"%s\">", chapter.Tasks[taskID].Meta.ID)
+	})
+
 	pageFile := fmt.Sprintf("%s.mdx", page.Name)
 	pagePath := filepath.Join(chapterPath, pageFile)
 	err := os.WriteFile(pagePath, []byte(content), 0755)
@@ -443,35 +639,62 @@ func (p *DocsProvider) writeProgress(path string) error {
 
 // writes the navigation config and returns the index page for the config
 func (p *DocsProvider) writeNavigation(path string) (string, error) {
+	indices, indexPage := p.buildNavigation()
+
+	indexJSON, err := json.MarshalIndent(indices, "", " ")
+	if err != nil {
+		return "", err
+	}
+
+	content := fmt.Sprintf(`export const navigation = %s`, indexJSON)
+	err = os.WriteFile(path, []byte(content), 0755)
+	if err != nil {
+		return "", fmt.Errorf("unable to write navigation to disk at %s", path)
+	}
+
+	return indexPage, nil
+}
+
+// buildNavigation returns the navigation for every book in the docs and the
+// index page for the site, each book has the uri of its first page so that
+// the site can link between books
+func (p *DocsProvider) buildNavigation() ([]BookIndex, string) {
 	indexPage := "/"
 
+	titleRegex, _ := regexp.Compile(`^#\s?(?P<title>.*)`)
+
 	indices := []BookIndex{}
-	for b, book := range p.config.Content {
+	for _, book := range p.config.Content {
 		bookIndex := BookIndex{
+			Name:     book.Meta.Name,
 			Title:    book.Title,
 			Chapters: []ChapterIndex{},
 		}
 
-		for c, chapter := range book.Chapters {
+		for _, chapter := range sortedChapters(book.Chapters) {
 			chapterIndex := ChapterIndex{
 				Title: chapter.Title,
 				Pages: []ChapterIndexPage{},
 			}
 
-			for p, page := range chapter.Pages {
+			for _, page := range sortedPages(chapter.Pages) {
 				pageIndex := ChapterIndexPage{
 					Title: page.Name,
 					URI:   fmt.Sprintf("/docs/%s/%s/%s", book.Meta.Name, chapter.Meta.Name, page.Name),
 				}
 
-				// if this is the first book and chapter and page
-				// set the index page
-				if b == 0 && c == 0 && p == 0 {
+				// the first page of the book is the entry point for the book
+				if bookIndex.URI == "" {
+					bookIndex.URI = pageIndex.URI
+				}
+
+				// the first page of the first book with content is the
+				// index page for the site
+				if indexPage == "/" {
 					indexPage = pageIndex.URI
 				}
 
 				// get the title from the heading of the page
-				titleRegex, _ := regexp.Compile(`^#\s?(?P<title>.*)`)
 				titleMatch := titleRegex.FindStringSubmatch(page.Content)
 				if len(titleMatch) > 0 {
 					pageIndex.Title = titleMatch[1]
@@ -486,18 +709,7 @@ func (p *DocsProvider) writeNavigation(path string) (string, error) {
 		indices = append(indices, bookIndex)
 	}
 
-	indexJSON, err := json.MarshalIndent(indices, "", " ")
-	if err != nil {
-		return "", err
-	}
-
-	content := fmt.Sprintf(`export const navigation = %s`, indexJSON)
-	err = os.WriteFile(path, []byte(content), 0755)
-	if err != nil {
-		return "", fmt.Errorf("unable to write navigation to disk at %s", path)
-	}
-
-	return indexPage, nil
+	return indices, indexPage
 }
 
 func (p *DocsProvider) writeConfig(configPath, indexPage string) error {
@@ -520,3 +732,28 @@ func (p *DocsProvider) writeConfig(configPath, indexPage string) error {
 
 	return nil
 }
+
+// withSidebarPosition adds the sidebar position to the front-matter of the
+// page content, front-matter is added when the page does not have any and an
+// existing sidebar_position is not changed
+func withSidebarPosition(content string, position int) string {
+	field := fmt.Sprintf("sidebar_position: %d", position)
+
+	if !strings.HasPrefix(content, "---\n") {
+		return fmt.Sprintf("---\n%s\n---\n\n%s", field, content)
+	}
+
+	end := strings.Index(content[4:], "\n---")
+	if end < 0 {
+		return content
+	}
+
+	frontMatter := content[4 : 4+end]
+	for _, line := range strings.Split(frontMatter, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "sidebar_position:") {
+			return content
+		}
+	}
+
+	return fmt.Sprintf("---\n%s\n%s", field, content[4:])
+}
@@ -0,0 +1,196 @@
+package docs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	htypes "github.com/jumppad-labs/hclconfig/types"
+	cmocks "github.com/jumppad-labs/jumppad/pkg/clients/container/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/container/types"
+	hmocks "github.com/jumppad-labs/jumppad/pkg/clients/http/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cert"
+	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupDocsReadinessTests(t *testing.T) (*DocsProvider, *cmocks.ContainerTasks, *hmocks.HTTP) {
+	c := &Docs{
+		ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{ID: "resource.docs.test"}},
+		Port:         8080,
+		HealthCheck:  &HealthCheck{Path: "/docs", Timeout: "1s"},
+	}
+
+	md := &cmocks.ContainerTasks{}
+	md.On("ContainerLogs", "abc", true, true).Return(io.NopCloser(strings.NewReader("starting\nerror: unable to build site\n")), nil)
+
+	hc := &hmocks.HTTP{}
+
+	return &DocsProvider{config: c, client: md, httpClient: hc, log: logger.NewTestLogger(t)}, md, hc
+}
+
+func TestDocsWaitForReadyChecksPath(t *testing.T) {
+	p, md, hc := setupDocsReadinessTests(t)
+	hc.On("HealthCheckHTTP", mock.Anything, "GET", mock.Anything, "", []int{200}, mock.Anything).Return(nil)
+
+	err := p.waitForReady("abc")
+	require.NoError(t, err)
+
+	address := hc.Calls[0].Arguments.String(0)
+	require.True(t, strings.HasSuffix(address, ":8080/docs"))
+
+	md.AssertNotCalled(t, "ContainerLogs", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDocsWaitForReadyReturnsLogsWhenNotReady(t *testing.T) {
+	p, _, hc := setupDocsReadinessTests(t)
+	hc.On("HealthCheckHTTP", mock.Anything, "GET", mock.Anything, "", []int{200}, mock.Anything).Return(fmt.Errorf("timeout"))
+
+	err := p.waitForReady("abc")
+	require.Error(t, err)
+	require.ErrorContains(t, err, "unable to build site")
+}
+
+func TestDocsWaitForReadyUsesHTTPSWithCertificate(t *testing.T) {
+	p, _, hc := setupDocsReadinessTests(t)
+	p.config.Certificate = &cert.CertificateLeaf{}
+	hc.On("HealthCheckHTTP", mock.Anything, "GET", mock.Anything, "", []int{200}, mock.Anything).Return(nil)
+
+	err := p.waitForReady("abc")
+	require.NoError(t, err)
+
+	address := hc.Calls[0].Arguments.String(0)
+	require.True(t, strings.HasPrefix(address, "https://"))
+}
+
+func TestDocsCreateTLSProxySharesDocsNetwork(t *testing.T) {
+	t.Setenv(utils.HomeEnvName(), t.TempDir())
+
+	p, md, _ := setupDocsReadinessTests(t)
+	p.config.ContainerName = "test.docs.local.jmpd.in"
+	p.config.Certificate = &cert.CertificateLeaf{
+		Cert:       cert.File{Path: "/certs/leaf.cert"},
+		PrivateKey: cert.File{Path: "/certs/leaf.key"},
+	}
+
+	md.On("PullImage", mock.Anything, false).Return(nil)
+	md.On("CreateContainer", mock.Anything).Return("def", nil)
+
+	err := p.createTLSProxy("abc")
+	require.NoError(t, err)
+
+	cc := testutils.GetCalls(&md.Mock, "CreateContainer")[0].Arguments[0].(*types.Container)
+	require.Equal(t, "tls.test.docs.local.jmpd.in", cc.Name)
+	require.Equal(t, []types.NetworkAttachment{{ID: "abc", IsContainer: true}}, cc.Networks)
+	require.Equal(t, "/certs/leaf.cert", cc.Volumes[1].Source)
+	require.Equal(t, "/certs/leaf.key", cc.Volumes[2].Source)
+
+	d, err := os.ReadFile(cc.Volumes[0].Source)
+	require.NoError(t, err)
+	require.Contains(t, string(d), "listen 443 ssl")
+	require.Contains(t, cc.Volumes[0].Source, "test.docs.local.jmpd.in")
+}
+
+func TestDocsLookupReturnsTLSProxy(t *testing.T) {
+	p, md, _ := setupDocsReadinessTests(t)
+	p.config.ContainerName = "test.docs.local.jmpd.in"
+	p.config.Certificate = &cert.CertificateLeaf{}
+
+	md.On("FindContainerIDs", "test.docs.local.jmpd.in").Return([]string{"abc"}, nil)
+	md.On("FindContainerIDs", "tls.test.docs.local.jmpd.in").Return([]string{"def"}, nil)
+
+	ids, err := p.Lookup()
+	require.NoError(t, err)
+	require.Equal(t, []string{"def", "abc"}, ids)
+}
+
+func TestDocsStaticVolumesAreMountedInStaticDirectory(t *testing.T) {
+	p, _, _ := setupDocsReadinessTests(t)
+	p.config.Volumes = ctypes.Volumes{
+		{Source: "/tmp/downloads", Destination: "downloads"},
+		{Source: "/tmp/files", Destination: "../../files"},
+	}
+
+	vols := p.staticVolumes()
+	require.Len(t, vols, 2)
+	require.Equal(t, "/tmp/downloads", vols[0].Source)
+	require.Equal(t, "/jumppad/public/downloads", vols[0].Destination)
+	require.Equal(t, "/jumppad/public/files", vols[1].Destination)
+}
+
+func TestDocsBuildNavigationIncludesAllBooks(t *testing.T) {
+	p, _, _ := setupDocsReadinessTests(t)
+
+	chapter := func(name string, pages ...Page) Chapter {
+		return Chapter{ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: name}}, Pages: pages}
+	}
+
+	p.config.Content = []Book{
+		{
+			ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "empty"}},
+			Title:        "Empty",
+		},
+		{
+			ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "consul"}},
+			Title:        "Consul",
+			Chapters:     []Chapter{chapter("intro", Page{Name: "start", Content: "# Getting Started"})},
+		},
+		{
+			ResourceBase: htypes.ResourceBase{Meta: htypes.Meta{Name: "vault"}},
+			Title:        "Vault",
+			Chapters:     []Chapter{chapter("setup", Page{Name: "install"}, Page{Name: "configure"})},
+		},
+	}
+
+	nav, index := p.buildNavigation()
+	require.Len(t, nav, 3)
+	require.Equal(t, "/docs/consul/intro/start", index)
+
+	require.Equal(t, "empty", nav[0].Name)
+	require.Empty(t, nav[0].URI)
+
+	require.Equal(t, "consul", nav[1].Name)
+	require.Equal(t, "/docs/consul/intro/start", nav[1].URI)
+	require.Equal(t, "Getting Started", nav[1].Chapters[0].Pages[0].Title)
+
+	require.Equal(t, "vault", nav[2].Name)
+	require.Equal(t, "/docs/vault/setup/install", nav[2].URI)
+	require.Len(t, nav[2].Chapters[0].Pages, 2)
+}
+
+func TestWithSidebarPositionAddsFrontMatter(t *testing.T) {
+	content := withSidebarPosition("# Intro", 2)
+	require.Equal(t, "---\nsidebar_position: 2\n---\n\n# Intro", content)
+}
+
+func TestWithSidebarPositionAddsToExistingFrontMatter(t *testing.T) {
+	content := withSidebarPosition("---\ntitle: Intro\n---\n# Intro", 1)
+	require.Equal(t, "---\nsidebar_position: 1\ntitle: Intro\n---\n# Intro", content)
+}
+
+func TestWithSidebarPositionDoesNotReplaceExistingPosition(t *testing.T) {
+	content := withSidebarPosition("---\nsidebar_position: 5\n---\n# Intro", 1)
+	require.Equal(t, "---\nsidebar_position: 5\n---\n# Intro", content)
+}
+
+func TestSortedPagesOrdersByOrderThenDeclaration(t *testing.T) {
+	pages := sortedPages([]Page{
+		{Name: "a"},
+		{Name: "b", Order: 2},
+		{Name: "c"},
+		{Name: "d", Order: 1},
+	})
+
+	names := []string{}
+	for _, p := range pages {
+		names = append(names, p.Name)
+	}
+
+	require.Equal(t, []string{"d", "b", "a", "c"}, names)
+}
@@ -1,27 +1,93 @@
 package docs
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+
 	"github.com/jumppad-labs/hclconfig/types"
 )
 
 const TypeChapter string = "chapter"
 
+// taskRegex matches the task references in the content of a page
+var taskRegex = regexp.MustCompile(`<Task id="(?P<id>[^"]*)">`)
+
 type Chapter struct {
 	types.ResourceBase `hcl:",remain"`
 
 	Prerequisites []string `hcl:"prerequisites,optional" json:"prerequisites"`
 
 	Title string          `hcl:"title,optional" json:"title,omitempty"`
+	Order int             `hcl:"order,optional" json:"order,omitempty"` // position of the chapter in the book, chapters without an order follow in declaration order
 	Pages []Page          `hcl:"page,block" json:"pages"`
 	Tasks map[string]Task `hcl:"tasks,optional" json:"tasks"`
 }
 
 type Page struct {
 	Name    string `hcl:"name,label" json:"name"`
+	Order   int    `hcl:"order,optional" json:"order,omitempty"` // position of the page in the chapter, pages without an order follow in declaration order
 	Content string `hcl:"content" json:"content"`
 }
 
 func (c *Chapter) Process() error {
+	// every task in the map must reference a task resource
+	names := []string{}
+	for name := range c.Tasks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := c.Tasks[name]
+		if t.Meta.ID == "" || t.Meta.Type != TypeTask {
+			return fmt.Errorf("task %s in chapter %s does not reference a task resource", name, c.Meta.ID)
+		}
+	}
+
+	// every task referenced in the pages must exist in the map
+	for _, page := range c.Pages {
+		for _, match := range taskRegex.FindAllStringSubmatch(page.Content, -1) {
+			if _, ok := c.Tasks[match[1]]; !ok {
+				return fmt.Errorf("page %s in chapter %s references task %s which is not defined in the chapter tasks", page.Name, c.Meta.ID, match[1])
+			}
+		}
+	}
 
 	return nil
 }
+
+// sortedChapters returns the chapters ordered by their order, chapters that do
+// not set an order follow in the order they were declared
+func sortedChapters(chapters []Chapter) []Chapter {
+	sorted := append([]Chapter{}, chapters...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return orderLess(sorted[i].Order, sorted[j].Order)
+	})
+
+	return sorted
+}
+
+// sortedPages returns the pages ordered by their order, pages that do not
+// set an order follow in the order they were declared
+func sortedPages(pages []Page) []Page {
+	sorted := append([]Page{}, pages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return orderLess(sorted[i].Order, sorted[j].Order)
+	})
+
+	return sorted
+}
+
+func orderLess(a, b int) bool {
+	if a == 0 {
+		return false
+	}
+
+	if b == 0 {
+		return true
+	}
+
+	return a < b
+}
@@ -1,8 +1,12 @@
 package docs
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cert"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
@@ -10,6 +14,9 @@ import (
 // TypeDocs is the resource string for a Docs resource
 const TypeDocs string = "docs"
 
+// defaultHealthCheckTimeout is the time to wait for the docs to be served
+const defaultHealthCheckTimeout = "60s"
+
 // Docs allows the running of a Docusaurus container which can be used for
 // online tutorials or documentation
 type Docs struct {
@@ -21,12 +28,26 @@ type Docs struct {
 
 	Content []Book `hcl:"content" json:"content"`
 
-	Port          int  `hcl:"port,optional" json:"port"`
-	OpenInBrowser bool `hcl:"open_in_browser,optional" json:"open_in_browser"` // When a host port is defined open the location in a browser
+	Port          int    `hcl:"port,optional" json:"port"`
+	OpenInBrowser bool   `hcl:"open_in_browser,optional" json:"open_in_browser"` // When a host port is defined open the location in a browser
+	OpenPath      string `hcl:"open_path,optional" json:"open_path,omitempty"`   // Path to open in the browser, defaults to /
 
 	Logo   Logo   `hcl:"logo,optional" json:"logo,omitempty"`
 	Assets string `hcl:"assets,optional" json:"assets,omitempty"`
 
+	// Volumes are mounted into the static directory of the docs so that
+	// additional files can be served, the destination is relative to the
+	// static directory
+	Volumes ctypes.Volumes `hcl:"volume,block" json:"volumes,omitempty"`
+
+	// HealthCheck configures the readiness check that must pass before the
+	// docs are considered created
+	HealthCheck *HealthCheck `hcl:"health_check,block" json:"health_check,omitempty"`
+
+	// Certificate is an optional leaf certificate, when set the docs are
+	// served over HTTPS, otherwise the docs are served over HTTP
+	Certificate *cert.CertificateLeaf `hcl:"certificate,optional" json:"certificate,omitempty"`
+
 	// Output parameters
 
 	// ContainerName is the fully qualified resource name for the container, this can be used
@@ -36,6 +57,17 @@ type Docs struct {
 	// ContentChecksum is the checksum of the content directory, this is used to determine if the
 	// docs need to be recreated
 	ContentChecksum string `hcl:"content_checksum,optional" json:"content_checksum,omitempty"`
+
+	// URL is the address the docs are served from
+	URL string `hcl:"url,optional" json:"url,omitempty"`
+}
+
+// HealthCheck is a HTTP readiness check for the docs container
+type HealthCheck struct {
+	// Path to request, defaults to the open_path of the docs or /
+	Path string `hcl:"path,optional" json:"path,omitempty"`
+	// Timeout to wait for the docs to be ready, defaults to 60s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
 }
 
 type Logo struct {
@@ -54,6 +86,44 @@ func (d *Docs) Process() error {
 		d.Assets = utils.EnsureAbsolute(d.Assets, d.Meta.File)
 	}
 
+	// make sure mount paths are absolute when type is bind
+	for i, v := range d.Volumes {
+		if v.Type == "" || v.Type == "bind" {
+			d.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), d.Meta.File)
+		}
+	}
+
+	// by default wait for the index page to be served
+	if d.HealthCheck == nil {
+		d.HealthCheck = &HealthCheck{}
+	}
+
+	if d.OpenPath != "" && !strings.HasPrefix(d.OpenPath, "/") {
+		d.OpenPath = "/" + d.OpenPath
+	}
+
+	if d.HealthCheck.Path == "" {
+		d.HealthCheck.Path = d.OpenPath
+	}
+
+	if d.HealthCheck.Path == "" {
+		d.HealthCheck.Path = "/"
+	}
+
+	if !strings.HasPrefix(d.HealthCheck.Path, "/") {
+		d.HealthCheck.Path = "/" + d.HealthCheck.Path
+	}
+
+	if d.HealthCheck.Timeout == "" {
+		d.HealthCheck.Timeout = defaultHealthCheckTimeout
+	}
+
+	if err := config.ValidateDuration(d, "health_check.timeout", d.HealthCheck.Timeout); err != nil {
+		return err
+	}
+
+	d.URL = fmt.Sprintf("%s://%s:%d", d.scheme(), utils.FQDN(d.Meta.Name, d.Meta.Module, d.Meta.Type), d.Port)
+
 	// do we have an existing resource in the state?
 	// if so we need to set any computed resources for dependents
 	cfg, err := config.LoadState()
@@ -69,3 +139,12 @@ func (d *Docs) Process() error {
 
 	return nil
 }
+
+// scheme returns the protocol the docs are served with
+func (d *Docs) scheme() string {
+	if d.Certificate != nil {
+		return "https"
+	}
+
+	return "http"
+}
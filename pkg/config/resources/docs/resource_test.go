@@ -5,6 +5,7 @@ import (
 
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/cert"
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/require"
 )
@@ -52,3 +53,90 @@ func TestDocsLoadsValuesFromState(t *testing.T) {
 
 	require.Equal(t, "fqdn.mine", docs.ContainerName)
 }
+
+func TestDocsProcessSetsDefaultHealthCheck(t *testing.T) {
+	h := &Docs{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+	}
+
+	err := h.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "/", h.HealthCheck.Path)
+	require.Equal(t, defaultHealthCheckTimeout, h.HealthCheck.Timeout)
+}
+
+func TestDocsProcessDefaultsHealthCheckToOpenPath(t *testing.T) {
+	h := &Docs{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		OpenPath:     "docs/intro",
+	}
+
+	err := h.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "/docs/intro", h.HealthCheck.Path)
+}
+
+func TestDocsProcessSetsURL(t *testing.T) {
+	h := &Docs{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "test", Type: TypeDocs, File: "./"}},
+		Port:         8080,
+	}
+
+	err := h.Process()
+	require.NoError(t, err)
+	require.Equal(t, "http://test.docs.local.jmpd.in:8080", h.URL)
+
+	h.Certificate = &cert.CertificateLeaf{}
+
+	err = h.Process()
+	require.NoError(t, err)
+	require.Equal(t, "https://test.docs.local.jmpd.in:8080", h.URL)
+}
+
+func TestDocsProcessReturnsErrorForInvalidHealthCheckTimeout(t *testing.T) {
+	h := &Docs{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		HealthCheck:  &HealthCheck{Path: "docs/index", Timeout: "soon"},
+	}
+
+	err := h.Process()
+	require.Error(t, err)
+}
+
+func setupChapterTasks() *Chapter {
+	return &Chapter{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.chapter.intro"}},
+		Pages: []Page{
+			{Name: "start", Content: `<Task id="install">install</Task>`},
+		},
+		Tasks: map[string]Task{
+			"install": {ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.task.install", Type: TypeTask}}},
+		},
+	}
+}
+
+func TestChapterProcessValidatesTasks(t *testing.T) {
+	c := setupChapterTasks()
+
+	err := c.Process()
+	require.NoError(t, err)
+}
+
+func TestChapterProcessReturnsErrorWhenPageReferencesMissingTask(t *testing.T) {
+	c := setupChapterTasks()
+	c.Pages[0].Content = `<Task id="instal">install</Task>`
+
+	err := c.Process()
+	require.ErrorContains(t, err, "page start")
+	require.ErrorContains(t, err, "instal")
+}
+
+func TestChapterProcessReturnsErrorWhenTaskIsNotAResource(t *testing.T) {
+	c := setupChapterTasks()
+	c.Tasks["install"] = Task{}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "task install")
+}
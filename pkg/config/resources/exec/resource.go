@@ -40,12 +40,18 @@ type Exec struct {
 }
 
 func (e *Exec) Process() error {
+	if err := config.ValidateDuration(e, "timeout", e.Timeout); err != nil {
+		return err
+	}
+
 	// check if it is a remote exec
 	if e.Image != nil || e.Target != nil {
 		// process volumes
 		// make sure mount paths are absolute
 		for i, v := range e.Volumes {
-			e.Volumes[i].Source = utils.EnsureAbsolute(v.Source, e.Meta.File)
+			if v.Type == "" || v.Type == "bind" {
+				e.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), e.Meta.File)
+			}
 		}
 
 		// make sure line endings are linux
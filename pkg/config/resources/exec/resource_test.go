@@ -94,3 +94,17 @@ func TestExecLocalWithNetworksReturnsError(t *testing.T) {
 	err := c.Process()
 	require.Error(t, err)
 }
+
+func TestExecWithInvalidTimeoutReturnsError(t *testing.T) {
+	c := &Exec{
+		ResourceBase: types.ResourceBase{
+			Meta: types.Meta{
+				ID: "resource.exec.test",
+			},
+		},
+		Timeout: "30x",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "resource.exec.test")
+}
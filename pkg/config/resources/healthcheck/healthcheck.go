@@ -6,6 +6,11 @@ type HealthCheckContainer struct {
 	// Timeout expressed as a go duration i.e 10s
 	Timeout string `hcl:"timeout" json:"timeout"`
 
+	// RestartOnFailure when set to true, the health checks are re-run when checking
+	// the resource for changes, i.e. during `jumppad dev`. If a check fails the
+	// container is recreated.
+	RestartOnFailure bool `hcl:"restart_on_failure,optional" json:"restart_on_failure,omitempty"`
+
 	HTTP []HealthCheckHTTP `hcl:"http,block" json:"http,omitempty"`
 	TCP  []HealthCheckTCP  `hcl:"tcp,block" json:"tcp,omitempty"`
 	Exec []HealthCheckExec `hcl:"exec,block" json:"exec,omitempty"`
@@ -39,7 +44,25 @@ type HealthCheckKubernetes struct {
 	// Timeout expressed as a go duration i.e 10s
 	Timeout string `hcl:"timeout" json:"timeout"`
 	//	pods = ["component=server,app=consul", "component=client,app=consul"] // is the pod running and healthy
-	Pods []string `hcl:"pods" json:"pods,omitempty"`
+	Pods []string `hcl:"pods,optional" json:"pods,omitempty"`
+	// Conditions that must be true on the given Kubernetes resources
+	Conditions []HealthCheckKubernetesCondition `hcl:"condition,block" json:"conditions,omitempty"`
+}
+
+// HealthCheckKubernetesCondition defines a status condition that must be
+// true on a Kubernetes resource, this can be used to check the status of
+// custom resources
+type HealthCheckKubernetesCondition struct {
+	// APIVersion of the resource i.e. "apps/v1" or "postgresql.cnpg.io/v1"
+	APIVersion string `hcl:"api_version" json:"api_version"`
+	// Resource is the plural name of the resource i.e. "deployments"
+	Resource string `hcl:"resource" json:"resource"`
+	// Name of the resource
+	Name string `hcl:"name" json:"name"`
+	// Namespace of the resource, leave empty for cluster scoped resources
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+	// Type of the condition, default "Ready"
+	Type string `hcl:"type,optional" json:"type,omitempty"`
 }
 
 type HealthCheckNomad struct {
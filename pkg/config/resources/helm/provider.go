@@ -2,7 +2,9 @@ package helm
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
@@ -13,6 +15,8 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 )
 
 var _ sdk.Provider = &Provider{}
@@ -66,14 +70,29 @@ func (p *Provider) Create(ctx context.Context) error {
 	if p.config.Repository != nil {
 		p.log.Debug("Updating Helm chart repository", "name", p.config.Repository.Name, "url", p.config.Repository.URL)
 
-		err := p.helmClient.UpsertChartRepository(p.config.Repository.Name, p.config.Repository.URL)
+		skipVerify := p.config.Repository.TLSVerify != nil && !*p.config.Repository.TLSVerify
+		if skipVerify {
+			p.log.Warn("TLS verification is disabled for Helm chart repository", "ref", p.config.Meta.ID, "url", p.config.Repository.URL)
+		}
+
+		err := p.helmClient.UpsertChartRepository(
+			p.config.Repository.Name,
+			p.config.Repository.URL,
+			p.config.Repository.Username,
+			p.config.Repository.Password,
+			p.config.Repository.CertFile,
+			p.config.Repository.KeyFile,
+			p.config.Repository.CAFile,
+			skipVerify,
+		)
 		if err != nil {
 			return fmt.Errorf("unable to initialize chart repository: %w", err)
 		}
 	}
 
-	// is the source a helm repo which should be downloaded?
-	if !utils.IsLocalFolder(p.config.Chart) && p.config.Repository == nil {
+	// is the source a helm repo which should be downloaded? charts in OCI
+	// registries are pulled by the Helm client
+	if !utils.IsLocalFolder(p.config.Chart) && p.config.Repository == nil && !isOCI(p.config.Chart) {
 		p.log.Debug("Fetching remote Helm chart", "ref", p.config.Meta.Name, "chart", p.config.Chart)
 
 		helmFolder := utils.HelmLocalFolder(p.config.Chart)
@@ -109,6 +128,11 @@ func (p *Provider) Create(ctx context.Context) error {
 		}
 	}
 
+	values, err := valuesMap(p.config.ValuesObject)
+	if err != nil {
+		return fmt.Errorf("unable to convert values_object: %w", err)
+	}
+
 	timeout := time.After(to)
 	errChan := make(chan error)
 	doneChan := make(chan struct{})
@@ -129,10 +153,15 @@ func (p *Provider) Create(ctx context.Context) error {
 				p.config.Namespace,
 				p.config.CreateNamespace,
 				p.config.SkipCRDs,
+				p.config.Atomic,
+				to,
 				p.config.Chart,
 				p.config.Version,
+				p.config.Username,
+				p.config.Password,
 				p.config.Values,
-				p.config.ValuesString)
+				p.config.ValuesString,
+				values)
 
 			if err == nil {
 				doneChan <- struct{}{}
@@ -160,13 +189,18 @@ func (p *Provider) Create(ctx context.Context) error {
 	}
 
 	// we can now health check the install
-	if p.config.HealthCheck != nil && len(p.config.HealthCheck.Pods) > 0 {
+	if p.config.HealthCheck != nil && (len(p.config.HealthCheck.Pods) > 0 || len(p.config.HealthCheck.Conditions) > 0) {
 		to, err := time.ParseDuration(p.config.HealthCheck.Timeout)
 		if err != nil {
 			return fmt.Errorf("unable to parse health check duration: %w", err)
 		}
 
-		err = p.kubeClient.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
+		conditions := []k8s.Condition{}
+		for _, c := range p.config.HealthCheck.Conditions {
+			conditions = append(conditions, k8s.Condition(c))
+		}
+
+		err = k8s.HealthCheck(ctx, p.kubeClient, p.config.HealthCheck.Pods, conditions, to)
 		if err != nil {
 			return fmt.Errorf("health check failed after helm chart setup: %w", err)
 		}
@@ -223,3 +257,29 @@ func (p *Provider) Changed() (bool, error) {
 
 	return false, nil
 }
+
+// valuesMap converts the structured values into a map that can be marshalled
+// to YAML for Helm
+func valuesMap(v cty.Value) (map[string]interface{}, error) {
+	if v.IsNull() {
+		return nil, nil
+	}
+
+	d, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]interface{}{}
+	err = json.Unmarshal(d, &values)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// isOCI returns true when the chart is a reference to an OCI registry
+func isOCI(chart string) bool {
+	return strings.HasPrefix(chart, "oci://")
+}
@@ -1,10 +1,14 @@
 package helm
 
 import (
+	"fmt"
+
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // TypeHelm is the string representation of the Meta.Type
@@ -27,9 +31,18 @@ type Helm struct {
 	// semver of the chart to install
 	Version string `hcl:"version,optional" json:"version,omitempty"`
 
+	// Username and Password are optional credentials used when pulling the
+	// chart from an OCI registry, e.g. oci://ghcr.io/org/chart
+	Username string `hcl:"username,optional" json:"username,omitempty"`
+	Password string `hcl:"password,optional" json:"-"`
+
 	Values       string            `hcl:"values,optional" json:"values"`
 	ValuesString map[string]string `hcl:"values_string,optional" json:"values_string"`
 
+	// ValuesObject is a structured set of values, unlike values_string nested
+	// maps, lists, numbers, and booleans keep their type
+	ValuesObject cty.Value `hcl:"values_object,optional" json:"-"`
+
 	// Namespace is the Kubernetes namespace
 	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
 
@@ -39,10 +52,15 @@ type Helm struct {
 	// Skip the install of any CRDs
 	SkipCRDs bool `hcl:"skip_crds,optional" json:"skip_crds,omitempty"`
 
+	// Atomic rolls back the install or upgrade when it fails, Helm waits for
+	// the resources to be ready before the release is marked as successful
+	Atomic bool `hcl:"atomic,optional" json:"atomic,omitempty"`
+
 	// Retry the install n number of times
 	Retry int `hcl:"retry,optional" json:"retry,omitempty"`
 
-	// Timeout specifies the maximum time a chart can run, default 300s
+	// Timeout specifies the maximum time a chart can run, it is also passed to
+	// Helm to bound the time spent waiting for the release, default 300s
 	Timeout string `hcl:"timeout,optional" json:"timeout"`
 
 	// Define health checks for the pods deployed by the chart
@@ -52,9 +70,42 @@ type Helm struct {
 type HelmRepository struct {
 	Name string `hcl:"name" json:"name"`
 	URL  string `hcl:"url" json:"url"`
+
+	// Username and Password are optional credentials for basic auth
+	Username string `hcl:"username,optional" json:"username,omitempty"`
+	Password string `hcl:"password,optional" json:"-"`
+
+	// CertFile and KeyFile are an optional client certificate for mTLS,
+	// CAFile is used to verify the repository certificate
+	CertFile string `hcl:"cert_file,optional" json:"cert_file,omitempty"`
+	KeyFile  string `hcl:"key_file,optional" json:"key_file,omitempty"`
+	CAFile   string `hcl:"ca_file,optional" json:"ca_file,omitempty"`
+
+	// TLSVerify controls if the repository certificate is verified, defaults
+	// to true. Set to false for internal repositories with self-signed
+	// certificates that can not be verified with ca_file
+	TLSVerify *bool `hcl:"tls_verify,optional" json:"tls_verify,omitempty"`
 }
 
 func (h *Helm) Process() error {
+	if err := config.ValidateDuration(h, "timeout", h.Timeout); err != nil {
+		return err
+	}
+
+	if h.HealthCheck != nil {
+		if err := config.ValidateDuration(h, "health_check.timeout", h.HealthCheck.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if (h.Username == "") != (h.Password == "") {
+		return fmt.Errorf("both username and password must be set for resource %s", h.Meta.ID)
+	}
+
+	if !h.ValuesObject.IsNull() && !h.ValuesObject.Type().IsObjectType() && !h.ValuesObject.Type().IsMapType() {
+		return fmt.Errorf("values_object must be a map for resource %s", h.Meta.ID)
+	}
+
 	// only set absolute if is local folder
 	if h.Chart != "" && utils.IsLocalFolder(utils.EnsureAbsolute(h.Chart, h.Meta.File)) {
 		h.Chart = utils.EnsureAbsolute(h.Chart, h.Meta.File)
@@ -64,5 +115,22 @@ func (h *Helm) Process() error {
 		h.Values = utils.EnsureAbsolute(h.Values, h.Meta.File)
 	}
 
+	if h.Repository != nil {
+		if (h.Repository.CertFile == "") != (h.Repository.KeyFile == "") {
+			return fmt.Errorf("both cert_file and key_file must be set for the repository in resource %s", h.Meta.ID)
+		}
+
+		if h.Repository.TLSVerify == nil {
+			verify := true
+			h.Repository.TLSVerify = &verify
+		}
+
+		for _, f := range []*string{&h.Repository.CertFile, &h.Repository.KeyFile, &h.Repository.CAFile} {
+			if *f != "" {
+				*f = utils.EnsureAbsolute(*f, h.Meta.File)
+			}
+		}
+	}
+
 	return nil
 }
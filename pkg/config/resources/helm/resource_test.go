@@ -7,6 +7,7 @@ import (
 
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 )
 
 func TestHelmProcessSetsAbsolute(t *testing.T) {
@@ -25,3 +26,37 @@ func TestHelmProcessSetsAbsolute(t *testing.T) {
 	require.Equal(t, wd, h.Chart)
 	require.Equal(t, path.Join(wd, "values.yaml"), h.Values)
 }
+
+func TestHelmProcessErrorsWhenPasswordMissing(t *testing.T) {
+	h := &Helm{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./", ID: "resource.helm.test"}},
+		Chart:        "oci://ghcr.io/jumppad-labs/charts/test",
+		Username:     "admin",
+	}
+
+	err := h.Process()
+	require.ErrorContains(t, err, "username and password")
+}
+
+func TestHelmProcessErrorsWhenValuesObjectNotMap(t *testing.T) {
+	h := &Helm{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./", ID: "resource.helm.test"}},
+		Chart:        "oci://ghcr.io/jumppad-labs/charts/test",
+		ValuesObject: cty.StringVal("replicas"),
+	}
+
+	err := h.Process()
+	require.ErrorContains(t, err, "values_object")
+}
+
+func TestHelmProcessVerifiesRepositoryTLSByDefault(t *testing.T) {
+	h := &Helm{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./", ID: "resource.helm.test"}},
+		Chart:        "consul",
+		Repository:   &HelmRepository{Name: "hashicorp", URL: "https://helm.releases.hashicorp.com"},
+	}
+
+	err := h.Process()
+	require.NoError(t, err)
+	require.True(t, *h.Repository.TLSVerify)
+}
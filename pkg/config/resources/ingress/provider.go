@@ -4,12 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"sort"
+	"strings"
 
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
 	"github.com/jumppad-labs/jumppad/pkg/clients/container"
+	kclient "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	nclient "github.com/jumppad-labs/jumppad/pkg/clients/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
@@ -20,10 +24,12 @@ var _ sdk.Provider = &Provider{}
 
 // Ingress defines a provider for handling connection ingress for a cluster
 type Provider struct {
-	config    *Ingress
-	client    container.ContainerTasks
-	connector connector.Connector
-	log       logger.Logger
+	config      *Ingress
+	client      container.ContainerTasks
+	connector   connector.Connector
+	kubeClient  kclient.Kubernetes
+	nomadClient nclient.Nomad
+	log         logger.Logger
 }
 
 func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
@@ -40,6 +46,8 @@ func (p *Provider) Init(cfg htypes.Resource, l sdk.Logger) error {
 	p.config = c
 	p.client = cli.ContainerTasks
 	p.connector = cli.Connector
+	p.kubeClient = cli.Kubernetes
+	p.nomadClient = cli.Nomad
 	p.log = l
 
 	return nil
@@ -53,7 +61,9 @@ func (p *Provider) Create(ctx context.Context) error {
 
 	p.log.Info("Create Ingress", "ref", p.config.Meta.ID)
 
-	if p.config.ExposeLocal {
+	// only remote ingress binds the local port, the port in use check is
+	// done by exposeRemote
+	if p.config.Direction == DirectionLocal {
 		return p.exposeLocal()
 	}
 
@@ -157,7 +167,7 @@ func (p *Provider) exposeLocal() error {
 		p.config.Target.Port,
 		connectorAddress,
 		fmt.Sprintf("localhost:%d", p.config.Port),
-		"local",
+		DirectionLocal,
 	)
 
 	if err != nil {
@@ -176,8 +186,8 @@ func (p *Provider) exposeLocal() error {
 
 func (p *Provider) exposeRemote() error {
 	// check if the port is in use, if so, return an immediate error
-	p.log.Debug("Checking if port is available", "port", p.config.Port)
-	tc, err := net.Dial("tcp", fmt.Sprintf("0.0.0.0:%d", p.config.Port))
+	p.log.Debug("Checking if port is available", "port", p.config.Port, "protocol", p.config.Protocol)
+	tc, err := net.Dial(p.config.Protocol, fmt.Sprintf("0.0.0.0:%d", p.config.Port))
 	if err == nil {
 		p.log.Debug("Port in use", "port", p.config.Port)
 		return fmt.Errorf("unable to create ingress port %d in use", p.config.Port)
@@ -192,7 +202,10 @@ func (p *Provider) exposeRemote() error {
 	port := fmt.Sprintf("%d", p.config.Target.Port)
 
 	if p.config.Target.NamedPort != "" {
-		port = p.config.Target.NamedPort
+		port, err = p.resolveNamedPort()
+		if err != nil {
+			return err
+		}
 	}
 
 	switch p.config.Target.Resource.Meta.Type {
@@ -232,7 +245,7 @@ func (p *Provider) exposeRemote() error {
 		p.config.Port,
 		connectorAddress,
 		destAddr,
-		"remote",
+		DirectionRemote,
 	)
 
 	if err != nil {
@@ -249,6 +262,76 @@ func (p *Provider) exposeRemote() error {
 	return nil
 }
 
+// resolveNamedPort checks the named port against the ports declared by the
+// target. Kubernetes service ports are resolved to the port number, Nomad
+// port labels are returned unchanged as the connector resolves them
+func (p *Provider) resolveNamedPort() (string, error) {
+	name := p.config.Target.NamedPort
+	available := []string{}
+
+	switch p.config.Target.Resource.Meta.Type {
+	case k8s.TypeK8sCluster:
+		_, kubePath, _ := utils.CreateKubeConfigPath(p.config.Target.Resource.Meta.ID)
+
+		kc, err := p.kubeClient.SetConfig(kubePath)
+		if err != nil {
+			return "", fmt.Errorf("unable to create Kubernetes client to resolve named port %s: %w", name, err)
+		}
+
+		svc, err := kc.GetService(p.config.Target.Config["service"], p.config.Target.Config["namespace"])
+		if err != nil {
+			return "", fmt.Errorf("unable to find service %s to resolve named port %s: %w", p.config.Target.Config["service"], name, err)
+		}
+
+		for _, sp := range svc.Spec.Ports {
+			if sp.Name == name {
+				return fmt.Sprintf("%d", sp.Port), nil
+			}
+
+			if sp.Name != "" {
+				available = append(available, sp.Name)
+			}
+		}
+
+	case nomad.TypeNomadCluster:
+		err := p.nomadClient.SetConfig(fmt.Sprintf("http://%s", p.config.Target.Resource.ExternalIP), p.config.Target.Resource.APIPort, 1)
+		if err != nil {
+			return "", fmt.Errorf("unable to create Nomad client to resolve named port %s: %w", name, err)
+		}
+
+		eps, err := p.nomadClient.Endpoints(p.config.Target.Config["job"], p.config.Target.Config["group"], p.config.Target.Config["task"])
+		if err != nil {
+			return "", fmt.Errorf("unable to find endpoints for job %s to resolve named port %s: %w", p.config.Target.Config["job"], name, err)
+		}
+
+		labels := map[string]bool{}
+		for _, ep := range eps {
+			for l := range ep {
+				labels[l] = true
+			}
+		}
+
+		if labels[name] {
+			return name, nil
+		}
+
+		for l := range labels {
+			available = append(available, l)
+		}
+
+	default:
+		return "", fmt.Errorf("target type must be either a Kubernetes or a Nomad cluster")
+	}
+
+	sort.Strings(available)
+
+	if len(available) == 0 {
+		return "", fmt.Errorf("named port %s not found in resource %s, the target does not declare any named ports", name, p.config.Meta.ID)
+	}
+
+	return "", fmt.Errorf("named port %s not found in resource %s, available named ports are: %s", name, p.config.Meta.ID, strings.Join(available, ", "))
+}
+
 // exposeK8sRemote exposes a remote kubernetes service to the local machine
 //func (c *Ingress) exposeK8sRemote() error {
 //	// get the target
@@ -0,0 +1,83 @@
+package ingress
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	kclient "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	nmocks "github.com/jumppad-labs/jumppad/pkg/clients/nomad/mocks"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func setupNamedPortProvider(t *testing.T, targetType, namedPort string) (*Provider, *kclient.MockKubernetes, *nmocks.Nomad) {
+	mk := &kclient.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("GetService", "web", "default").Return(&v1.Service{
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{
+				{Name: "http", Port: 8080},
+				{Name: "metrics", Port: 9102},
+			},
+		},
+	}, nil)
+
+	mn := &nmocks.Nomad{}
+	mn.On("SetConfig", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mn.On("Endpoints", "web", "web", "server").Return([]map[string]string{
+		{"http": "10.5.0.2:21000", "grpc": "10.5.0.2:21001"},
+	}, nil)
+
+	c := &Ingress{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.web"}},
+		Target: TrafficTarget{
+			Resource:  TargetConfig{Meta: types.Meta{Type: targetType}},
+			NamedPort: namedPort,
+			Config: map[string]string{
+				"service":   "web",
+				"namespace": "default",
+				"job":       "web",
+				"group":     "web",
+				"task":      "server",
+			},
+		},
+	}
+
+	return &Provider{config: c, kubeClient: mk, nomadClient: mn, log: logger.NewTestLogger(t)}, mk, mn
+}
+
+func TestResolveNamedPortReturnsKubernetesServicePort(t *testing.T) {
+	p, mk, _ := setupNamedPortProvider(t, k8s.TypeK8sCluster, "http")
+
+	port, err := p.resolveNamedPort()
+	require.NoError(t, err)
+	require.Equal(t, "8080", port)
+	mk.AssertCalled(t, "GetService", "web", "default")
+}
+
+func TestResolveNamedPortListsAvailableKubernetesPorts(t *testing.T) {
+	p, _, _ := setupNamedPortProvider(t, k8s.TypeK8sCluster, "htp")
+
+	_, err := p.resolveNamedPort()
+	require.ErrorContains(t, err, "named port htp not found")
+	require.ErrorContains(t, err, "available named ports are: http, metrics")
+}
+
+func TestResolveNamedPortReturnsNomadLabel(t *testing.T) {
+	p, _, _ := setupNamedPortProvider(t, nomad.TypeNomadCluster, "http")
+
+	port, err := p.resolveNamedPort()
+	require.NoError(t, err)
+	require.Equal(t, "http", port)
+}
+
+func TestResolveNamedPortListsAvailableNomadLabels(t *testing.T) {
+	p, _, _ := setupNamedPortProvider(t, nomad.TypeNomadCluster, "web")
+
+	_, err := p.resolveNamedPort()
+	require.ErrorContains(t, err, "available named ports are: grpc, http")
+}
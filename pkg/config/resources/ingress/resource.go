@@ -11,6 +11,13 @@ import (
 // TypeIngress is the resource string for the type
 const TypeIngress string = "ingress"
 
+// DirectionRemote exposes a service in the target cluster on the local machine
+const DirectionRemote = "remote"
+
+// DirectionLocal exposes a service running on the local machine inside the
+// target cluster
+const DirectionLocal = "local"
+
 // Ingress defines an ingress service mapping ports between local host and resources like containers and kube cluster
 type Ingress struct {
 	types.ResourceBase `hcl:",remain"`
@@ -18,8 +25,17 @@ type Ingress struct {
 	// local port to expose the service on
 	Port int `hcl:"port" json:"port"`
 
-	// Are we exposing a local serve to the target
-	// if
+	// Direction of the ingress, "remote" exposes a service in the target on
+	// the local port, "local" exposes the local port as a service in the
+	// target, defaults to remote
+	Direction string `hcl:"direction,optional" json:"direction,omitempty"`
+
+	// Protocol of the exposed service, defaults to tcp. The connector only
+	// forwards TCP streams, udp is rejected until it is supported by the
+	// connector
+	Protocol string `hcl:"protocol,optional" json:"protocol,omitempty"`
+
+	// Deprecated: use Direction = "local"
 	ExposeLocal bool `hcl:"expose_local,optional" json:"expose_local"`
 
 	// details for the destination service
@@ -46,6 +62,9 @@ type TargetConfig struct {
 	Meta          types.Meta `hcl:"meta" json:"meta"`
 	ExternalIP    string     `hcl:"external_ip,optional" json:"external_ip,omitempty"`
 	ConnectorPort int        `hcl:"connector_port,optional" json:"connector_port,omitempty"`
+
+	// APIPort is used to resolve named ports on Nomad targets
+	APIPort int `hcl:"api_port,optional" json:"api_port,omitempty"`
 }
 
 // Traffic defines either a source or a destination block for ingress traffic
@@ -72,6 +91,33 @@ func (i *Ingress) Process() error {
 			"ports 60000 and 60001 are reserved for internal use", i.Port)
 	}
 
+	// expose_local is kept for existing configuration
+	if i.ExposeLocal {
+		if i.Direction == DirectionRemote {
+			return fmt.Errorf("expose_local can not be used with direction %q in resource %s", i.Direction, i.Meta.ID)
+		}
+
+		i.Direction = DirectionLocal
+	}
+
+	switch i.Direction {
+	case "":
+		i.Direction = DirectionRemote
+	case DirectionRemote, DirectionLocal:
+	default:
+		return fmt.Errorf("invalid direction %q for resource %s, must be remote or local", i.Direction, i.Meta.ID)
+	}
+
+	switch i.Protocol {
+	case "":
+		i.Protocol = "tcp"
+	case "tcp":
+	case "udp":
+		return fmt.Errorf("protocol udp is not supported for resource %s, the connector only forwards tcp traffic", i.Meta.ID)
+	default:
+		return fmt.Errorf("invalid protocol %q for resource %s, must be tcp", i.Protocol, i.Meta.ID)
+	}
+
 	if i.Target.Config == nil {
 		i.Target.Config = make(map[string]string)
 	}
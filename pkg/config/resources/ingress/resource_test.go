@@ -43,3 +43,50 @@ func TestIngressSetsOutputsFromState(t *testing.T) {
 	require.Equal(t, "42", c.IngressID)
 	require.Equal(t, "127.0.0.1", c.LocalAddress)
 }
+
+func TestIngressProcessDefaultsDirectionToRemote(t *testing.T) {
+	c := &Ingress{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.test"}}}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, DirectionRemote, c.Direction)
+}
+
+func TestIngressProcessSetsDirectionLocalForExposeLocal(t *testing.T) {
+	c := &Ingress{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.test"}},
+		ExposeLocal:  true,
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, DirectionLocal, c.Direction)
+}
+
+func TestIngressProcessReturnsErrorForInvalidDirection(t *testing.T) {
+	c := &Ingress{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.test"}},
+		Direction:    "sideways",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid direction")
+}
+
+func TestIngressProcessDefaultsProtocolToTCP(t *testing.T) {
+	c := &Ingress{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.test"}}}
+
+	err := c.Process()
+	require.NoError(t, err)
+	require.Equal(t, "tcp", c.Protocol)
+}
+
+func TestIngressProcessReturnsErrorForUDP(t *testing.T) {
+	c := &Ingress{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.ingress.test"}},
+		Protocol:     "udp",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "only forwards tcp")
+}
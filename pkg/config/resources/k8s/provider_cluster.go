@@ -3,18 +3,23 @@ package k8s
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Masterminds/semver"
+	"github.com/docker/go-units"
 	htypes "github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/clients"
 	"github.com/jumppad-labs/jumppad/pkg/clients/connector"
@@ -26,16 +31,42 @@ import (
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 	sdk "github.com/jumppad-labs/plugin-sdk"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // https://github.com/rancher/k3d/blob/master/cli/commands.go
 
 var _ sdk.Provider = &ClusterProvider{}
 
+// startTimeout is the default time to wait for the cluster to start when
+// the resource does not specify a timeout
 var startTimeout = (300 * time.Second)
 
+// networkTimeout is the maximum time to wait for a network that the cluster
+// is attached to before failing
+var networkTimeout = (30 * time.Second)
+
 //var startTimeout = (60 * time.Second)
 
+// kubeletRunningMessage is written to the server log once the cluster has
+// started
+const kubeletRunningMessage = "Running kubelet"
+
+// startPollInterval is the initial time between checks of the server log
+// while waiting for the cluster to start, the interval doubles after each
+// check up to maxStartPollInterval
+var startPollInterval = 500 * time.Millisecond
+var maxStartPollInterval = 5 * time.Second
+
+// serverAddressRegex matches the server address in a kubeconfig, the host can
+// be an ip address, an ipv6 address, or a hostname depending on the version
+// of k3s, the port is captured so that it can be preserved
+var serverAddressRegex = regexp.MustCompile(`(?m)^(\s*server:\s*)https://(?:\[[^\]]+\]|[^/\s:]+)(:\d+)?`)
+
+// maxLastLogsSize is the maximum number of bytes of the server log that is
+// stored in the LastLogs output when the cluster fails to start
+var maxLastLogsSize = 64 * 1024
+
 // K8sCluster defines a provider which can create Kubernetes clusters
 type ClusterProvider struct {
 	config     *Cluster
@@ -74,7 +105,12 @@ func (p *ClusterProvider) Create(ctx context.Context) error {
 		return nil
 	}
 
-	return p.createK3s(ctx)
+	timeout, err := p.clusterStartTimeout()
+	if err != nil {
+		return err
+	}
+
+	return p.createK3s(ctx, timeout)
 }
 
 // Destroy implements interface method to destroy a cluster
@@ -100,6 +136,51 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 
 	p.log.Debug("Refresh Kubernetes Cluster", "ref", p.config.Meta.Name)
 
+	// recreate the cluster when the server container has been removed
+	missing, err := p.nodesMissing()
+	if err != nil {
+		return err
+	}
+
+	if missing {
+		p.log.Info("Cluster server container is missing, recreating", "ref", p.config.Meta.ID)
+
+		timeout, err := p.clusterStartTimeout()
+		if err != nil {
+			return err
+		}
+
+		// remove any remaining config from the previous cluster
+		err = p.destroyK3s(true)
+		if err != nil {
+			return fmt.Errorf("unable to clean up missing cluster: %w", err)
+		}
+
+		return p.createK3s(ctx, timeout)
+	}
+
+	// redeploy the connector when the cluster was created with a different
+	// version or the connector config has changed, the deployment is rolled
+	// to the new image and config
+	cs, err := connectorDeploymentChecksum(p.config.ConnectorPort, p.config.ConnectorPort+1, connectorLogLevel(), p.config.Connector)
+	if err != nil {
+		return err
+	}
+
+	if p.config.ConnectorImage != connectorImage || p.config.ConnectorChecksum != cs {
+		p.log.Info("Connector deployment changed, redeploying connector", "ref", p.config.Meta.ID, "image", connectorImage)
+
+		p.kubeClient, err = p.kubeClient.SetConfig(p.config.KubeConfig.ConfigPath)
+		if err != nil {
+			return fmt.Errorf("unable to create Kubernetes client: %w", err)
+		}
+
+		err = p.deployConnector(ctx, p.config.ConnectorPort, p.config.ConnectorPort+1)
+		if err != nil {
+			return err
+		}
+	}
+
 	ci, err := p.getChangedImages()
 	if err != nil {
 		return err
@@ -119,6 +200,17 @@ func (p *ClusterProvider) Refresh(ctx context.Context) error {
 func (p *ClusterProvider) Changed() (bool, error) {
 	p.log.Debug("Checking changes Leaf Certificate", "ref", p.config.Meta.Name)
 
+	// check that the server container is still running
+	missing, err := p.nodesMissing()
+	if err != nil {
+		return false, err
+	}
+
+	if missing {
+		p.log.Debug("Cluster server container is missing, requires refresh", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
 	// check to see if the any of the copied images have changed
 	i, err := p.getChangedImages()
 	if err != nil {
@@ -132,6 +224,89 @@ func (p *ClusterProvider) Changed() (bool, error) {
 	return false, nil
 }
 
+// nodesMissing returns true when fewer containers are running for the
+// cluster than expected, the cluster runs a single server node
+func (p *ClusterProvider) nodesMissing() (bool, error) {
+	ids, err := p.Lookup()
+	if err != nil {
+		return false, fmt.Errorf("unable to lookup cluster containers: %w", err)
+	}
+
+	return len(ids) < 1, nil
+}
+
+// checkHostPorts returns an error listing all the tcp host ports that are
+// already in use by another process
+func checkHostPorts(ports []ctypes.Port) error {
+	hostPorts := []int{}
+	for _, cp := range ports {
+		if cp.Host == "" || (cp.Protocol != "" && cp.Protocol != "tcp") {
+			continue
+		}
+
+		hp, err := strconv.Atoi(cp.Host)
+		if err != nil {
+			continue
+		}
+
+		hostPorts = append(hostPorts, hp)
+	}
+
+	inUse := utils.PortsInUse(hostPorts...)
+	if len(inUse) == 0 {
+		return nil
+	}
+
+	used := []string{}
+	for _, u := range inUse {
+		used = append(used, strconv.Itoa(u))
+	}
+
+	if len(used) == 1 {
+		return fmt.Errorf("port %s already in use by another process", used[0])
+	}
+
+	return fmt.Errorf("ports %s already in use by another process", strings.Join(used, ", "))
+}
+
+// clusterSnapshotter returns the containerd snapshotter for the cluster, if a
+// storage driver other than overlay is used then snapshotter must be set to
+// native or the container will not start. Rootless Docker and Podman can not
+// mount overlay inside the cluster container even though it is privileged,
+// so the native snapshotter is always used
+func clusterSnapshotter(info *ctypes.EngineInfo) string {
+	if info.Rootless {
+		return "native"
+	}
+
+	if info.StorageDriver == ctypes.StorageDriverOverlay || info.StorageDriver == ctypes.StorageDriverOverlay2 {
+		return "overlayfs"
+	}
+
+	return "native"
+}
+
+// imagePlatform returns the platform for the cluster image, when not set the
+// platform is derived from the architecture of the host
+func (p *ClusterProvider) imagePlatform() string {
+	if p.config.Image.Platform != "" {
+		return p.config.Image.Platform
+	}
+
+	return "linux/" + runtime.GOARCH
+}
+
+// platformArch returns the architecture from a platform string in the format
+// os/arch[/variant]
+func platformArch(platform string) string {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+
+	return parts[1]
+}
+
 // ImportLocalDockerImages fetches Docker images stored on the local client and imports them into the cluster
 func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force bool) error {
 	id, err := p.Lookup()
@@ -162,12 +337,37 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 		return err
 	}
 
-	for _, i := range imagesFile {
-		p.log.Debug("Importing docker image", "ref", p.config.Meta.ID, "image", i)
+	var total int64
+	for n, i := range imagesFile {
+		// the files are returned relative to the default mount path, convert
+		// to the path the volume is mounted in the cluster
+		if rel, ok := strings.CutPrefix(i, utils.ImageVolumeMountPath); ok {
+			i = path.Join(p.imageCachePath(), rel)
+		}
+
+		size := p.importedImageSize(id[0], i)
+		total += size
+
+		p.log.Info(
+			fmt.Sprintf("Importing image %d/%d: %s", n+1, len(imagesFile), importedImageName(i)),
+			"ref", p.config.Meta.ID,
+			"size", units.HumanSize(float64(size)),
+		)
 
 		// execute the command to import the image
 		// write any command output to the logger
 		_, err = p.client.ExecuteCommand(id[0], []string{"ctr", "image", "import", i}, nil, "/", "", "", 300, p.log.StandardWriter())
+		if err != nil {
+			return fmt.Errorf("unable to import image %s: %w", importedImageName(i), err)
+		}
+	}
+
+	if len(imagesFile) > 0 {
+		p.log.Info("Imported images", "ref", p.config.Meta.ID, "count", len(imagesFile), "size", units.HumanSize(float64(total)))
+	}
+
+	if p.config.VerifyImages {
+		err := p.verifyImportedImages(id[0], images)
 		if err != nil {
 			return err
 		}
@@ -182,6 +382,76 @@ func (p *ClusterProvider) ImportLocalDockerImages(images []ctypes.Image, force b
 	return nil
 }
 
+// importedImageSize returns the size in bytes of the image file in the
+// cluster, 0 is returned when the size can not be determined
+func (p *ClusterProvider) importedImageSize(id, file string) int64 {
+	out := bytes.NewBufferString("")
+
+	_, err := p.client.ExecuteCommand(id, []string{"stat", "-c", "%s", file}, nil, "/", "", "", 30, out)
+	if err != nil {
+		return 0
+	}
+
+	size, err := strconv.ParseInt(strings.TrimSpace(out.String()), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return size
+}
+
+// importedImageName returns the name of the image for a file in the image
+// cache, the filename is the base64 encoded name of the image
+func importedImageName(file string) string {
+	name, err := base64.StdEncoding.DecodeString(path.Base(file))
+	if err != nil {
+		return path.Base(file)
+	}
+
+	return string(name)
+}
+
+// verifyImportedImages checks that the id of each image imported into the
+// cluster matches the id of the image in the local registry, the id is the
+// digest of the image config which is unchanged by save and import
+func (p *ClusterProvider) verifyImportedImages(id string, images []ctypes.Image) error {
+	for _, i := range images {
+		if i.Name == "" {
+			continue
+		}
+
+		local, err := p.client.FindImageInLocalRegistry(i)
+		if err != nil {
+			return fmt.Errorf("unable to find image %s in the local registry: %w", i.Name, err)
+		}
+
+		out := bytes.NewBufferString("")
+		_, err = p.client.ExecuteCommand(id, []string{"crictl", "inspecti", "-o", "go-template", "--template", "{{.status.id}}", i.Name}, nil, "/", "", "", 300, out)
+		if err != nil {
+			return fmt.Errorf("unable to inspect image %s in the cluster: %w", i.Name, err)
+		}
+
+		imported := strings.TrimSpace(out.String())
+		if imported != local {
+			return fmt.Errorf("image %s failed verification, the digest in the cluster %s does not match the local digest %s", i.Name, imported, local)
+		}
+
+		p.log.Debug("Verified imported image", "ref", p.config.Meta.ID, "image", i.Name, "digest", imported)
+	}
+
+	return nil
+}
+
+// imageCachePath returns the path the image volume is mounted at in the
+// cluster
+func (p *ClusterProvider) imageCachePath() string {
+	if p.config.ImageCachePath != "" {
+		return p.config.ImageCachePath
+	}
+
+	return utils.ImageVolumeMountPath
+}
+
 func (p *ClusterProvider) pruneBuildImages() error {
 	ids, err := p.Lookup()
 	if err != nil {
@@ -244,9 +514,12 @@ func (p *ClusterProvider) getChangedImages() ([]ctypes.Image, error) {
 	return changed, nil
 }
 
-func (p *ClusterProvider) createK3s(ctx context.Context) error {
+func (p *ClusterProvider) createK3s(ctx context.Context, timeout time.Duration) error {
 	p.log.Info("Creating Cluster", "ref", p.config.Meta.ID)
 
+	// clear the logs from any previous failed attempt
+	p.config.LastLogs = ""
+
 	// check the cluster does not already exist
 	ids, err := p.Lookup()
 	if err != nil {
@@ -257,13 +530,23 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 		return fmt.Errorf("error, cluster exists")
 	}
 
-	img := ctypes.Image{Name: p.config.Image.Name, Username: p.config.Image.Username, Password: p.config.Image.Password}
+	img := ctypes.Image{Name: p.config.Image.Name, Username: p.config.Image.Username, Password: p.config.Image.Password, Platform: p.imagePlatform()}
+	if arch := platformArch(img.Platform); arch != runtime.GOARCH {
+		p.log.Warn("Cluster image platform does not match the host architecture, the cluster will run under emulation", "ref", p.config.Meta.ID, "platform", img.Platform, "host", runtime.GOARCH)
+	}
+
 	// pull the container image
 	err = p.client.PullImage(img, false)
 	if err != nil {
 		return err
 	}
 
+	// ensure the networks the cluster attaches to have been created
+	err = p.waitForNetworks(ctx)
+	if err != nil {
+		return err
+	}
+
 	// create the volume for the cluster
 	volID, err := p.client.CreateVolume("images")
 	if err != nil {
@@ -293,7 +576,7 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	cc.Volumes = []ctypes.Volume{
 		{
 			Source:      volID,
-			Destination: "/cache",
+			Destination: p.imageCachePath(),
 			Type:        "volume",
 		},
 	}
@@ -328,7 +611,7 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	// Add any custom environment variables
 	cc.Environment = map[string]string{}
 
-	// set the environment variables for the K3S_KUBECONFIG_OUTPUT and K3S_CLUSTER_SECRET
+	// set the environment variables for the K3S_KUBECONFIG_OUTPUT
 	cc.Environment["K3S_KUBECONFIG_OUTPUT"] = "/output/kubeconfig.yaml"
 
 	// only add the variables for the cache when the kubernetes version is >= v1.18.16
@@ -375,15 +658,14 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	}
 
 	// set the Connector server port to a random number
-	p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
+	port, err := utils.RandomPort()
+	if err != nil {
+		return err
+	}
 
-	// determine the snapshotter, if a storage driver other than overlay is used then
-	// snapshotter must be set to native or the container will not start
-	snapShotter := "native"
+	p.config.ConnectorPort = port
 
-	if p.client.EngineInfo().StorageDriver == ctypes.StorageDriverOverlay || p.client.EngineInfo().StorageDriver == ctypes.StorageDriverOverlay2 {
-		snapShotter = "overlayfs"
-	}
+	snapShotter := clusterSnapshotter(p.client.EngineInfo())
 
 	// only add the variables for the cache when the kubernetes version is >= v1.18.16
 	sv, err = semver.NewConstraint(">= v1.25.0")
@@ -392,16 +674,32 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 		return err
 	}
 
-	disableArgs := "--no-deploy=traefik"
+	// generate a token for the cluster when one has not been set
+	if p.config.ClusterToken == "" {
+		token, err := generateClusterToken()
+		if err != nil {
+			return fmt.Errorf("unable to generate cluster token: %w", err)
+		}
+
+		p.config.ClusterToken = token
+	}
+
+	// components that are not deployed, by default traefik is disabled
+	components := p.config.DisableComponents
+	if len(components) == 0 {
+		components = []string{"traefik"}
+	}
+
+	disableFlag := "--no-deploy"
 	clusterToken := ""
 
 	if sv.Check(v) {
-		disableArgs = "--disable=traefik"
-		clusterToken = "--token=mysupersecret"
+		disableFlag = "--disable"
+		clusterToken = fmt.Sprintf("--token=%s", p.config.ClusterToken)
 	} else {
 		// add the cluster secret as an env this is deprecated in v1.25 and
 		// replaced with --token
-		cc.Environment["K3S_CLUSTER_SECRET"] = "mysupersecret"
+		cc.Environment["K3S_CLUSTER_SECRET"] = p.config.ClusterToken
 	}
 
 	// create the server address
@@ -415,11 +713,23 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 		"server",
 		fmt.Sprintf("--https-listen-port=%d", p.config.APIPort),
 		"--kube-proxy-arg=conntrack-max-per-core=0",
-		disableArgs,
+	}
+
+	for _, c := range components {
+		args = append(args, fmt.Sprintf("%s=%s", disableFlag, c))
+	}
+
+	args = append(
+		args,
 		fmt.Sprintf("--snapshotter=%s", snapShotter),
 		fmt.Sprintf("--tls-san=%s", FQDN),                // add the FQDN for the server
 		fmt.Sprintf("--tls-san=%s", utils.GetDockerIP()), // add the docker host IP
 		clusterToken,
+	)
+
+	// add any additional subject alternative names for the API server
+	for _, san := range p.config.TLSSANs {
+		args = append(args, fmt.Sprintf("--tls-san=%s", san))
 	}
 
 	// expose the API server and Connector ports
@@ -461,14 +771,22 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 
 	cc.Command = args
 
+	// check the host ports are free before creating the container so that
+	// all conflicts can be reported at once
+	err = checkHostPorts(cc.Ports)
+	if err != nil {
+		return err
+	}
+
 	id, err := p.client.CreateContainer(cc)
 	if err != nil {
 		return err
 	}
 
 	// wait for the server to start
-	err = p.waitForStart(ctx, id)
+	err = p.waitForStart(ctx, id, timeout)
 	if err != nil {
+		p.captureLogs(id)
 		return err
 	}
 
@@ -488,20 +806,14 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	// set the external IP
 	p.config.ExternalIP = utils.GetDockerIP()
 
-	// get the Kubernetes config file and drop it in a temp folder
-	kc, err := p.copyKubeConfig(id)
+	// get the Kubernetes config file, the server location is replaced as the
+	// file is copied to $HOME/.jumppad/config/[clustername]/kubeconfig.yml
+	// we need to do this as Jumppad might be using a remote Docker engine
+	config, err := p.copyKubeConfig(id)
 	if err != nil {
 		return fmt.Errorf("unable to copy Kubernetes config: %w", err)
 	}
 
-	// replace the server location in the kubeconfig file
-	// and write to $HOME/.shipyard/config/[clustername]/kubeconfig.yml
-	// we need to do this as Shipyard might be using a remote Docker engine
-	config, err := p.createLocalKubeConfig(kc)
-	if err != nil {
-		return fmt.Errorf("unable to create local Kubernetes config: %w", err)
-	}
-
 	p.config.KubeConfig.ConfigPath = config
 
 	// parse the kubeconfig and get the details
@@ -530,16 +842,10 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	}
 
 	// ensure essential pods have started before announcing the resource is available
-	err = p.kubeClient.HealthCheckPods(ctx, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, startTimeout)
+	err = p.kubeClient.HealthCheckPods(ctx, []string{"app=local-path-provisioner", "k8s-app=kube-dns"}, timeout)
 	if err != nil {
 		// fetch the logs from the container before exit
-		lr, lerr := p.client.ContainerLogs(id, true, true)
-		if lerr != nil {
-			p.log.Error("unable to get logs from container", "error", lerr)
-		}
-
-		// copy the logs to the output
-		io.Copy(p.log.StandardWriter(), lr)
+		p.captureLogs(id)
 
 		return fmt.Errorf("timeout waiting for Kubernetes default pods: %w", err)
 	}
@@ -568,8 +874,64 @@ func (p *ClusterProvider) createK3s(ctx context.Context) error {
 	return p.deployConnector(ctx, p.config.ConnectorPort, p.config.ConnectorPort+1)
 }
 
-func (p *ClusterProvider) waitForStart(ctx context.Context, id string) error {
+// captureLogs copies the server container logs to the logger and stores the
+// end of the log, bounded by maxLastLogsSize, in the LastLogs output
+func (p *ClusterProvider) captureLogs(id string) {
+	lr, err := p.client.ContainerLogs(id, true, true)
+	if err != nil {
+		p.log.Error("unable to get logs from container", "error", err)
+		return
+	}
+	defer lr.Close()
+
+	tail := &tailBuffer{max: maxLastLogsSize}
+	io.Copy(io.MultiWriter(p.log.StandardWriter(), tail), lr)
+
+	p.config.LastLogs = string(tail.data)
+}
+
+// tailBuffer is a writer that keeps only the last max bytes written to it
+type tailBuffer struct {
+	max  int
+	data []byte
+}
+
+func (t *tailBuffer) Write(b []byte) (int, error) {
+	t.data = append(t.data, b...)
+	if len(t.data) > t.max {
+		t.data = t.data[len(t.data)-t.max:]
+	}
+
+	return len(b), nil
+}
+
+// clusterStartTimeout returns the time to wait for the cluster to start, when the
+// resource does not set a timeout the default startTimeout is used
+func (p *ClusterProvider) clusterStartTimeout() (time.Duration, error) {
+	if p.config.Timeout == "" {
+		return startTimeout, nil
+	}
+
+	d, err := time.ParseDuration(p.config.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse timeout %q for cluster %s: %w", p.config.Timeout, p.config.Meta.ID, err)
+	}
+
+	return d, nil
+}
+
+// waitForStart polls the server container logs until the kubelet is running,
+// only the part of the log that has not already been scanned is checked and
+// the time between polls increases up to maxStartPollInterval
+func (p *ClusterProvider) waitForStart(ctx context.Context, id string, timeout time.Duration) error {
 	start := time.Now()
+	interval := startPollInterval
+
+	// offset is the number of bytes of the log that have been scanned, tail
+	// holds the end of the previous read so that the message is found when it
+	// is split across reads
+	offset := int64(0)
+	tail := ""
 
 	for {
 		if ctx.Err() != nil {
@@ -577,7 +939,7 @@ func (p *ClusterProvider) waitForStart(ctx context.Context, id string) error {
 		}
 
 		// not running after timeout exceeded? Rollback and delete everything.
-		if startTimeout != 0 && time.Now().After(start.Add(startTimeout)) {
+		if timeout != 0 && time.Now().After(start.Add(timeout)) {
 			//deleteCluster()
 			return errors.New("cluster creation exceeded specified timeout")
 		}
@@ -585,47 +947,56 @@ func (p *ClusterProvider) waitForStart(ctx context.Context, id string) error {
 		// scan container logs for a line that tells us that the required services are up and running
 		out, err := p.client.ContainerLogs(id, true, true)
 		if err != nil {
-			out.Close()
 			return fmt.Errorf("unable to get docker logs for %s\n%+v", id, err)
 		}
 
+		// skip the part of the log that has already been scanned
+		io.CopyN(io.Discard, out, offset)
+
 		// read from the log and check for Kublet running
 		buf := new(bytes.Buffer)
 		nRead, _ := buf.ReadFrom(out)
 		out.Close()
-		output := buf.String()
-		if nRead > 0 && strings.Contains(string(output), "Running kubelet") {
+
+		offset += nRead
+		output := tail + buf.String()
+		if strings.Contains(output, kubeletRunningMessage) {
 			break
 		}
 
+		if len(output) >= len(kubeletRunningMessage) {
+			tail = output[len(output)-len(kubeletRunningMessage)+1:]
+		} else {
+			tail = output
+		}
+
 		// wait and try again
-		time.Sleep(1 * time.Second)
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+
+		interval = interval * 2
+		if interval > maxStartPollInterval {
+			interval = maxStartPollInterval
+		}
 	}
 
 	return nil
 }
 
 func (p *ClusterProvider) copyKubeConfig(id string) (string, error) {
-	// create destination kubeconfig file paths
-	_, kubePath, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
-
-	// get kubeconfig file from container and read contents
-	err := p.client.CopyFromContainer(id, "/output/kubeconfig.yaml", kubePath)
-	if err != nil {
-		return "", err
-	}
-
-	return kubePath, nil
-}
-
-func (p *ClusterProvider) createLocalKubeConfig(kubeconfig string) (string, error) {
 	ip := utils.GetDockerIP()
+
+	// create destination kubeconfig file paths
 	_, kubePath, _ := utils.CreateKubeConfigPath(p.config.Meta.ID)
 
-	err := p.changeServerAddressInK8sConfig(
-		fmt.Sprintf("https://%s", ip),
-		kubeconfig,
+	// get kubeconfig file from container pointing the server at the docker ip
+	err := p.client.CopyFromContainerAndTransform(
+		id,
+		"/output/kubeconfig.yaml",
 		kubePath,
+		serverAddressTransform(fmt.Sprintf("https://%s", ip)),
 	)
 	if err != nil {
 		return "", err
@@ -634,36 +1005,12 @@ func (p *ClusterProvider) createLocalKubeConfig(kubeconfig string) (string, erro
 	return kubePath, nil
 }
 
-func (p *ClusterProvider) changeServerAddressInK8sConfig(addr, origFile, newFile string) error {
-	// read the config into a string
-	f, err := os.OpenFile(origFile, os.O_RDONLY, 0666)
-	if err != nil {
-		return err
+// serverAddressTransform returns a transform that replaces the host of the
+// server in a kubeconfig with addr, keeping the port
+func serverAddressTransform(addr string) func([]byte) []byte {
+	return func(d []byte) []byte {
+		return serverAddressRegex.ReplaceAll(d, []byte("${1}"+addr+"${2}"))
 	}
-	defer f.Close()
-
-	readBytes, err := io.ReadAll(f)
-	if err != nil {
-		return fmt.Errorf("unable to read kubeconfig, %v", err)
-	}
-
-	// manipulate the file
-	newConfig := strings.Replace(
-		string(readBytes),
-		"server: https://127.0.0.1",
-		fmt.Sprintf("server: %s", addr),
-		-1,
-	)
-
-	kubeconfigfile, err := os.Create(newFile)
-	if err != nil {
-		return fmt.Errorf("could not create kubeconfig file %s\n%+v", newFile, err)
-	}
-
-	defer kubeconfigfile.Close()
-	kubeconfigfile.Write([]byte(newConfig))
-
-	return nil
 }
 
 // deployConnector deploys the connector service to the cluster
@@ -723,21 +1070,17 @@ func (p *ClusterProvider) deployConnector(ctx context.Context, grpcPort, httpPor
 		return fmt.Errorf("unable to create RBAC for connector: %s", err)
 	}
 
-	// get the log level from the environment variable
-	ll := os.Getenv("LOG_LEVEL")
-	if ll == "" {
-		ll = "info"
-	}
+	ll := connectorLogLevel()
 
 	files = append(files, path.Join(dir, "deployment.yaml"))
 	p.log.Debug("Writing deployment config", "file", files[3])
-	err = writeConnectorDeployment(files[3], grpcPort, httpPort, ll)
+	err = writeConnectorDeployment(files[3], grpcPort, httpPort, ll, p.config.Connector)
 	if err != nil {
 		return fmt.Errorf("unable to create deployment for connector: %s", err)
 	}
 
 	// deploy the application config
-	err = p.kubeClient.Apply(files, true)
+	err = p.kubeClient.Apply(files, "", true)
 	if err != nil {
 		return fmt.Errorf("unable to apply configuration: %s", err)
 	}
@@ -748,6 +1091,12 @@ func (p *ClusterProvider) deployConnector(ctx context.Context, grpcPort, httpPor
 		return fmt.Errorf("timeout waiting for connector to start: %s", err)
 	}
 
+	p.config.ConnectorImage = connectorImage
+	p.config.ConnectorChecksum, err = connectorDeploymentChecksum(grpcPort, httpPort, ll, p.config.Connector)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -786,18 +1135,31 @@ func (p *ClusterProvider) createRegistriesConfig() (string, error) {
 	}
 
 	// if the config is nil, do nothing
-	if p.config.Config == nil ||
-		p.config.Config.DockerConfig == nil ||
-		len(p.config.Config.DockerConfig.InsecureRegistries) < 1 {
+	if p.config.Config == nil {
 		return "", nil
 	}
 
-	for _, ir := range p.config.Config.DockerConfig.InsecureRegistries {
-		dc.Mirrors[ir] = dockerMirror{
-			Endpoints: []string{fmt.Sprintf("http://%s", ir)},
+	if p.config.Config.DockerConfig != nil {
+		for _, ir := range p.config.Config.DockerConfig.InsecureRegistries {
+			dc.Mirrors[ir] = dockerMirror{
+				Endpoints: []string{fmt.Sprintf("http://%s", ir)},
+			}
 		}
 	}
 
+	// explicit mirrors override the insecure registries
+	if p.config.Config.ContainerdConfig != nil {
+		for _, m := range p.config.Config.ContainerdConfig.Mirrors {
+			dc.Mirrors[m.Registry] = dockerMirror{
+				Endpoints: m.Endpoints,
+			}
+		}
+	}
+
+	if len(dc.Mirrors) < 1 {
+		return "", nil
+	}
+
 	// write the config to a file
 	data, err := yaml.Marshal(&dc)
 	if err != nil {
@@ -844,16 +1206,302 @@ func writeConnectorK8sSecret(path, root, key, cert string) error {
 	), os.ModePerm)
 }
 
-func writeConnectorDeployment(path string, grpc, http int, logLevel string) error {
-	return os.WriteFile(path, []byte(
-		fmt.Sprintf(connectorDeployment, grpc, http, logLevel),
-	), os.ModePerm)
+func writeConnectorDeployment(path string, grpc, http int, logLevel string, cc *ConnectorConfig) error {
+	d, err := renderConnectorDeployment(grpc, http, logLevel, cc)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(d), os.ModePerm)
+}
+
+// renderConnectorDeployment returns the Kubernetes deployment for the connector
+func renderConnectorDeployment(grpc, http int, logLevel string, cc *ConnectorConfig) (string, error) {
+	sched, err := connectorScheduling(cc)
+	if err != nil {
+		return "", err
+	}
+
+	probes, err := connectorProbes(cc)
+	if err != nil {
+		return "", err
+	}
+
+	res, err := connectorResources(cc)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(connectorDeployment, grpc, http, sched, connectorImage, probes, res, logLevel), nil
+}
+
+// connectorDeploymentChecksum returns a checksum of the rendered connector
+// deployment, used to detect when the deployed connector is out of date
+func connectorDeploymentChecksum(grpc, http int, logLevel string, cc *ConnectorConfig) (string, error) {
+	d, err := renderConnectorDeployment(grpc, http, logLevel, cc)
+	if err != nil {
+		return "", err
+	}
+
+	return utils.HashString(d)
+}
+
+// connectorLogLevel returns the log level for the connector from the
+// LOG_LEVEL environment variable
+func connectorLogLevel() string {
+	ll := os.Getenv("LOG_LEVEL")
+	if ll == "" {
+		ll = "info"
+	}
+
+	return ll
+}
+
+type podToleration struct {
+	Key      string `yaml:"key,omitempty"`
+	Operator string `yaml:"operator,omitempty"`
+	Value    string `yaml:"value,omitempty"`
+	Effect   string `yaml:"effect,omitempty"`
+}
+
+type podScheduling struct {
+	Tolerations  []podToleration   `yaml:"tolerations"`
+	NodeSelector map[string]string `yaml:"nodeSelector,omitempty"`
+}
+
+// connectorScheduling returns the tolerations and node selector for the
+// connector pod spec, the control-plane taint is always tolerated so that the
+// connector can run on single node clusters
+func connectorScheduling(cc *ConnectorConfig) (string, error) {
+	ps := podScheduling{
+		Tolerations: []podToleration{
+			{Key: "node-role.kubernetes.io/control-plane", Operator: "Exists", Effect: "NoSchedule"},
+		},
+	}
+
+	if cc != nil {
+		for _, t := range cc.Tolerations {
+			ps.Tolerations = append(ps.Tolerations, podToleration(t))
+		}
+
+		ps.NodeSelector = cc.NodeSelector
+	}
+
+	d, err := yaml.Marshal(ps)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate scheduling config for connector: %w", err)
+	}
+
+	// indent to match the pod spec in the deployment
+	return indentYAML(d, 6), nil
+}
+
+// defaultConnectorProbe is used for the connector probes when the cluster
+// does not configure them
+var defaultConnectorProbe = ConnectorProbe{
+	InitialDelay:     "5s",
+	Period:           "10s",
+	Timeout:          "1s",
+	FailureThreshold: 3,
+}
+
+type httpGetAction struct {
+	Path string `yaml:"path"`
+	Port string `yaml:"port"`
+}
+
+type containerProbe struct {
+	HTTPGet             httpGetAction `yaml:"httpGet"`
+	InitialDelaySeconds int           `yaml:"initialDelaySeconds"`
+	PeriodSeconds       int           `yaml:"periodSeconds"`
+	TimeoutSeconds      int           `yaml:"timeoutSeconds"`
+	FailureThreshold    int           `yaml:"failureThreshold"`
+}
+
+type containerProbes struct {
+	ReadinessProbe containerProbe `yaml:"readinessProbe"`
+	LivenessProbe  containerProbe `yaml:"livenessProbe"`
+}
+
+// connectorProbes returns the readiness and liveness probes for the connector
+// container, the probes call the health endpoint on the http port. Any value
+// not set in the cluster config uses the default
+func connectorProbes(cc *ConnectorConfig) (string, error) {
+	pr := defaultConnectorProbe
+	if cc != nil && cc.Probe != nil {
+		if cc.Probe.InitialDelay != "" {
+			pr.InitialDelay = cc.Probe.InitialDelay
+		}
+
+		if cc.Probe.Period != "" {
+			pr.Period = cc.Probe.Period
+		}
+
+		if cc.Probe.Timeout != "" {
+			pr.Timeout = cc.Probe.Timeout
+		}
+
+		if cc.Probe.FailureThreshold > 0 {
+			pr.FailureThreshold = cc.Probe.FailureThreshold
+		}
+	}
+
+	probe := containerProbe{
+		HTTPGet:          httpGetAction{Path: "/health", Port: "http"},
+		FailureThreshold: pr.FailureThreshold,
+	}
+
+	for _, f := range []struct {
+		value string
+		dest  *int
+	}{
+		{pr.InitialDelay, &probe.InitialDelaySeconds},
+		{pr.Period, &probe.PeriodSeconds},
+		{pr.Timeout, &probe.TimeoutSeconds},
+	} {
+		d, err := time.ParseDuration(f.value)
+		if err != nil {
+			return "", fmt.Errorf("unable to parse probe duration for connector: %w", err)
+		}
+
+		*f.dest = int(d.Seconds())
+	}
+
+	d, err := yaml.Marshal(containerProbes{ReadinessProbe: probe, LivenessProbe: probe})
+	if err != nil {
+		return "", fmt.Errorf("unable to generate probe config for connector: %w", err)
+	}
+
+	// indent to match the container spec in the deployment
+	return indentYAML(d, 8), nil
+}
+
+// defaultConnectorResources are the requests and limits for the connector
+// when the cluster does not configure them
+var defaultConnectorResources = ConnectorResources{
+	CPURequest:    "50m",
+	MemoryRequest: "64Mi",
+	CPULimit:      "500m",
+	MemoryLimit:   "256Mi",
+}
+
+type resourceList struct {
+	CPU    string `yaml:"cpu"`
+	Memory string `yaml:"memory"`
+}
+
+type containerResources struct {
+	Resources struct {
+		Requests resourceList `yaml:"requests"`
+		Limits   resourceList `yaml:"limits"`
+	} `yaml:"resources"`
+}
+
+// connectorResources returns the resource requests and limits for the
+// connector container, any value not set in the cluster config uses the default
+func connectorResources(cc *ConnectorConfig) (string, error) {
+	cr := defaultConnectorResources
+	if cc != nil && cc.Resources != nil {
+		for _, f := range []struct {
+			value string
+			dest  *string
+		}{
+			{cc.Resources.CPURequest, &cr.CPURequest},
+			{cc.Resources.MemoryRequest, &cr.MemoryRequest},
+			{cc.Resources.CPULimit, &cr.CPULimit},
+			{cc.Resources.MemoryLimit, &cr.MemoryLimit},
+		} {
+			if f.value != "" {
+				*f.dest = f.value
+			}
+		}
+
+		// a limit below the default request lowers the request to the limit
+		// so that the deployment remains valid
+		cr.CPURequest = capQuantity(cr.CPURequest, cc.Resources.CPULimit)
+		cr.MemoryRequest = capQuantity(cr.MemoryRequest, cc.Resources.MemoryLimit)
+	}
+
+	res := containerResources{}
+	res.Resources.Requests = resourceList{CPU: cr.CPURequest, Memory: cr.MemoryRequest}
+	res.Resources.Limits = resourceList{CPU: cr.CPULimit, Memory: cr.MemoryLimit}
+
+	d, err := yaml.Marshal(res)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate resource config for connector: %w", err)
+	}
+
+	// indent to match the container spec in the deployment
+	return indentYAML(d, 8), nil
+}
+
+// capQuantity returns limit when it is set and smaller than value
+func capQuantity(value, limit string) string {
+	if limit == "" {
+		return value
+	}
+
+	v, err := resource.ParseQuantity(value)
+	if err != nil {
+		return value
+	}
+
+	l, err := resource.ParseQuantity(limit)
+	if err != nil {
+		return value
+	}
+
+	if v.Cmp(l) > 0 {
+		return limit
+	}
+
+	return value
+}
+
+// indentYAML indents every line of the yaml document by the given number of
+// spaces so that it can be inserted into the connector deployment
+func indentYAML(d []byte, spaces int) string {
+	lines := strings.Split(strings.TrimSpace(string(d)), "\n")
+	for i, l := range lines {
+		lines[i] = strings.Repeat(" ", spaces) + l
+	}
+
+	return strings.Join(lines, "\n")
 }
 
 func writeConnectorRBAC(path string) error {
 	return os.WriteFile(path, []byte(connectorRBAC), os.ModePerm)
 }
 
+// waitForNetworks blocks until all the networks the cluster is attached to
+// can be found, a network created in the same run may not be available
+// immediately
+func (p *ClusterProvider) waitForNetworks(ctx context.Context) error {
+	for _, n := range p.config.Networks {
+		timeout, cancel := context.WithTimeout(ctx, networkTimeout)
+
+		for {
+			_, err := p.client.FindNetwork(n.ID)
+			if err == nil {
+				break
+			}
+
+			p.log.Debug("Waiting for network", "ref", p.config.Meta.ID, "network", n.ID, "error", err)
+
+			select {
+			case <-timeout.Done():
+				cancel()
+				return fmt.Errorf("timeout waiting for network %s to be created: %w", n.ID, err)
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+
+		cancel()
+	}
+
+	return nil
+}
+
 type dockerConfig struct {
 	Mirrors map[string]dockerMirror `yaml:"mirrors"`
 }
@@ -877,6 +1525,9 @@ type Configuration struct {
 	} `yaml:"users"`
 }
 
+// connectorImage is the version of the connector deployed to clusters
+const connectorImage = "ghcr.io/jumppad-labs/connector:v0.4.0"
+
 var connectorDeployment = `
 apiVersion: v1
 kind: ServiceAccount
@@ -923,15 +1574,18 @@ spec:
         app: connector
     spec:
       serviceAccountName: connector
+%s
       containers:
       - name: connector
         imagePullPolicy: IfNotPresent
-        image: ghcr.io/jumppad-labs/connector:v0.4.0
+        image: %s
         ports:
           - name: grpc
             containerPort: 60000
           - name: http
             containerPort: 60001
+%s
+%s
         command: ["/connector", "run"]
         args: [
           "--grpc-bind=:60000",
@@ -999,3 +1653,15 @@ metadata:
   name: connector-certs
   namespace: jumppad
 `
+
+// generateClusterToken returns a random hex encoded token used to join nodes
+// to the cluster
+func generateClusterToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
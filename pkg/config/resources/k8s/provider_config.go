@@ -56,21 +56,35 @@ func (p *ConfigProvider) create(ctx context.Context) error {
 		return err
 	}
 
-	err = p.client.Apply(p.config.Paths, p.config.WaitUntilReady)
+	if p.config.Namespace != "" && p.config.CreateNamespace {
+		p.log.Debug("Creating namespace", "ref", p.config.Meta.ID, "namespace", p.config.Namespace)
+
+		err = p.client.CreateNamespace(p.config.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = p.client.Apply(p.config.Paths, p.config.Namespace, p.config.WaitUntilReady)
 	if err != nil {
 		return err
 	}
 
 	// run any health checks
-	if p.config.HealthCheck != nil && len(p.config.HealthCheck.Pods) > 0 {
+	if p.config.HealthCheck != nil && (len(p.config.HealthCheck.Pods) > 0 || len(p.config.HealthCheck.Conditions) > 0) {
 		to, err := time.ParseDuration(p.config.HealthCheck.Timeout)
 		if err != nil {
 			return fmt.Errorf("unable to parse healthcheck duration: %w", err)
 		}
 
-		err = p.client.HealthCheckPods(ctx, p.config.HealthCheck.Pods, to)
+		conditions := []k8s.Condition{}
+		for _, c := range p.config.HealthCheck.Conditions {
+			conditions = append(conditions, k8s.Condition(c))
+		}
+
+		err = k8s.HealthCheck(ctx, p.client, p.config.HealthCheck.Pods, conditions, to)
 		if err != nil {
-			return fmt.Errorf("healthcheck failed after helm chart setup: %w", err)
+			return fmt.Errorf("healthcheck failed after applying config: %w", err)
 		}
 	}
 
@@ -102,7 +116,7 @@ func (p *ConfigProvider) destroy(ctx context.Context, force bool) error {
 		return err
 	}
 
-	err = p.client.Delete(p.config.Paths)
+	err = p.client.Delete(p.config.Paths, p.config.Namespace)
 	if err != nil {
 		p.log.Debug("There was a problem destroying Kubernetes config, logging message but ignoring error", "ref", p.config.Meta.ID, "error", err)
 	}
@@ -126,7 +140,7 @@ func (p *ConfigProvider) Refresh(ctx context.Context) error {
 
 	p.log.Info("Refresh Kubernetes config", "ref", p.config.Meta.ID, "paths", cp)
 
-	err = p.client.Delete(dp)
+	err = p.client.Delete(dp, p.config.Namespace)
 	if err != nil {
 		p.log.Debug("There was a problem destroying Kubernetes config, logging message but ignoring error", "ref", p.config.Meta.ID, "error", err)
 	}
@@ -14,12 +14,14 @@ import (
 	"github.com/jumppad-labs/jumppad/testutils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func setupK8sConfig(t *testing.T) (*k8scli.MockKubernetes, *ConfigProvider) {
 	mk := &k8scli.MockKubernetes{}
 	mk.On("SetConfig", mock.Anything).Return(nil)
-	mk.On("Apply", mock.Anything, mock.Anything).Return(nil)
+	mk.On("Apply", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mk.On("CreateNamespace", mock.Anything).Return(nil)
 	mk.On("Delete", mock.Anything, mock.Anything).Return(nil)
 
 	// create the test files
@@ -48,7 +50,8 @@ func TestCreatesCorrectly(t *testing.T) {
 
 	//_, destPath, _ := utils.CreateKubeConfigPath("testcluster")
 	//mk.AssertCalled(t, "SetConfig", destPath)
-	mk.AssertCalled(t, "Apply", p.config.Paths, p.config.WaitUntilReady)
+	mk.AssertCalled(t, "Apply", p.config.Paths, "", p.config.WaitUntilReady)
+	mk.AssertNotCalled(t, "CreateNamespace", mock.Anything)
 }
 
 func TestRunsHealthChecks(t *testing.T) {
@@ -62,7 +65,7 @@ func TestRunsHealthChecks(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.NoError(t, err)
 
-	mk.AssertCalled(t, "HealthCheckPods", mock.Anything, []string{"app=mine"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckPods", mock.Anything, []string{"app=mine"}, withinTimeout(60*time.Second))
 }
 
 func TestHealthCheckFailReturnsError(t *testing.T) {
@@ -76,7 +79,7 @@ func TestHealthCheckFailReturnsError(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.Error(t, err)
 
-	mk.AssertCalled(t, "HealthCheckPods", mock.Anything, []string{"app=mine"}, 60*time.Second)
+	mk.AssertCalled(t, "HealthCheckPods", mock.Anything, []string{"app=mine"}, withinTimeout(60*time.Second))
 }
 
 func TestCreateSetupErrorReturnsError(t *testing.T) {
@@ -94,7 +97,7 @@ func TestDestroysCorrectly(t *testing.T) {
 	err := p.Destroy(context.Background(), false)
 	assert.NoError(t, err)
 
-	mk.AssertCalled(t, "Delete", p.config.Paths)
+	mk.AssertCalled(t, "Delete", p.config.Paths, "")
 }
 
 func TestDestroySetupErrorReturnsError(t *testing.T) {
@@ -161,3 +164,81 @@ func TestRefreshWithAddedFileApplies(t *testing.T) {
 
 	mk.AssertNumberOfCalls(t, "Apply", 2)
 }
+
+func TestCreatesNamespaceWhenSet(t *testing.T) {
+	mk, p := setupK8sConfig(t)
+	p.config.Namespace = "mine"
+	p.config.CreateNamespace = true
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	mk.AssertCalled(t, "CreateNamespace", "mine")
+	mk.AssertCalled(t, "Apply", p.config.Paths, "mine", p.config.WaitUntilReady)
+}
+
+func TestCreateNamespaceErrorReturnsError(t *testing.T) {
+	mk, p := setupK8sConfig(t)
+	p.config.Namespace = "mine"
+	p.config.CreateNamespace = true
+	testutils.RemoveOn(&mk.Mock, "CreateNamespace")
+	mk.On("CreateNamespace", mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	mk.AssertNotCalled(t, "Apply", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunsConditionHealthChecks(t *testing.T) {
+	mk, p := setupK8sConfig(t)
+	p.config.HealthCheck = &healthcheck.HealthCheckKubernetes{
+		Timeout: "60s",
+		Conditions: []healthcheck.HealthCheckKubernetesCondition{
+			{
+				APIVersion: "postgresql.cnpg.io/v1",
+				Resource:   "clusters",
+				Name:       "db",
+				Namespace:  "default",
+			},
+		},
+	}
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	gvr := schema.GroupVersionResource{Group: "postgresql.cnpg.io", Version: "v1", Resource: "clusters"}
+	mk.AssertCalled(t, "WaitForCondition", mock.Anything, gvr, "db", "default", "Ready", withinTimeout(60*time.Second))
+	mk.AssertNotCalled(t, "HealthCheckPods", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConditionHealthCheckFailReturnsError(t *testing.T) {
+	mk, p := setupK8sConfig(t)
+	p.config.HealthCheck = &healthcheck.HealthCheckKubernetes{
+		Timeout: "60s",
+		Conditions: []healthcheck.HealthCheckKubernetesCondition{
+			{
+				APIVersion: "apps/v1",
+				Resource:   "deployments",
+				Name:       "web",
+				Type:       "Available",
+			},
+		},
+	}
+	mk.On("WaitForCondition", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+
+	gvr := schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	mk.AssertCalled(t, "WaitForCondition", mock.Anything, gvr, "web", "", "Available", withinTimeout(60*time.Second))
+}
+
+// withinTimeout matches the time remaining before a health check deadline
+// that was set with the given timeout
+func withinTimeout(timeout time.Duration) interface{} {
+	return mock.MatchedBy(func(d time.Duration) bool {
+		return d > 0 && d <= timeout
+	})
+}
@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	htypes "github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients"
+	"github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+)
+
+var _ sdk.Provider = &ExecProvider{}
+
+// ExecProvider executes commands in pods running in a Kubernetes cluster
+type ExecProvider struct {
+	config *Exec
+	client k8s.Kubernetes
+	log    sdk.Logger
+}
+
+func (p *ExecProvider) Init(cfg htypes.Resource, l sdk.Logger) error {
+	c, ok := cfg.(*Exec)
+	if !ok {
+		return fmt.Errorf("unable to initialize Exec provider, resource is not of type K8sExec")
+	}
+
+	cli, err := clients.GenerateClients(l)
+	if err != nil {
+		return err
+	}
+
+	p.config = c
+	p.client = cli.Kubernetes
+	p.log = l
+
+	return nil
+}
+
+// Create executes the command in the pod
+func (p *ExecProvider) Create(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping create, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	p.log.Info("Executing command in Kubernetes pod", "ref", p.config.Meta.ID, "selector", p.config.Selector, "command", p.config.Command)
+
+	cs, err := utils.ChecksumFromInterface(p.config.Command)
+	if err != nil {
+		return fmt.Errorf("unable to generate checksum for command: %s", err)
+	}
+
+	p.client, err = p.client.SetConfig(p.config.Cluster.KubeConfig.ConfigPath)
+	if err != nil {
+		return fmt.Errorf("unable to create Kubernetes client: %w", err)
+	}
+
+	stdout, stderr, exitCode, err := p.client.ExecInPod(p.config.Namespace, p.config.Selector, p.config.Container, p.config.Command)
+	if err != nil {
+		return fmt.Errorf("unable to execute command: %w", err)
+	}
+
+	p.config.Stdout = stdout
+	p.config.Stderr = stderr
+	p.config.ExitCode = exitCode
+	p.config.Checksum = cs
+
+	if exitCode != 0 {
+		p.log.Error("Command exited with non zero exit code", "ref", p.config.Meta.ID, "exit_code", exitCode, "stderr", stderr)
+		return fmt.Errorf("command exited with non zero exit code %d", exitCode)
+	}
+
+	return nil
+}
+
+// Destroy is a no-op, commands executed in pods can not be reversed
+func (p *ExecProvider) Destroy(ctx context.Context, force bool) error {
+	return nil
+}
+
+func (p *ExecProvider) Lookup() ([]string, error) {
+	return []string{}, nil
+}
+
+func (p *ExecProvider) Refresh(ctx context.Context) error {
+	if ctx.Err() != nil {
+		p.log.Debug("Skipping refresh, context cancelled", "ref", p.config.Meta.ID)
+		return nil
+	}
+
+	changed, err := p.Changed()
+	if err != nil {
+		return err
+	}
+
+	if changed {
+		p.log.Debug("Refresh Kubernetes exec", "ref", p.config.Meta.ID)
+		return p.Create(ctx)
+	}
+
+	return nil
+}
+
+func (p *ExecProvider) Changed() (bool, error) {
+	cs, err := utils.ChecksumFromInterface(p.config.Command)
+	if err != nil {
+		return false, fmt.Errorf("unable to generate checksum for command: %s", err)
+	}
+
+	if cs != p.config.Checksum {
+		p.log.Debug("Command has changed", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	k8scli "github.com/jumppad-labs/jumppad/pkg/clients/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupK8sExec(t *testing.T) (*k8scli.MockKubernetes, *ExecProvider) {
+	mk := &k8scli.MockKubernetes{}
+	mk.On("SetConfig", mock.Anything).Return(nil)
+	mk.On("ExecInPod", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("out", "err", 0, nil)
+
+	e := &Exec{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "exec", ID: "resource.k8s_exec.exec"}},
+		Namespace:    "default",
+		Selector:     "app=db",
+		Container:    "postgres",
+		Command:      []string{"psql", "-c", "select 1"},
+	}
+
+	p := &ExecProvider{e, mk, logger.NewTestLogger(t)}
+
+	return mk, p
+}
+
+func TestExecCreateExecutesInPod(t *testing.T) {
+	mk, p := setupK8sExec(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	mk.AssertCalled(t, "ExecInPod", "default", "app=db", "postgres", []string{"psql", "-c", "select 1"})
+	require.Equal(t, "out", p.config.Stdout)
+	require.Equal(t, "err", p.config.Stderr)
+	require.Equal(t, 0, p.config.ExitCode)
+}
+
+func TestExecCreateNonZeroExitReturnsError(t *testing.T) {
+	mk, p := setupK8sExec(t)
+	testutils.RemoveOn(&mk.Mock, "ExecInPod")
+	mk.On("ExecInPod", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", "boom", 1, nil)
+
+	err := p.Create(context.Background())
+	require.Error(t, err)
+
+	require.Equal(t, 1, p.config.ExitCode)
+}
+
+func TestExecCreateErrorReturnsError(t *testing.T) {
+	mk, p := setupK8sExec(t)
+	testutils.RemoveOn(&mk.Mock, "ExecInPod")
+	mk.On("ExecInPod", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", "", -1, fmt.Errorf("boom"))
+
+	err := p.Create(context.Background())
+	require.Error(t, err)
+}
+
+func TestExecCreateSetsChecksum(t *testing.T) {
+	_, p := setupK8sExec(t)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.False(t, changed)
+}
+
+func TestExecChangedWhenCommandInStateDiffers(t *testing.T) {
+	testutils.SetupState(t, `
+{
+  "blueprint": null,
+  "resources": [
+  {
+      "meta": {
+      "id": "resource.k8s_exec.exec",
+      "name": "exec",
+      "type": "k8s_exec"
+      },
+      "checksum": "checksum-of-previous-command"
+  }
+  ]
+}`)
+
+	_, p := setupK8sExec(t)
+	err := p.config.Process()
+	require.NoError(t, err)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.True(t, changed)
+}
@@ -2,11 +2,14 @@ package k8s
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	ctypes "github.com/jumppad-labs/jumppad/pkg/config/resources/container"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // TypeK8sCluster is the resource string for a Cluster resource
@@ -27,13 +30,43 @@ type Cluster struct {
 	// Images that will be copied from the local docker cache to the cluster
 	CopyImages []ctypes.Image `hcl:"copy_image,block" json:"copy_images,omitempty"`
 
+	// VerifyImages compares the digest of the images imported into the cluster
+	// with the digest of the local image, creation fails when they do not match
+	VerifyImages bool `hcl:"verify_images,optional" json:"verify_images,omitempty"`
+
 	Ports      []ctypes.Port      `hcl:"port,block" json:"ports,omitempty"`             // ports to expose
 	PortRanges []ctypes.PortRange `hcl:"port_range,block" json:"port_ranges,omitempty"` // range of ports to expose
 
 	Environment map[string]string `hcl:"environment,optional" json:"environment,omitempty"` // environment variables to set when starting the container
 
+	// TLSSANs are additional hostnames or ip addresses that are added to the
+	// certificate for the API server, the server FQDN and the docker host ip
+	// are always added
+	TLSSANs []string `hcl:"tls_sans,optional" json:"tls_sans,omitempty"`
+
+	// DisableComponents are the k3s components that are not deployed to the
+	// cluster i.e. traefik, servicelb, metrics-server, local-storage, when
+	// not set traefik is disabled
+	DisableComponents []string `hcl:"disable_components,optional" json:"disable_components,omitempty"`
+
+	// ImageCachePath is the path the image cache volume is mounted at inside
+	// the cluster, images copied to the cluster are imported from this path,
+	// defaults to /cache
+	ImageCachePath string `hcl:"image_cache_path,optional" json:"image_cache_path,omitempty"`
+
 	Config *ClusterConfig `hcl:"config,block" json:"config,omitempty"`
 
+	// Connector configures how the connector deployment is scheduled
+	Connector *ConnectorConfig `hcl:"connector,block" json:"connector,omitempty"`
+
+	// ClusterToken is the shared secret used to join nodes to the cluster,
+	// when not set a random token is generated
+	ClusterToken string `hcl:"cluster_token,optional" json:"cluster_token,omitempty"`
+
+	// Timeout is the maximum time to wait for the cluster and the default
+	// pods to start, expressed as a duration i.e. 10m, defaults to 300s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+
 	// output parameters
 
 	// Kubernetes config details
@@ -45,6 +78,14 @@ type Cluster struct {
 	// Port the connector is running on
 	ConnectorPort int `hcl:"connector_port,optional" json:"connector_port,omitempty"`
 
+	// ConnectorImage is the image of the connector deployed to the cluster,
+	// the connector is redeployed when it does not match the current version
+	ConnectorImage string `hcl:"connector_image,optional" json:"connector_image,omitempty"`
+
+	// ConnectorChecksum is a checksum of the connector deployment, the
+	// connector is redeployed when the deployment changes
+	ConnectorChecksum string `hcl:"connector_checksum,optional" json:"connector_checksum,omitempty"`
+
 	// Fully qualified domain name for the container, this address can be
 	// used to reference the container within docker and from other containers
 	ContainerName string `hcl:"container_name,optional" json:"container_name,omitempty"`
@@ -52,11 +93,33 @@ type Cluster struct {
 	// ExternalIP is the ip address of the cluster, this generally resolves
 	// to the docker ip
 	ExternalIP string `hcl:"external_ip,optional" json:"external_ip,omitempty"`
+
+	// LastLogs contains the end of the server container log when the cluster
+	// fails to start, it is empty when the cluster was created successfully
+	LastLogs string `hcl:"last_logs,optional" json:"last_logs,omitempty"`
 }
 
 type ClusterConfig struct {
 	// Specifies configuration for the Docker driver.
 	DockerConfig *DockerConfig `hcl:"docker,block" json:"docker,omitempty"`
+
+	// ContainerdConfig configures the registries used by containerd inside
+	// the cluster, this is written to /etc/rancher/k3s/registries.yaml
+	ContainerdConfig *ContainerdConfig `hcl:"containerd,block" json:"containerd,omitempty"`
+}
+
+type ContainerdConfig struct {
+	// Mirrors override the endpoints used to pull images for a registry
+	Mirrors []Mirror `hcl:"mirror,block" json:"mirrors,omitempty"`
+}
+
+type Mirror struct {
+	// Registry is the name of the registry i.e. docker.io
+	Registry string `hcl:"registry,label" json:"registry"`
+
+	// Endpoints are the addresses used to pull images for the registry,
+	// endpoints are tried in order
+	Endpoints []string `hcl:"endpoint" json:"endpoint"`
 }
 
 type DockerConfig struct {
@@ -67,6 +130,69 @@ type DockerConfig struct {
 	InsecureRegistries []string `hcl:"insecure_registries,optional" json:"insecure-registries,omitempty"`
 }
 
+type ConnectorConfig struct {
+	// NodeSelector restricts the connector to nodes with the given labels
+	NodeSelector map[string]string `hcl:"node_selector,optional" json:"node_selector,omitempty"`
+
+	// Tolerations allow the connector to be scheduled on tainted nodes, the
+	// control-plane taint is always tolerated
+	Tolerations []Toleration `hcl:"toleration,block" json:"tolerations,omitempty"`
+
+	// Probe configures the readiness and liveness probes for the connector,
+	// when not set the defaults are used
+	Probe *ConnectorProbe `hcl:"probe,block" json:"probe,omitempty"`
+
+	// Resources sets the cpu and memory requests and limits for the connector,
+	// when not set the defaults are used
+	Resources *ConnectorResources `hcl:"resources,block" json:"resources,omitempty"`
+}
+
+// ConnectorResources are the resource requests and limits for the connector,
+// values are Kubernetes quantities i.e. 100m for cpu or 128Mi for memory
+type ConnectorResources struct {
+	// CPURequest defaults to 50m
+	CPURequest string `hcl:"cpu_request,optional" json:"cpu_request,omitempty"`
+
+	// MemoryRequest defaults to 64Mi
+	MemoryRequest string `hcl:"memory_request,optional" json:"memory_request,omitempty"`
+
+	// CPULimit defaults to 500m
+	CPULimit string `hcl:"cpu_limit,optional" json:"cpu_limit,omitempty"`
+
+	// MemoryLimit defaults to 256Mi
+	MemoryLimit string `hcl:"memory_limit,optional" json:"memory_limit,omitempty"`
+}
+
+// ConnectorProbe configures how Kubernetes checks the health of the connector,
+// a connector that fails the liveness probe is restarted
+type ConnectorProbe struct {
+	// InitialDelay is the time to wait after the connector starts before
+	// probing i.e. 5s, defaults to 5s
+	InitialDelay string `hcl:"initial_delay,optional" json:"initial_delay,omitempty"`
+
+	// Period is the time between probes i.e. 10s, defaults to 10s
+	Period string `hcl:"period,optional" json:"period,omitempty"`
+
+	// Timeout is the time to wait for a probe to respond i.e. 1s, defaults to 1s
+	Timeout string `hcl:"timeout,optional" json:"timeout,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed probes before the
+	// connector is restarted, defaults to 3
+	FailureThreshold int `hcl:"failure_threshold,optional" json:"failure_threshold,omitempty"`
+}
+
+type Toleration struct {
+	Key string `hcl:"key,optional" json:"key,omitempty"`
+
+	// Operator is either Exists or Equal, defaults to Equal
+	Operator string `hcl:"operator,optional" json:"operator,omitempty"`
+	Value    string `hcl:"value,optional" json:"value,omitempty"`
+
+	// Effect is NoSchedule, PreferNoSchedule, or NoExecute, when empty all
+	// effects are tolerated
+	Effect string `hcl:"effect,optional" json:"effect,omitempty"`
+}
+
 type KubeConfig struct {
 	ConfigPath        string `hcl:"path" json:"path"`                             // path to the kubeconfig file
 	CA                string `hcl:"ca" json:"ca"`                                 // base64 encoded ca certificate
@@ -86,8 +212,63 @@ func (k *Cluster) Process() error {
 		k.Image = &ctypes.Image{Name: fmt.Sprintf("%s:%s", k3sBaseImage, k3sBaseVersion)}
 	}
 
+	err := config.ValidateDuration(k, "timeout", k.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if k.Image.Platform != "" && len(strings.Split(k.Image.Platform, "/")) < 2 {
+		return fmt.Errorf("invalid platform %q for resource %s, platform must be in the format os/arch[/variant] i.e. linux/arm64", k.Image.Platform, k.Meta.ID)
+	}
+
+	if k.Connector != nil {
+		for _, t := range k.Connector.Tolerations {
+			switch t.Operator {
+			case "", "Equal", "Exists":
+			default:
+				return fmt.Errorf("invalid operator %q for connector toleration in resource %s, must be Exists or Equal", t.Operator, k.Meta.ID)
+			}
+
+			switch t.Effect {
+			case "", "NoSchedule", "PreferNoSchedule", "NoExecute":
+			default:
+				return fmt.Errorf("invalid effect %q for connector toleration in resource %s, must be NoSchedule, PreferNoSchedule, or NoExecute", t.Effect, k.Meta.ID)
+			}
+
+			if t.Operator == "Exists" && t.Value != "" {
+				return fmt.Errorf("connector toleration with operator Exists must not set a value in resource %s", k.Meta.ID)
+			}
+		}
+
+		if pr := k.Connector.Probe; pr != nil {
+			err := validateProbe(k, pr)
+			if err != nil {
+				return err
+			}
+		}
+
+		if cr := k.Connector.Resources; cr != nil {
+			err := validateConnectorResources(k, cr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if k.Config != nil && k.Config.ContainerdConfig != nil {
+		for _, m := range k.Config.ContainerdConfig.Mirrors {
+			if len(m.Endpoints) == 0 {
+				return fmt.Errorf("mirror %s in resource %s must specify at least one endpoint", m.Registry, k.Meta.ID)
+			}
+		}
+	}
+
+	// make sure mount paths are absolute when type is bind, relative paths
+	// are resolved against the file containing the resource
 	for i, v := range k.Volumes {
-		k.Volumes[i].Source = utils.EnsureAbsolute(v.Source, k.Meta.File)
+		if v.Type == "" || v.Type == "bind" {
+			k.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), k.Meta.File)
+		}
 	}
 
 	// do we have an existing resource in the state?
@@ -102,9 +283,16 @@ func (k *Cluster) Process() error {
 			k.ContainerName = kstate.ContainerName
 			k.APIPort = kstate.APIPort
 			k.ConnectorPort = kstate.ConnectorPort
+			k.ConnectorImage = kstate.ConnectorImage
+			k.ConnectorChecksum = kstate.ConnectorChecksum
 			k.ExternalIP = kstate.ExternalIP
 			k.KubeConfig = kstate.KubeConfig
 
+			// keep the generated token so that the cluster can be rejoined
+			if k.ClusterToken == "" {
+				k.ClusterToken = kstate.ClusterToken
+			}
+
 			// add the network addresses
 			for _, a := range kstate.Networks {
 				for i, m := range k.Networks {
@@ -132,3 +320,78 @@ func (k *Cluster) Process() error {
 
 	return nil
 }
+
+// validateProbe checks the durations for the connector probe, Kubernetes
+// expresses probe timings in whole seconds so the period and timeout must be
+// at least one second
+func validateProbe(k *Cluster, pr *ConnectorProbe) error {
+	fields := []struct {
+		name  string
+		value string
+		min   time.Duration
+	}{
+		{"connector.probe.initial_delay", pr.InitialDelay, 0},
+		{"connector.probe.period", pr.Period, time.Second},
+		{"connector.probe.timeout", pr.Timeout, time.Second},
+	}
+
+	for _, f := range fields {
+		err := config.ValidateDuration(k, f.name, f.value)
+		if err != nil {
+			return err
+		}
+
+		if f.value == "" {
+			continue
+		}
+
+		d, _ := time.ParseDuration(f.value)
+		if d < f.min {
+			return fmt.Errorf("invalid duration %q for %s in resource %s, must be at least %s", f.value, f.name, k.Meta.ID, f.min)
+		}
+	}
+
+	if pr.FailureThreshold < 0 {
+		return fmt.Errorf("invalid failure_threshold %d for connector probe in resource %s, must be greater than 0", pr.FailureThreshold, k.Meta.ID)
+	}
+
+	return nil
+}
+
+// validateConnectorResources checks that the connector resources are valid
+// Kubernetes quantities and that the requests do not exceed the limits
+func validateConnectorResources(k *Cluster, cr *ConnectorResources) error {
+	pairs := []struct {
+		name    string
+		request string
+		limit   string
+	}{
+		{"cpu", cr.CPURequest, cr.CPULimit},
+		{"memory", cr.MemoryRequest, cr.MemoryLimit},
+	}
+
+	for _, p := range pairs {
+		var req, lim resource.Quantity
+		var err error
+
+		if p.request != "" {
+			req, err = resource.ParseQuantity(p.request)
+			if err != nil {
+				return fmt.Errorf("invalid %s_request %q for connector in resource %s: %w", p.name, p.request, k.Meta.ID, err)
+			}
+		}
+
+		if p.limit != "" {
+			lim, err = resource.ParseQuantity(p.limit)
+			if err != nil {
+				return fmt.Errorf("invalid %s_limit %q for connector in resource %s: %w", p.name, p.limit, k.Meta.ID, err)
+			}
+		}
+
+		if p.request != "" && p.limit != "" && req.Cmp(lim) > 0 {
+			return fmt.Errorf("%s_request %s for connector in resource %s must not be greater than %s_limit %s", p.name, p.request, k.Meta.ID, p.name, p.limit)
+		}
+	}
+
+	return nil
+}
@@ -34,6 +34,26 @@ func TestK8sClusterProcessSetsAbsolute(t *testing.T) {
 	require.Equal(t, wd, c.Volumes[0].Source)
 }
 
+func TestK8sClusterProcessReturnsErrorForInvalidTimeout(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Timeout:      "5 minutes",
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid duration")
+}
+
+func TestK8sClusterProcessReturnsErrorForInvalidPlatform(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Image:        &ctypes.Image{Name: "shipyardrun/k3s:v1.27.4", Platform: "arm64"},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid platform")
+}
+
 func TestK8sClusterSetsOutputsFromState(t *testing.T) {
 	testutils.SetupState(t, `
 {
@@ -83,3 +103,63 @@ func TestK8sClusterSetsOutputsFromState(t *testing.T) {
 	require.Equal(t, "10.5.0.2", c.Networks[0].AssignedAddress)
 	require.Equal(t, "cloud", c.Networks[0].Name)
 }
+
+func TestK8sClusterProcessReturnsErrorForInvalidTolerationEffect(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Connector: &ConnectorConfig{
+			Tolerations: []Toleration{{Key: "dedicated", Operator: "Exists", Effect: "Never"}},
+		},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "invalid effect")
+}
+
+func TestK8sClusterProcessReturnsErrorForInvalidProbePeriod(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Connector: &ConnectorConfig{
+			Probe: &ConnectorProbe{Period: "500ms"},
+		},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "connector.probe.period")
+}
+
+func TestK8sClusterProcessReturnsErrorForInvalidConnectorResources(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Connector: &ConnectorConfig{
+			Resources: &ConnectorResources{MemoryRequest: "lots"},
+		},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "memory_request")
+}
+
+func TestK8sClusterProcessReturnsErrorWhenConnectorRequestExceedsLimit(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Connector: &ConnectorConfig{
+			Resources: &ConnectorResources{CPURequest: "2", CPULimit: "500m"},
+		},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "cpu_request")
+}
+
+func TestK8sClusterProcessReturnsErrorForMirrorWithoutEndpoints(t *testing.T) {
+	c := &Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.test", File: "./"}},
+		Config: &ClusterConfig{
+			ContainerdConfig: &ContainerdConfig{Mirrors: []Mirror{{Registry: "docker.io"}}},
+		},
+	}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "docker.io")
+}
@@ -1,6 +1,8 @@
 package k8s
 
 import (
+	"fmt"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 	"github.com/jumppad-labs/jumppad/pkg/config/resources/healthcheck"
@@ -19,6 +21,11 @@ type Config struct {
 
 	// Path of a file or directory of Kubernetes config files to apply
 	Paths []string `hcl:"paths" validator:"filepath" json:"paths"`
+	// Namespace when set, all resources that do not declare a namespace are created in
+	// this namespace, resources that declare a different namespace cause an error
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+	// CreateNamespace when set to true creates the namespace before applying the config
+	CreateNamespace bool `hcl:"create_namespace,optional" json:"create_namespace,omitempty"`
 	// WaitUntilReady when set to true waits until all resources have been created and are in a "Running" state
 	WaitUntilReady bool `hcl:"wait_until_ready" json:"wait_until_ready"`
 
@@ -33,6 +40,16 @@ type Config struct {
 }
 
 func (k *Config) Process() error {
+	if k.HealthCheck != nil {
+		if err := config.ValidateDuration(k, "health_check.timeout", k.HealthCheck.Timeout); err != nil {
+			return err
+		}
+	}
+
+	if k.CreateNamespace && k.Namespace == "" {
+		return fmt.Errorf("create_namespace requires the namespace parameter to be set")
+	}
+
 	// make all the paths absolute
 	for i, p := range k.Paths {
 		k.Paths[i] = utils.EnsureAbsolute(p, k.Meta.File)
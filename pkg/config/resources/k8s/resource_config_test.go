@@ -24,3 +24,13 @@ func TestK8sConfigProcessSetsAbsolute(t *testing.T) {
 	require.Equal(t, path.Join(wd, "one.yaml"), k.Paths[0])
 	require.Equal(t, path.Join(wd, "two.yaml"), k.Paths[1])
 }
+
+func TestK8sConfigProcessCreateNamespaceWithoutNamespaceReturnsError(t *testing.T) {
+	k := &Config{
+		ResourceBase:    types.ResourceBase{Meta: types.Meta{File: "./"}},
+		CreateNamespace: true,
+	}
+
+	err := k.Process()
+	require.Error(t, err)
+}
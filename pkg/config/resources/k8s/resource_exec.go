@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+)
+
+// TypeK8sExec defines the string type for the Kubernetes exec resource
+const TypeK8sExec string = "k8s_exec"
+const TypeKubernetesExec string = "kubernetes_exec"
+
+// Exec executes a command inside a running pod in a Kubernetes cluster
+type Exec struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Cluster Cluster `hcl:"cluster" json:"cluster"`
+
+	// Namespace of the pod, default "default"
+	Namespace string `hcl:"namespace,optional" json:"namespace,omitempty"`
+	// Selector is a label selector used to find the pod, the command is executed
+	// in the first running pod that matches
+	Selector string `hcl:"selector" json:"selector"`
+	// Container in the pod to execute the command, when not set the default container is used
+	Container string `hcl:"container,optional" json:"container,omitempty"`
+	// Command to execute
+	Command []string `hcl:"command" json:"command"`
+
+	// output
+
+	// Stdout contains the output written to stdout by the command
+	Stdout string `hcl:"stdout,optional" json:"stdout,omitempty"`
+	// Stderr contains the output written to stderr by the command
+	Stderr string `hcl:"stderr,optional" json:"stderr,omitempty"`
+	// ExitCode of the command
+	ExitCode int `hcl:"exit_code,optional" json:"exit_code,omitempty"`
+	// Checksum of the command that was last executed, used to detect when the
+	// command changes
+	Checksum string `hcl:"checksum,optional" json:"checksum,omitempty"`
+}
+
+func (e *Exec) Process() error {
+	if e.Namespace == "" {
+		e.Namespace = "default"
+	}
+
+	if len(e.Command) == 0 {
+		return fmt.Errorf("command must contain at least one element")
+	}
+
+	cfg, err := config.LoadState()
+	if err == nil {
+		// try and find the resource in the state
+		r, _ := cfg.FindResource(e.Meta.ID)
+		if r != nil {
+			state := r.(*Exec)
+			e.Stdout = state.Stdout
+			e.Stderr = state.Stderr
+			e.ExitCode = state.ExitCode
+			e.Checksum = state.Checksum
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	config.RegisterResource(TypeK8sExec, &Exec{}, &ExecProvider{})
+}
+
+func TestK8sExecProcessSetsDefaultNamespace(t *testing.T) {
+	e := &Exec{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+		Command:      []string{"ls"},
+	}
+
+	err := e.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "default", e.Namespace)
+}
+
+func TestK8sExecProcessWithoutCommandReturnsError(t *testing.T) {
+	e := &Exec{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{File: "./"}},
+	}
+
+	err := e.Process()
+	require.Error(t, err)
+}
+
+func TestK8sExecSetsOutputsFromState(t *testing.T) {
+	testutils.SetupState(t, `
+{
+  "blueprint": null,
+  "resources": [
+  {
+      "meta": {
+      "id": "resource.k8s_exec.test",
+      "name": "test",
+      "type": "k8s_exec"
+      },
+      "stdout": "hello",
+      "exit_code": 2,
+      "checksum": "abc"
+  }
+  ]
+}`)
+
+	e := &Exec{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_exec.test"}},
+		Command:      []string{"ls"},
+	}
+
+	err := e.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "hello", e.Stdout)
+	require.Equal(t, 2, e.ExitCode)
+	require.Equal(t, "abc", e.Checksum)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strconv"
 
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/network"
@@ -47,8 +48,16 @@ func (p *Provider) Create(ctx context.Context) error {
 		return nil
 	}
 
+	if p.config.External {
+		return p.attachExternal()
+	}
+
 	p.log.Info("Creating Network", "ref", p.config.Meta.ID)
 
+	if p.config.MTU < 0 || p.config.MTU > 65535 {
+		return fmt.Errorf("unable to create network %s, invalid mtu %d", p.config.Meta.Name, p.config.MTU)
+	}
+
 	// validate the subnet
 	_, cidr, err := net.ParseCIDR(p.config.Subnet)
 	if err != nil {
@@ -117,6 +126,11 @@ func (p *Provider) Destroy(ctx context.Context, force bool) error {
 		return nil
 	}
 
+	if p.config.External {
+		p.log.Debug("Skipping destroy, network is external", "ref", p.config.Meta.ID)
+		return nil
+	}
+
 	p.log.Info("Destroy Network", "ref", p.config.Meta.ID)
 
 	// check network exists if so remove
@@ -183,6 +197,7 @@ func (p *Provider) createWithDriver(driver string) error {
 			"id":         p.config.Meta.ID,
 		},
 		Attachable: true,
+		Options:    p.driverOptions(),
 	}
 
 	_, err := p.client.NetworkCreate(context.Background(), p.config.Meta.Name, opts)
@@ -190,6 +205,55 @@ func (p *Provider) createWithDriver(driver string) error {
 	return err
 }
 
+// attachExternal ensures that an externally created network exists and sets
+// the subnet from the existing network
+func (p *Provider) attachExternal() error {
+	p.log.Info("Using external Network", "ref", p.config.Meta.ID)
+
+	name := p.config.Name
+	if name == "" {
+		name = p.config.Meta.Name
+	}
+
+	nets, err := p.getNetworks(name)
+	if err != nil {
+		return fmt.Errorf("unable to list existing networks: %w", err)
+	}
+
+	for _, n := range nets {
+		// the name filter matches partial names
+		if n.Name != name {
+			continue
+		}
+
+		if len(n.IPAM.Config) > 0 {
+			p.config.Subnet = n.IPAM.Config[0].Subnet
+		}
+
+		p.config.Name = n.Name
+		p.config.EnableIPv6 = n.EnableIPv6
+
+		return nil
+	}
+
+	return fmt.Errorf("unable to find external network %s, ref: %s", name, p.config.Meta.ID)
+}
+
+// driverOptions returns the driver options for the network, the mtu is
+// mapped to the generic Docker driver option
+func (p *Provider) driverOptions() map[string]string {
+	opts := map[string]string{}
+	for k, v := range p.config.Options {
+		opts[k] = v
+	}
+
+	if p.config.MTU > 0 {
+		opts["com.docker.network.driver.mtu"] = strconv.Itoa(p.config.MTU)
+	}
+
+	return opts
+}
+
 func (p *Provider) getNetworks(name string) ([]network.Summary, error) {
 	args := filters.NewArgs()
 	args.Add("name", name)
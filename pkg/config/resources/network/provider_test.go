@@ -181,3 +181,97 @@ func TestCreateWithOverlappingSubnetReturnsError(t *testing.T) {
 	err := p.Create(context.Background())
 	assert.Error(t, err)
 }
+
+func TestNetworkCreatesWithMTUAndOptions(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+	c.MTU = 1400
+	c.Options = map[string]string{"com.docker.network.bridge.enable_icc": "true"}
+
+	md, p := setupNetworkTests(t, c)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	params := md.Calls[1].Arguments
+	nco := params[2].(network.CreateOptions)
+
+	assert.Equal(t, "1400", nco.Options["com.docker.network.driver.mtu"])
+	assert.Equal(t, "true", nco.Options["com.docker.network.bridge.enable_icc"])
+}
+
+func TestNetworkWithInvalidMTUReturnsError(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "testnetwork"}},
+	}
+	c.Subnet = "10.1.2.0/24"
+	c.MTU = -1
+
+	md, p := setupNetworkTests(t, c)
+
+	err := p.Create(context.Background())
+	assert.Error(t, err)
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNetworkExternalDoesNotCreate(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "bridge"}},
+		External:     true,
+	}
+
+	md, p := setupNetworkTests(t, c)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, "10.8.2.0/24", c.Subnet)
+}
+
+func TestNetworkExternalUsesExplicitName(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "compose"}},
+		External:     true,
+		Name:         "bridge",
+	}
+
+	md, p := setupNetworkTests(t, c)
+
+	err := p.Create(context.Background())
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, "10.8.2.0/24", c.Subnet)
+}
+
+func TestNetworkExternalReturnsErrorWhenNotExists(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "compose_default"}},
+		External:     true,
+	}
+
+	md, p := setupNetworkTests(t, c)
+
+	err := p.Create(context.Background())
+	assert.ErrorContains(t, err, "compose_default")
+
+	md.AssertNotCalled(t, "NetworkCreate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNetworkExternalDoesNotDestroy(t *testing.T) {
+	c := &Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{Name: "bridge"}},
+		External:     true,
+	}
+
+	md, p := setupNetworkTests(t, c)
+	md.On("NetworkRemove", mock.Anything, mock.Anything).Return(nil)
+
+	err := p.Destroy(context.Background(), false)
+	assert.NoError(t, err)
+
+	md.AssertNotCalled(t, "NetworkRemove", mock.Anything, mock.Anything)
+}
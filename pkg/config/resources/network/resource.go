@@ -1,7 +1,10 @@
 package network
 
 import (
+	"fmt"
+
 	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config"
 )
 
 // TypeNetwork is the string resource type for Network resources
@@ -18,6 +21,50 @@ type Network struct {
 	// embedded type holding name, etc
 	types.ResourceBase `hcl:",remain"`
 
-	Subnet     string `hcl:"subnet" json:"subnet"`
+	Subnet     string `hcl:"subnet,optional" json:"subnet"`
 	EnableIPv6 bool   `hcl:"enable_ipv6,optional" json:"enable_ipv6"`
+
+	// External networks are not created or removed by jumppad, the resource
+	// name must match the name of an existing Docker network
+	External bool `hcl:"external,optional" json:"external,omitempty"`
+	// Name is the name of the existing Docker network for external networks,
+	// when not set the resource name is used. Containers and clusters can
+	// reference the external network using this name.
+	Name string `hcl:"name,optional" json:"name,omitempty"`
+
+	// MTU sets the maximum transmission unit for the network, when not set
+	// the Docker default of 1500 is used
+	MTU int `hcl:"mtu,optional" json:"mtu,omitempty"`
+	// Options are additional driver options passed to Docker when creating
+	// the network
+	Options map[string]string `hcl:"options,optional" json:"options,omitempty"`
+}
+
+func (n *Network) Process() error {
+	if !n.External && n.Subnet == "" {
+		return fmt.Errorf("subnet is required for network %s, only external networks can omit the subnet", n.Meta.ID)
+	}
+
+	if !n.External && n.Name != "" {
+		return fmt.Errorf("name can only be set for external networks, network %s", n.Meta.ID)
+	}
+
+	if n.External && n.Name == "" {
+		n.Name = n.Meta.Name
+	}
+
+	// external networks read the subnet from the existing network, restore
+	// it from the state so dependent resources can reference it
+	if n.External {
+		cfg, err := config.LoadState()
+		if err == nil {
+			r, _ := cfg.FindResource(n.Meta.ID)
+			if r != nil && n.Subnet == "" {
+				state := r.(*Network)
+				n.Subnet = state.Subnet
+			}
+		}
+	}
+
+	return nil
 }
@@ -5,7 +5,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/rand"
 	"os"
 	"path"
 	"path/filepath"
@@ -361,7 +360,12 @@ func (p *ClusterProvider) createNomad(ctx context.Context) error {
 	}
 
 	// set the API server port to a random number
-	p.config.ConnectorPort = rand.Intn(utils.MaxRandomPort-utils.MinRandomPort) + utils.MinRandomPort
+	port, err := utils.RandomPort()
+	if err != nil {
+		return err
+	}
+
+	p.config.ConnectorPort = port
 	p.config.ConfigDir = path.Join(utils.JumppadHome(), strings.Replace(p.config.Meta.ID, ".", "_", -1), "config")
 
 	// set the external IP to the address where the docker daemon is running
@@ -491,7 +495,7 @@ func (p *ClusterProvider) createServerNode(img ctypes.Image, volumeID string, is
 	cc.Volumes = []ctypes.Volume{
 		{
 			Source:      volumeID,
-			Destination: "/cache",
+			Destination: utils.ImageVolumeMountPath,
 			Type:        "volume",
 		},
 		{
@@ -614,7 +618,7 @@ func (p *ClusterProvider) createClientNode(id string, image, volumeID, serverID
 	cc.Volumes = []ctypes.Volume{
 		{
 			Source:      volumeID,
-			Destination: "/cache",
+			Destination: utils.ImageVolumeMountPath,
 			Type:        "volume",
 		},
 		{
@@ -103,7 +103,7 @@ func (n *NomadCluster) Process() error {
 	for i, v := range n.Volumes {
 		if v.Type == "" || v.Type == "bind" {
 			// only change path for bind mounts
-			n.Volumes[i].Source = utils.EnsureAbsolute(v.Source, n.Meta.File)
+			n.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), n.Meta.File)
 		}
 	}
 
@@ -31,6 +31,12 @@ type NomadJob struct {
 }
 
 func (n *NomadJob) Process() error {
+	if n.HealthCheck != nil {
+		if err := config.ValidateDuration(n, "health_check.timeout", n.HealthCheck.Timeout); err != nil {
+			return err
+		}
+	}
+
 	// make all the paths absolute
 	for i, p := range n.Paths {
 		n.Paths[i] = utils.EnsureAbsolute(p, n.Meta.File)
@@ -3,6 +3,7 @@ package random
 import (
 	"context"
 	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -49,6 +50,7 @@ func (p *RandomIDProvider) Create(ctx context.Context) error {
 	bigInt.SetBytes(bytes)
 	dec := bigInt.String()
 
+	p.config.Base64 = base64.RawURLEncoding.EncodeToString(bytes)
 	p.config.Hex = hex
 	p.config.Dec = dec
 
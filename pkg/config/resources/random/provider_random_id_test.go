@@ -0,0 +1,37 @@
+package random
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomIDCreatesEncodings(t *testing.T) {
+	c := &RandomID{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.random_id.test"}},
+		ByteLength:   16,
+	}
+
+	p := &RandomIDProvider{}
+	err := p.Init(c, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	b, err := hex.DecodeString(c.Hex)
+	require.NoError(t, err)
+	require.Len(t, b, 16)
+
+	b64, err := base64.RawURLEncoding.DecodeString(c.Base64)
+	require.NoError(t, err)
+	require.Equal(t, b, b64)
+
+	require.Equal(t, new(big.Int).SetBytes(b).String(), c.Dec)
+}
@@ -67,6 +67,17 @@ func (p *RandomPasswordProvider) Create(ctx context.Context) error {
 
 	result = make([]byte, 0, p.config.Length)
 
+	// a custom character set replaces all other options
+	if p.config.Charset != "" {
+		s, err := generateRandomBytes(&p.config.Charset, p.config.Length)
+		if err != nil {
+			return err
+		}
+
+		p.config.Value = string(s)
+		return nil
+	}
+
 	for k, v := range minMapping {
 		s, err := generateRandomBytes(&k, v)
 		if err != nil {
@@ -0,0 +1,90 @@
+package random
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func setupRandomPassword(t *testing.T, c *RandomPassword) *RandomPasswordProvider {
+	c.ResourceBase = types.ResourceBase{Meta: types.Meta{ID: "resource.random_password.test"}}
+
+	if c.Special == nil {
+		c.Special = boolPointer(true)
+	}
+
+	if c.Numeric == nil {
+		c.Numeric = boolPointer(true)
+	}
+
+	if c.Lower == nil {
+		c.Lower = boolPointer(true)
+	}
+
+	if c.Upper == nil {
+		c.Upper = boolPointer(true)
+	}
+
+	p := &RandomPasswordProvider{}
+	err := p.Init(c, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	return p
+}
+
+func TestRandomPasswordCreatesPasswordWithLength(t *testing.T) {
+	c := &RandomPassword{Length: 32}
+	p := setupRandomPassword(t, c)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, c.Value, 32)
+}
+
+func TestRandomPasswordCreatesPasswordWithMinimums(t *testing.T) {
+	c := &RandomPassword{
+		Length:     16,
+		Special:    boolPointer(false),
+		MinNumeric: 4,
+		MinUpper:   4,
+	}
+	p := setupRandomPassword(t, c)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, c.Value, 16)
+	require.GreaterOrEqual(t, countChars(c.Value, "0123456789"), 4)
+	require.GreaterOrEqual(t, countChars(c.Value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), 4)
+}
+
+func TestRandomPasswordCreatesPasswordFromCharset(t *testing.T) {
+	c := &RandomPassword{
+		Length:     64,
+		Charset:    "ab",
+		MinSpecial: 10,
+	}
+	p := setupRandomPassword(t, c)
+
+	err := p.Create(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, c.Value, 64)
+	require.Equal(t, 64, countChars(c.Value, "ab"))
+}
+
+func countChars(s, chars string) int {
+	n := 0
+	for _, r := range s {
+		if strings.ContainsRune(chars, r) {
+			n++
+		}
+	}
+
+	return n
+}
@@ -1,6 +1,8 @@
 package random
 
 import (
+	"fmt"
+
 	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/jumppad-labs/jumppad/pkg/config"
 )
@@ -16,6 +18,10 @@ type RandomPassword struct {
 
 	OverrideSpecial string `hcl:"override_special,optional" json:"override_special"`
 
+	// Charset when set replaces the default character set used to generate the password,
+	// special, numeric, lower and upper settings and minimums are ignored
+	Charset string `hcl:"charset,optional" json:"charset,omitempty"`
+
 	Special    *bool `hcl:"special,optional" json:"special"`
 	Numeric    *bool `hcl:"numeric,optional" json:"numeric"`
 	Lower      *bool `hcl:"lower,optional" json:"lower"`
@@ -30,6 +36,10 @@ type RandomPassword struct {
 }
 
 func (c *RandomPassword) Process() error {
+	if c.Length < 1 {
+		return fmt.Errorf("length must be greater than 0")
+	}
+
 	if c.Special == nil {
 		c.Special = boolPointer(true)
 	}
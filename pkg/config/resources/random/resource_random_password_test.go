@@ -0,0 +1,35 @@
+package random
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomPasswordProcessReturnsErrorWhenLengthInvalid(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	c := &RandomPassword{ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.random_password.test"}}}
+
+	err := c.Process()
+	require.ErrorContains(t, err, "length must be greater than 0")
+}
+
+func TestRandomPasswordProcessSetsDefaults(t *testing.T) {
+	testutils.SetupState(t, "")
+
+	c := &RandomPassword{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.random_password.test"}},
+		Length:       8,
+	}
+
+	err := c.Process()
+	require.NoError(t, err)
+
+	require.True(t, *c.Special)
+	require.True(t, *c.Numeric)
+	require.True(t, *c.Lower)
+	require.True(t, *c.Upper)
+}
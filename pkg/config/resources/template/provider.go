@@ -41,36 +41,9 @@ func (p *TemplateProvider) Create(ctx context.Context) error {
 		return nil
 	}
 
-	// check the template is valid
-	if p.config.Source == "" {
-		return fmt.Errorf("template source empty")
-	}
-
-	output := p.config.Source
-	if p.config.Variables != nil {
-
-		vars := parseVars(p.config.Variables)
-
-		tmpl, err := raymond.Parse(p.config.Source)
-		if err != nil {
-			return fmt.Errorf("error parsing template: %s", err)
-		}
-
-		tmpl.RegisterHelpers(map[string]interface{}{
-			"quote": func(in string) string {
-				return fmt.Sprintf(`"%s"`, in)
-			},
-			"trim": func(in string) string {
-				return strings.TrimSpace(in)
-			},
-		})
-
-		result, err := tmpl.Exec(vars)
-		if err != nil {
-			return fmt.Errorf("error processing template: %s", err)
-		}
-
-		output = result
+	output, err := p.render()
+	if err != nil {
+		return err
 	}
 
 	// gemerate a checksum from the result
@@ -152,10 +125,79 @@ func (p *TemplateProvider) Refresh(ctx context.Context) error {
 	return p.Create(ctx)
 }
 
+// Changed returns true when the rendered template differs from the
+// last rendered output or when the destination file has been removed
 func (p *TemplateProvider) Changed() (bool, error) {
+	output, err := p.render()
+	if err != nil {
+		return false, err
+	}
+
+	cs, err := utils.ChecksumFromInterface(output)
+	if err != nil {
+		return false, fmt.Errorf("unable to generate checksum for template: %s", err)
+	}
+
+	if cs != p.config.Checksum {
+		p.log.Debug("Template has changed, needs refresh", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
+	if _, err := os.Stat(p.config.Destination); err != nil {
+		p.log.Debug("Template destination does not exist, needs refresh", "ref", p.config.Meta.ID)
+		return true, nil
+	}
+
 	return false, nil
 }
 
+// render reads the template source and processes it with the
+// template variables
+func (p *TemplateProvider) render() (string, error) {
+	// check the template is valid
+	if p.config.Source == "" {
+		return "", fmt.Errorf("template source empty")
+	}
+
+	// source can be a path to a file or the template contents
+	source := p.config.Source
+	if fi, err := os.Stat(source); err == nil && !fi.IsDir() {
+		d, err := os.ReadFile(source)
+		if err != nil {
+			return "", fmt.Errorf("unable to read template source: %s", err)
+		}
+
+		source = strings.Replace(string(d), "\r\n", "\n", -1)
+	}
+
+	if p.config.Variables == nil {
+		return source, nil
+	}
+
+	vars := parseVars(p.config.Variables)
+
+	tmpl, err := raymond.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("error parsing template: %s", err)
+	}
+
+	tmpl.RegisterHelpers(map[string]interface{}{
+		"quote": func(in string) string {
+			return fmt.Sprintf(`"%s"`, in)
+		},
+		"trim": func(in string) string {
+			return strings.TrimSpace(in)
+		},
+	})
+
+	result, err := tmpl.Exec(vars)
+	if err != nil {
+		return "", fmt.Errorf("error processing template: %s", err)
+	}
+
+	return result, nil
+}
+
 // parseVars converts a map[string]cty.Value into map[string]interface
 // where the interface are generic go types like string, number, bool, slice, map
 //
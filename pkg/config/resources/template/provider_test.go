@@ -0,0 +1,72 @@
+package template
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func setupTemplateProvider(t *testing.T, source string) (*Template, *TemplateProvider) {
+	tmpl := &Template{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.template.test"}},
+		Source:       source,
+		Destination:  filepath.Join(t.TempDir(), "out.hcl"),
+		Variables: map[string]cty.Value{
+			"name": cty.StringVal("consul"),
+		},
+	}
+
+	p := &TemplateProvider{}
+	err := p.Init(tmpl, logger.NewTestLogger(t))
+	require.NoError(t, err)
+
+	return tmpl, p
+}
+
+func TestRenderReadsTemplateFromFileSource(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "config.hcl.tmpl")
+	err := os.WriteFile(src, []byte("name = \"{{name}}\"\r\n"), 0644)
+	require.NoError(t, err)
+
+	_, p := setupTemplateProvider(t, src)
+
+	out, err := p.render()
+	require.NoError(t, err)
+	require.Equal(t, "name = \"consul\"\n", out)
+}
+
+func TestRenderUsesInlineSource(t *testing.T) {
+	_, p := setupTemplateProvider(t, `name = "{{name}}"`)
+
+	out, err := p.render()
+	require.NoError(t, err)
+	require.Equal(t, `name = "consul"`, out)
+}
+
+func TestChangedReturnsTrueWhenFileSourceChanges(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "config.hcl.tmpl")
+	err := os.WriteFile(src, []byte(`name = "{{name}}"`), 0644)
+	require.NoError(t, err)
+
+	_, p := setupTemplateProvider(t, src)
+
+	err = p.Create(context.Background())
+	require.NoError(t, err)
+
+	changed, err := p.Changed()
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	err = os.WriteFile(src, []byte(`updated = "{{name}}"`), 0644)
+	require.NoError(t, err)
+
+	changed, err = p.Changed()
+	require.NoError(t, err)
+	require.True(t, changed)
+}
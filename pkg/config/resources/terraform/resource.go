@@ -52,7 +52,7 @@ func (t *Terraform) Process() error {
 	for i, v := range t.Volumes {
 		// make sure mount paths are absolute when type is bind, unless this is the docker sock
 		if v.Type == "" || v.Type == "bind" {
-			t.Volumes[i].Source = utils.EnsureAbsolute(v.Source, t.Meta.File)
+			t.Volumes[i].Source = utils.EnsureAbsolute(utils.ExpandPath(v.Source), t.Meta.File)
 		}
 	}
 
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// JSONSchemaDraft is the JSON schema version used for the generated schema
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON schema description of a resource, attribute or block
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+
+	// Block is true when the property is defined as a HCL block rather than
+	// an attribute
+	Block bool `json:"x-hcl-block,omitempty"`
+	// Label is the name of the HCL label for the block
+	Label string `json:"x-hcl-label,omitempty"`
+}
+
+var ctyValueType = reflect.TypeOf(cty.Value{})
+
+// GenerateSchema returns a JSON schema containing a definition for every
+// registered resource type, the schema is generated from the hcl struct tags
+func GenerateSchema() *Schema {
+	s := &Schema{
+		Schema:      JSONSchemaDraft,
+		Title:       "jumppad",
+		Definitions: map[string]*Schema{},
+	}
+
+	for name, r := range registeredTypes {
+		rs := schemaForType(reflect.TypeOf(r))
+		rs.Title = name
+
+		s.Definitions[name] = rs
+	}
+
+	return s
+}
+
+// schemaForType returns the schema for the given type, struct types are
+// converted to objects containing all the fields that have a hcl tag
+func schemaForType(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == ctyValueType {
+		// cty values can hold any type
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaForType(t.Elem())}
+	case reflect.Struct:
+		return schemaForStruct(t)
+	}
+
+	return &Schema{}
+}
+
+func schemaForStruct(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, ok := f.Tag.Lookup("hcl")
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		name := parts[0]
+		kind := ""
+		if len(parts) > 1 {
+			kind = parts[1]
+		}
+
+		switch kind {
+		case "remain":
+			// the embedded resource base contains the meta properties
+			// only depends_on and disabled can be set by the user
+			s.Properties["depends_on"] = &Schema{Type: "array", Items: &Schema{Type: "string"}}
+			s.Properties["disabled"] = &Schema{Type: "boolean"}
+
+		case "label":
+			s.Label = name
+
+		case "block":
+			fs := schemaForType(f.Type)
+			fs.Block = true
+
+			s.Properties[name] = fs
+
+			// a block is required unless it is a pointer or a list
+			if f.Type.Kind() == reflect.Struct {
+				s.Required = append(s.Required, name)
+			}
+
+		case "optional":
+			s.Properties[name] = schemaForType(f.Type)
+
+		default:
+			s.Properties[name] = schemaForType(f.Type)
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	sort.Strings(s.Required)
+
+	return s
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaTestPort struct {
+	Local int    `hcl:"local"`
+	Host  string `hcl:"host,optional"`
+}
+
+type schemaTestImage struct {
+	Name string `hcl:"name"`
+}
+
+type schemaTestResource struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Command     []string          `hcl:"command,optional"`
+	Environment map[string]string `hcl:"environment,optional"`
+	Privileged  bool              `hcl:"privileged,optional"`
+	Image       schemaTestImage   `hcl:"image,block"`
+	Ports       []schemaTestPort  `hcl:"port,block"`
+
+	Internal string `json:"internal"`
+}
+
+func TestGenerateSchemaAddsRegisteredTypes(t *testing.T) {
+	RegisterResource("schema_test", &schemaTestResource{}, nil)
+	t.Cleanup(func() {
+		delete(registeredTypes, "schema_test")
+	})
+
+	s := GenerateSchema()
+	require.Equal(t, JSONSchemaDraft, s.Schema)
+
+	r := s.Definitions["schema_test"]
+	require.NotNil(t, r)
+	require.Equal(t, "object", r.Type)
+	require.Equal(t, []string{"image"}, r.Required)
+
+	require.Equal(t, "array", r.Properties["command"].Type)
+	require.Equal(t, "string", r.Properties["command"].Items.Type)
+	require.Equal(t, "object", r.Properties["environment"].Type)
+	require.Equal(t, "string", r.Properties["environment"].AdditionalProperties.Type)
+	require.Equal(t, "boolean", r.Properties["privileged"].Type)
+	require.Equal(t, "boolean", r.Properties["disabled"].Type)
+	require.Equal(t, "array", r.Properties["depends_on"].Type)
+	require.NotContains(t, r.Properties, "internal")
+
+	require.True(t, r.Properties["image"].Block)
+	require.Equal(t, []string{"name"}, r.Properties["image"].Required)
+
+	require.True(t, r.Properties["port"].Block)
+	require.Equal(t, "array", r.Properties["port"].Type)
+	require.Equal(t, "integer", r.Properties["port"].Items.Properties["local"].Type)
+	require.Equal(t, []string{"local"}, r.Properties["port"].Items.Required)
+}
@@ -1,12 +1,30 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/zclconf/go-cty/cty"
 )
 
+// ValidateDuration checks that the value of the given field can be parsed as
+// a go duration, empty values are ignored as the field is optional
+func ValidateDuration(r types.Resource, field, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	_, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf(`invalid duration "%s" for %s in resource %s, durations are expressed as a number and unit i.e. 30s or 5m`, value, field, r.Metadata().ID)
+	}
+
+	return nil
+}
+
 // ParseVars converts a map[string]cty.Value into map[string]interface
 // where the interface are generic go types like string, number, bool, slice, map
 func ParseVars(value map[string]cty.Value) map[string]interface{} {
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/jumppad-labs/hclconfig/types"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -37,3 +38,19 @@ func TestProcessesTypes(t *testing.T) {
 
 	require.Equal(t, "abc", output["map"].(map[string]interface{})["foo"])
 }
+
+func TestValidateDurationWithValidValueReturnsNoError(t *testing.T) {
+	r := &types.ResourceBase{Meta: types.Meta{ID: "resource.exec.test"}}
+
+	require.NoError(t, ValidateDuration(r, "timeout", "30s"))
+	require.NoError(t, ValidateDuration(r, "timeout", ""))
+}
+
+func TestValidateDurationWithInvalidValueReturnsError(t *testing.T) {
+	r := &types.ResourceBase{Meta: types.Meta{ID: "resource.exec.test"}}
+
+	err := ValidateDuration(r, "timeout", "30x")
+	require.ErrorContains(t, err, `"30x"`)
+	require.ErrorContains(t, err, "timeout")
+	require.ErrorContains(t, err, "resource.exec.test")
+}
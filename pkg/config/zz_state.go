@@ -1,23 +1,77 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
 	"github.com/jumppad-labs/jumppad/pkg/utils"
 )
 
+// MaxStateBackups is the number of state backups that are kept
+var MaxStateBackups = 10
+
+// stateLogger is used to report resources that were dropped when recovering
+// a corrupt state file
+var stateLogger logger.Logger
+
+// SetStateLogger sets the logger used when loading the state
+func SetStateLogger(l logger.Logger) {
+	stateLogger = l
+}
+
+// StateRecoveredError is returned by LoadState when the state file is corrupt
+// and only the well formed resources could be recovered, the recovered state
+// is returned with the error so that it can be inspected or repaired
+type StateRecoveredError struct {
+	// Dropped describes the entries that could not be recovered
+	Dropped []string
+	Err     error
+}
+
+func (e *StateRecoveredError) Error() string {
+	return fmt.Sprintf("state file is corrupt, %d resources could not be recovered, run 'jumppad status --repair' to rewrite the state with the recovered resources: %s", len(e.Dropped), e.Err)
+}
+
+func (e *StateRecoveredError) Unwrap() error {
+	return e.Err
+}
+
+// LoadState reads the state file, when the state file is corrupt the well
+// formed resources are recovered and returned with a StateRecoveredError
 func LoadState() (*hclconfig.Config, error) {
 	d, err := os.ReadFile(utils.StatePath())
 	if err != nil {
-		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %s", err)
+		return hclconfig.NewConfig(), fmt.Errorf("unable to read state file: %w", err)
 	}
 
 	p := NewParser(nil, nil, nil)
 	c, err := p.UnmarshalJSON(d)
 	if err != nil {
-		return hclconfig.NewConfig(), fmt.Errorf("unable to unmarshal state file: %s", err)
+		// the state file may be corrupt, attempt to recover the resources
+		// that are well formed so that they can still be destroyed
+		rc, dropped, rerr := recoverState(d)
+		if rerr != nil {
+			return hclconfig.NewConfig(), fmt.Errorf("unable to unmarshal state file: %s", err)
+		}
+
+		if stateLogger != nil {
+			stateLogger.Warn("State file is corrupt, recovered resources", "recovered", len(rc.Resources), "dropped", len(dropped), "error", err)
+
+			for _, d := range dropped {
+				stateLogger.Warn("Dropped resource from corrupt state file", "resource", d)
+			}
+		}
+
+		return rc, &StateRecoveredError{Dropped: dropped, Err: err}
 	}
 
 	return c, nil
@@ -42,3 +96,237 @@ func SaveState(c *hclconfig.Config) error {
 
 	return nil
 }
+
+// RepairState loads the state recovering any well formed resources from a
+// corrupt state file and writes the cleaned state back to disk
+func RepairState() (*hclconfig.Config, error) {
+	c, err := LoadState()
+	if err != nil && !IsStateRecovered(err) {
+		return nil, err
+	}
+
+	err = SaveState(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// IsStateRecovered returns true when the error was returned because the
+// state file was corrupt and only part of the state could be recovered
+func IsStateRecovered(err error) bool {
+	var re *StateRecoveredError
+	return errors.As(err, &re)
+}
+
+// recoverState reads the resource entries from a corrupt state file one at
+// a time, returning a config containing the entries that could be parsed and
+// a description of the entries that were dropped
+func recoverState(d []byte) (*hclconfig.Config, []string, error) {
+	dec := json.NewDecoder(bytes.NewReader(d))
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, nil, fmt.Errorf("state file does not contain a JSON object")
+	}
+
+	c := hclconfig.NewConfig()
+	dropped := []string{}
+	found := false
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			break
+		}
+
+		if key, ok := t.(string); !ok || key != "resources" {
+			// skip any other values
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				break
+			}
+
+			continue
+		}
+
+		if t, err := dec.Token(); err != nil || t != json.Delim('[') {
+			break
+		}
+
+		found = true
+
+		for i := 0; dec.More(); i++ {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				// the remainder of the file can not be read
+				dropped = append(dropped, fmt.Sprintf("entry %d and any following entries, %s", i, err))
+				break
+			}
+
+			err := recoverResource(c, raw)
+			if err != nil {
+				dropped = append(dropped, fmt.Sprintf("%s, %s", resourceDescription(raw, i), err))
+			}
+		}
+
+		break
+	}
+
+	if !found {
+		return nil, nil, fmt.Errorf("state file does not contain any resources")
+	}
+
+	return c, dropped, nil
+}
+
+// recoverResource parses a single resource entry and adds it to the config
+func recoverResource(c *hclconfig.Config, raw json.RawMessage) error {
+	d := []byte(fmt.Sprintf(`{"resources": [%s]}`, raw))
+
+	p := NewParser(nil, nil, nil)
+	rc, err := p.UnmarshalJSON(d)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range rc.Resources {
+		err := c.AppendResource(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resourceDescription returns the id of the resource entry if it can be
+// determined, otherwise the position of the entry
+func resourceDescription(raw json.RawMessage, i int) string {
+	e := struct {
+		Meta struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"meta"`
+	}{}
+
+	json.Unmarshal(raw, &e)
+
+	switch {
+	case e.Meta.ID != "":
+		return e.Meta.ID
+	case e.Meta.Name != "" && e.Meta.Type != "":
+		return fmt.Sprintf("%s.%s", e.Meta.Type, e.Meta.Name)
+	}
+
+	return fmt.Sprintf("entry %d", i)
+}
+
+// StateBackupDir returns the directory where state backups are stored
+func StateBackupDir() string {
+	return filepath.Join(utils.StateDir(), "backups")
+}
+
+// BackupState writes a copy of the current state to the backup directory
+// and removes the oldest backups so that at most MaxStateBackups are kept,
+// the name of the backup is returned, when there is no state nothing is
+// written and an empty name is returned
+func BackupState() (string, error) {
+	d, err := os.ReadFile(utils.StatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("unable to read state file: %s", err)
+	}
+
+	err = os.MkdirAll(StateBackupDir(), os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("unable to create directory for state backups '%s', error: %s", StateBackupDir(), err)
+	}
+
+	name := fmt.Sprintf("state-%s.json", time.Now().UTC().Format("20060102T150405.000000000"))
+
+	err = os.WriteFile(filepath.Join(StateBackupDir(), name), d, os.ModePerm)
+	if err != nil {
+		return "", fmt.Errorf("unable to write state backup '%s', error: %s", name, err)
+	}
+
+	backups, err := ListStateBackups()
+	if err != nil {
+		return name, err
+	}
+
+	// backups are sorted newest first, remove any over the limit
+	if len(backups) > MaxStateBackups {
+		for _, b := range backups[MaxStateBackups:] {
+			os.Remove(filepath.Join(StateBackupDir(), b))
+		}
+	}
+
+	return name, nil
+}
+
+// ListStateBackups returns the names of the state backups, newest first
+func ListStateBackups() ([]string, error) {
+	files, err := os.ReadDir(StateBackupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+
+		return nil, fmt.Errorf("unable to read state backups: %s", err)
+	}
+
+	backups := []string{}
+	for _, f := range files {
+		if f.IsDir() || !strings.HasPrefix(f.Name(), "state-") || filepath.Ext(f.Name()) != ".json" {
+			continue
+		}
+
+		backups = append(backups, f.Name())
+	}
+
+	// the names contain a sortable timestamp
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	return backups, nil
+}
+
+// RestoreState replaces the current state with the given backup, the
+// current state is backed up before it is replaced
+func RestoreState(name string) error {
+	if name != filepath.Base(name) {
+		return fmt.Errorf("invalid state backup '%s', specify the name of the backup", name)
+	}
+
+	d, err := os.ReadFile(filepath.Join(StateBackupDir(), name))
+	if err != nil {
+		return fmt.Errorf("unable to read state backup '%s': %s", name, err)
+	}
+
+	// check the backup is valid before replacing the state
+	_, err = NewParser(nil, nil, nil).UnmarshalJSON(d)
+	if err != nil {
+		return fmt.Errorf("state backup '%s' is not valid: %s", name, err)
+	}
+
+	_, err = BackupState()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(utils.StateDir(), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to create directory for state file '%s', error: %s", utils.StateDir(), err)
+	}
+
+	err = os.WriteFile(utils.StatePath(), d, os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("unable to write state file '%s', error: %s", utils.StatePath(), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+type stateTestResource struct {
+	types.ResourceBase `hcl:",remain"`
+
+	Value string `hcl:"value,optional" json:"value,omitempty"`
+}
+
+var truncatedState = `
+{
+  "resources": [
+    {
+      "meta": {
+        "id": "resource.state_test.one",
+        "name": "one",
+        "type": "state_test"
+      },
+      "value": "one"
+    },
+    {
+      "meta": {
+        "id": "resource.state_test.two",
+        "name": "two",
+        "type": "state_test"
+      },
+      "value": "two"
+    },
+    {
+      "meta": {
+        "id": "resource.state_test.three",
+        "name": "thr
+`
+
+func setupStateTests(t *testing.T, state string) {
+	RegisterResource("state_test", &stateTestResource{}, nil)
+	t.Cleanup(func() {
+		delete(registeredTypes, "state_test")
+	})
+
+	testutils.SetupState(t, state)
+}
+
+func TestLoadStateRecoversResourcesFromTruncatedState(t *testing.T) {
+	setupStateTests(t, truncatedState)
+
+	c, err := LoadState()
+	require.True(t, IsStateRecovered(err))
+	require.Len(t, c.Resources, 2)
+
+	r, err := c.FindResource("resource.state_test.two")
+	require.NoError(t, err)
+	require.Equal(t, "two", r.(*stateTestResource).Value)
+}
+
+func TestLoadStateReturnsErrorWhenStateCanNotBeRecovered(t *testing.T) {
+	setupStateTests(t, "garbage")
+
+	_, err := LoadState()
+	require.Error(t, err)
+	require.False(t, IsStateRecovered(err))
+}
+
+func TestLoadStateDoesNotRewriteRecoveredState(t *testing.T) {
+	setupStateTests(t, truncatedState)
+
+	_, err := LoadState()
+	require.Error(t, err)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.Equal(t, truncatedState, string(d))
+}
+
+func TestRepairStateWritesCleanedState(t *testing.T) {
+	setupStateTests(t, truncatedState)
+
+	_, err := RepairState()
+	require.NoError(t, err)
+
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.NotContains(t, string(d), "resource.state_test.three")
+
+	c, err := NewParser(nil, nil, nil).UnmarshalJSON(d)
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 2)
+}
+
+var validState = `
+{
+  "resources": [
+    {
+      "meta": {
+        "id": "resource.state_test.one",
+        "name": "one",
+        "type": "state_test"
+      },
+      "value": "one"
+    }
+  ]
+}
+`
+
+func TestBackupStateKeepsMaximumBackups(t *testing.T) {
+	setupStateTests(t, validState)
+
+	old := MaxStateBackups
+	MaxStateBackups = 2
+	t.Cleanup(func() { MaxStateBackups = old })
+
+	for i := 0; i < 3; i++ {
+		name, err := BackupState()
+		require.NoError(t, err)
+		require.NotEmpty(t, name)
+	}
+
+	backups, err := ListStateBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+}
+
+func TestBackupStateDoesNothingWhenNoState(t *testing.T) {
+	setupStateTests(t, "")
+
+	name, err := BackupState()
+	require.NoError(t, err)
+	require.Empty(t, name)
+}
+
+func TestRestoreStateReplacesState(t *testing.T) {
+	setupStateTests(t, validState)
+
+	name, err := BackupState()
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.StatePath(), []byte(`{"resources": []}`), os.ModePerm)
+	require.NoError(t, err)
+
+	err = RestoreState(name)
+	require.NoError(t, err)
+
+	c, err := LoadState()
+	require.NoError(t, err)
+	require.Len(t, c.Resources, 1)
+
+	// the replaced state should also have been backed up
+	backups, err := ListStateBackups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+}
+
+func TestRestoreStateReturnsErrorForPath(t *testing.T) {
+	setupStateTests(t, validState)
+
+	err := RestoreState("../state.json")
+	require.Error(t, err)
+}
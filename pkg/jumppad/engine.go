@@ -5,10 +5,13 @@ import (
 	// "fmt"
 
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 
 	"github.com/jumppad-labs/hclconfig"
 	hclerrors "github.com/jumppad-labs/hclconfig/errors"
@@ -38,6 +41,9 @@ type Engine interface {
 	ParseConfig(string) (*hclconfig.Config, error)
 	ParseConfigWithVariables(string, map[string]string, string) (*hclconfig.Config, error)
 	Destroy(ctx context.Context, force bool) error
+	// SetParallelism sets the maximum number of resources that are created or
+	// destroyed concurrently, a value of 0 does not limit concurrency
+	SetParallelism(int)
 	Config() *hclconfig.Config
 	Diff(path string, variables map[string]string, variablesFile string) (new []types.Resource, changed []types.Resource, removed []types.Resource, cfg *hclconfig.Config, err error)
 }
@@ -49,6 +55,15 @@ type EngineImpl struct {
 	config    *hclconfig.Config
 	ctx       context.Context
 	force     bool
+
+	// limiter bounds the number of concurrent provider calls
+	limiter chan struct{}
+
+	// destroyErrors collects the errors from destroy so that independent
+	// resources can continue to be destroyed
+	destroyMutex  sync.Mutex
+	destroyErrors []error
+	destroyFailed []types.Resource
 }
 
 // New creates a new Jumppad engine
@@ -60,6 +75,9 @@ func New(p config.Providers, l logger.Logger) (Engine, error) {
 	// Set the standard writer to our logger as the DAG uses the standard library log.
 	log.SetOutput(l.StandardWriter())
 
+	// report any resources dropped when recovering a corrupt state file
+	config.SetStateLogger(l)
+
 	return e, nil
 }
 
@@ -113,8 +131,12 @@ func (e *EngineImpl) Diff(path string, variables map[string]string, variablesFil
 	var changed []types.Resource
 	var removed []types.Resource
 
-	// load the stack
-	past, _ := config.LoadState()
+	// load the stack, a corrupt state must be repaired before it is changed
+	// otherwise the resources that could not be recovered are lost
+	past, err := config.LoadState()
+	if config.IsStateRecovered(err) {
+		return nil, nil, nil, nil, err
+	}
 
 	// Parse the config to check it is valid
 	res, parseErr := e.ParseConfigWithVariables(path, variables, variablesFile)
@@ -231,8 +253,24 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 		}
 	}
 
+	unlock, err := acquireLock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// take a copy of the state before making any changes
+	e.backupState()
+
 	// get a diff of resources
-	_, _, removed, _, err := e.Diff(path, vars, variablesFile)
+	_, _, removed, parsed, err := e.Diff(path, vars, variablesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// check for invalid or conflicting static ip addresses before creating
+	// any resources
+	err = validateStaticIPs(parsed)
 	if err != nil {
 		return nil, err
 	}
@@ -372,14 +410,66 @@ func (e *EngineImpl) ApplyWithVariables(ctx context.Context, path string, vars m
 	return e.config, processErr
 }
 
+// SetParallelism sets the maximum number of resources that are created or
+// destroyed concurrently
+func (e *EngineImpl) SetParallelism(n int) {
+	if n <= 0 {
+		e.limiter = nil
+		return
+	}
+
+	e.limiter = make(chan struct{}, n)
+}
+
+// acquire blocks until a resource can be processed, the returned function
+// must be called to release the slot
+func (e *EngineImpl) acquire() func() {
+	if e.limiter == nil {
+		return func() {}
+	}
+
+	e.limiter <- struct{}{}
+
+	return func() {
+		<-e.limiter
+	}
+}
+
+// backupState takes a copy of the current state, failure to backup the state
+// does not stop the operation
+func (e *EngineImpl) backupState() {
+	name, err := config.BackupState()
+	if err != nil {
+		e.log.Warn("Unable to backup state", "error", err)
+		return
+	}
+
+	if name != "" {
+		e.log.Debug("Backed up state", "backup", name)
+	}
+}
+
 // Destroy the resources defined by the state
 func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
 	e.log.Info("Destroying resources", "force", force)
 	e.force = force
 	e.ctx = ctx
 
+	unlock, err := acquireLock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// take a copy of the state so that it can be restored if needed
+	e.backupState()
+
 	// load the state
 	c, err := config.LoadState()
+	if config.IsStateRecovered(err) {
+		return err
+	}
+
 	if err != nil {
 		e.log.Debug("State file does not exist")
 	}
@@ -391,6 +481,9 @@ func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
 	// image cache which is manually added by Apply process
 	// should have the correct dependency graph to be
 	// destroyed last
+	e.destroyErrors = nil
+	e.destroyFailed = nil
+
 	err = e.config.Walk(e.destroyCallback, true)
 	if err != nil {
 
@@ -398,6 +491,14 @@ func (e *EngineImpl) Destroy(ctx context.Context, force bool) error {
 		return fmt.Errorf("error trying to call Destroy on provider: %s", err)
 	}
 
+	if len(e.destroyErrors) > 0 {
+		// save the state so that the resources which have been destroyed
+		// are removed
+		config.SaveState(e.config)
+
+		return fmt.Errorf("unable to destroy %d resources:\n%w", len(e.destroyErrors), errors.Join(e.destroyErrors...))
+	}
+
 	// remove the state
 	return os.Remove(utils.StatePath())
 }
@@ -530,6 +631,9 @@ func (e *EngineImpl) createCallback(r types.Resource) error {
 		return nil
 	}
 
+	release := e.acquire()
+	defer release()
+
 	p := e.providers.GetProvider(r)
 	if p == nil {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
@@ -665,6 +769,21 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 		return nil
 	}
 
+	// resources that a failed resource depends on can not be destroyed
+	if dep := e.failedDependent(r); dep != "" {
+		e.log.Info("Skipping resource, a dependent resource could not be destroyed", "fqdn", fqrn.String(), "dependent", dep)
+
+		// skipped resources also protect their own dependencies
+		e.destroyMutex.Lock()
+		e.destroyFailed = append(e.destroyFailed, r)
+		e.destroyMutex.Unlock()
+
+		return nil
+	}
+
+	release := e.acquire()
+	defer release()
+
 	p := e.providers.GetProvider(r)
 
 	if p == nil {
@@ -675,7 +794,14 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 	err := p.Destroy(e.ctx, e.force)
 	if err != nil && !e.force {
 		r.Metadata().Properties[constants.PropertyStatus] = constants.StatusFailed
-		return fmt.Errorf("unable to destroy resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err)
+
+		// record the error and continue destroying independent resources
+		e.destroyMutex.Lock()
+		e.destroyErrors = append(e.destroyErrors, fmt.Errorf("unable to destroy resource Name: %s, Type: %s, Error: %s", r.Metadata().Name, r.Metadata().Type, err))
+		e.destroyFailed = append(e.destroyFailed, r)
+		e.destroyMutex.Unlock()
+
+		return nil
 	}
 
 	// remove from the state
@@ -683,3 +809,20 @@ func (e *EngineImpl) destroyCallback(r types.Resource) error {
 
 	return nil
 }
+
+// failedDependent returns the id of a resource that failed or was skipped
+// during destroy and depends on the given resource
+func (e *EngineImpl) failedDependent(r types.Resource) string {
+	e.destroyMutex.Lock()
+	defer e.destroyMutex.Unlock()
+
+	for _, f := range e.destroyFailed {
+		for _, d := range f.GetDependencies() {
+			if d == r.Metadata().ID || strings.HasPrefix(d, r.Metadata().ID+".") {
+				return f.Metadata().ID
+			}
+		}
+	}
+
+	return ""
+}
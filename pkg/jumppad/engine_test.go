@@ -405,6 +405,21 @@ func TestDestroyCallsProviderDestroyForEachProvider(t *testing.T) {
 	require.NoFileExists(t, utils.StatePath())
 }
 
+func TestDestroyReturnsErrorAndKeepsStateWhenStateIsCorrupt(t *testing.T) {
+	corrupt := existingState[:len(existingState)-40]
+	e, mp := setupTestsWithState(t, nil, corrupt)
+
+	err := e.Destroy(context.Background(), false)
+	require.True(t, config.IsStateRecovered(err))
+
+	testAssertMethodCalled(t, mp, "Destroy", 0)
+
+	// the state must only be rewritten by a repair
+	d, err := os.ReadFile(utils.StatePath())
+	require.NoError(t, err)
+	require.Equal(t, corrupt, string(d))
+}
+
 func TestDestroyNotCallsProviderDestroyForResourcesDisabled(t *testing.T) {
 	e, mp := setupTestsWithState(t, nil, disabledState)
 
@@ -442,6 +457,31 @@ func TestDestroyFailSetsStatus(t *testing.T) {
 	require.Equal(t, constants.StatusFailed, r.Metadata().Properties[constants.PropertyStatus])
 }
 
+func TestDestroyCollectsErrorsForIndependentResources(t *testing.T) {
+	e, mp := setupTestsWithState(t, map[string]error{"mycontainer": fmt.Errorf("boom"), "jumppad": fmt.Errorf("bang")}, complexState)
+
+	err := e.Destroy(context.Background(), false)
+	require.Error(t, err)
+	require.ErrorContains(t, err, "boom")
+	require.ErrorContains(t, err, "bang")
+
+	// dependencies of the failed container should not be destroyed
+	testAssertMethodCalled(t, mp, "Destroy", 3)
+
+	r, _ := e.config.FindResource("resource.template.mytemplate")
+	require.NotNil(t, r)
+}
+
+func TestDestroyWithParallelismCallsProviderDestroyForEachProvider(t *testing.T) {
+	e, mp := setupTestsWithState(t, nil, complexState)
+	e.SetParallelism(1)
+
+	err := e.Destroy(context.Background(), false)
+	require.NoError(t, err)
+
+	testAssertMethodCalled(t, mp, "Destroy", 5)
+}
+
 func TestParseConfig(t *testing.T) {
 	e, mp := setupTests(t, nil)
 
@@ -492,6 +532,24 @@ func TestParseWithEnvironmentVariables(t *testing.T) {
 	require.Equal(t, "consul:1.8.1", c.(*container.Container).Image.Name)
 }
 
+func TestParseWithLocals(t *testing.T) {
+	e, mp := setupTests(t, nil)
+
+	_, err := e.ParseConfig("../../examples/locals/locals.hcl")
+	require.NoError(t, err)
+
+	// should not have created any providers
+	testAssertMethodCalled(t, mp, "Create", 0)
+
+	c, err := e.config.FindResource("resource.container.consul")
+	require.NoError(t, err)
+	require.Equal(t, "hashicorp/consul:1.16.2", c.(*container.Container).Image.Name)
+
+	c, err = e.config.FindResource("resource.container.consul_client")
+	require.NoError(t, err)
+	require.Equal(t, "hashicorp/consul:1.16.2", c.(*container.Container).Image.Name)
+}
+
 func testAssertMethodCalled(t *testing.T, p *mocks.Providers, method string, n int, resource ...types.Resource) {
 	if len(resource) > 1 {
 		panic("testAssertMethodCalled only expects 0 or 1 resources")
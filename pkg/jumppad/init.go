@@ -42,9 +42,11 @@ func init() {
 	config.RegisterResource(ingress.TypeIngress, &ingress.Ingress{}, &ingress.Provider{})
 	config.RegisterResource(k8s.TypeK8sCluster, &k8s.Cluster{}, &k8s.ClusterProvider{})
 	config.RegisterResource(k8s.TypeK8sConfig, &k8s.Config{}, &k8s.ConfigProvider{})
+	config.RegisterResource(k8s.TypeK8sExec, &k8s.Exec{}, &k8s.ExecProvider{})
 	// add alias for k8s
 	config.RegisterResource(k8s.TypeKubernetesCluster, &k8s.Cluster{}, &k8s.ClusterProvider{})
 	config.RegisterResource(k8s.TypeKubernetesConfig, &k8s.Config{}, &k8s.ConfigProvider{})
+	config.RegisterResource(k8s.TypeKubernetesExec, &k8s.Exec{}, &k8s.ExecProvider{})
 
 	config.RegisterResource(network.TypeNetwork, &network.Network{}, &network.Provider{})
 	config.RegisterResource(nomad.TypeNomadCluster, &nomad.NomadCluster{}, &nomad.ClusterProvider{})
@@ -0,0 +1,122 @@
+package jumppad
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	ps "github.com/mitchellh/go-ps"
+)
+
+// processExists returns true when a process with the given pid is running,
+// replaced in tests
+var processExists = func(pid int) bool {
+	p, err := ps.FindProcess(pid)
+	return err == nil && p != nil
+}
+
+// acquireLock takes the lock that prevents concurrent operations on the state,
+// the lock file contains the pid of the process holding the lock. When the lock
+// is held by a running process, including the current process, an error is
+// returned, locks held by a process that no longer exists are released. The
+// returned function releases the lock.
+func acquireLock() (func(), error) {
+	path := utils.LockPath()
+
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create lock directory: %w", err)
+	}
+
+	// write the pid to a temporary file and link it to the lock so that the
+	// lock is never seen without the pid
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".jumppad.lock.*")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create lock file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = fmt.Fprintf(tmp, "%d", os.Getpid())
+	tmp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to write lock file: %w", err)
+	}
+
+	// try twice, the second attempt follows the removal of a stale lock
+	for range 2 {
+		err = os.Link(tmp.Name(), path)
+		if err == nil {
+			return func() { releaseLock(path) }, nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("unable to create lock file: %w", err)
+		}
+
+		pid, err := lockOwner(path)
+		if err == nil && pid == os.Getpid() {
+			return nil, fmt.Errorf("the lock is already held by this process (pid %d)", pid)
+		}
+
+		if err == nil && processExists(pid) {
+			return nil, fmt.Errorf("another jumppad operation is in progress (pid %d)", pid)
+		}
+
+		// the process holding the lock no longer exists
+		err = releaseStaleLock(path, tmp.Name()+".stale", pid)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("unable to acquire lock %s", path)
+}
+
+// releaseStaleLock moves the lock aside before removing it so that only one
+// process can release a stale lock. When the lock that was moved is no longer
+// held by the stale owner another process has taken the lock since the owner
+// was read, the lock is moved back and an error is returned.
+func releaseStaleLock(path, stale string, owner int) error {
+	err := os.Rename(path, stale)
+	if errors.Is(err, os.ErrNotExist) {
+		// another process has already released the lock
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to release stale lock: %w", err)
+	}
+	defer os.Remove(stale)
+
+	pid, err := lockOwner(stale)
+	if err == nil && pid != owner {
+		os.Link(stale, path)
+		return fmt.Errorf("another jumppad operation is in progress (pid %d)", pid)
+	}
+
+	return nil
+}
+
+// releaseLock removes the lock when it is held by the current process
+func releaseLock(path string) {
+	pid, err := lockOwner(path)
+	if err != nil || pid != os.Getpid() {
+		return
+	}
+
+	os.Remove(path)
+}
+
+// lockOwner returns the pid of the process holding the lock
+func lockOwner(path string) (int, error) {
+	d, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(d)))
+}
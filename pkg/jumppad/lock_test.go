@@ -0,0 +1,109 @@
+package jumppad
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/utils"
+	"github.com/jumppad-labs/jumppad/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+func setupLock(t *testing.T, running bool) {
+	testutils.SetupState(t, "")
+
+	pe := processExists
+	processExists = func(pid int) bool { return running }
+
+	t.Cleanup(func() {
+		processExists = pe
+	})
+}
+
+func writeLock(t *testing.T, pid string) {
+	err := os.MkdirAll(filepath.Dir(utils.LockPath()), os.ModePerm)
+	require.NoError(t, err)
+
+	err = os.WriteFile(utils.LockPath(), []byte(pid), 0644)
+	require.NoError(t, err)
+}
+
+func TestAcquireLockCreatesLockWithPid(t *testing.T) {
+	setupLock(t, false)
+
+	unlock, err := acquireLock()
+	require.NoError(t, err)
+
+	pid, err := lockOwner(utils.LockPath())
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), pid)
+
+	unlock()
+	require.NoFileExists(t, utils.LockPath())
+}
+
+func TestAcquireLockReturnsErrorWhenHeldByRunningProcess(t *testing.T) {
+	setupLock(t, true)
+	writeLock(t, "99999")
+
+	_, err := acquireLock()
+	require.ErrorContains(t, err, "another jumppad operation is in progress (pid 99999)")
+	require.FileExists(t, utils.LockPath())
+}
+
+func TestAcquireLockReleasesStaleLock(t *testing.T) {
+	setupLock(t, false)
+	writeLock(t, "99999")
+
+	unlock, err := acquireLock()
+	require.NoError(t, err)
+	defer unlock()
+
+	pid, err := lockOwner(utils.LockPath())
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), pid)
+}
+
+func TestAcquireLockReturnsErrorWhenHeldByCurrentProcess(t *testing.T) {
+	setupLock(t, false)
+
+	unlock, err := acquireLock()
+	require.NoError(t, err)
+	defer unlock()
+
+	_, err = acquireLock()
+	require.ErrorContains(t, err, "already held by this process")
+
+	pid, err := lockOwner(utils.LockPath())
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), pid)
+}
+
+func TestReleaseStaleLockRestoresLockTakenByAnotherProcess(t *testing.T) {
+	setupLock(t, false)
+	writeLock(t, "12345")
+
+	// the lock was read when held by 99999 and taken by 12345 since
+	err := releaseStaleLock(utils.LockPath(), utils.LockPath()+".stale", 99999)
+	require.ErrorContains(t, err, "pid 12345")
+
+	pid, err := lockOwner(utils.LockPath())
+	require.NoError(t, err)
+	require.Equal(t, 12345, pid)
+	require.NoFileExists(t, utils.LockPath()+".stale")
+}
+
+func TestApplyReturnsErrorWhenLocked(t *testing.T) {
+	e, _ := setupTests(t, nil)
+
+	pe := processExists
+	processExists = func(pid int) bool { return true }
+	t.Cleanup(func() { processExists = pe })
+
+	writeLock(t, "99999")
+
+	_, err := e.Apply(context.Background(), "../../examples/single_file/container.hcl")
+	require.ErrorContains(t, err, "another jumppad operation is in progress")
+}
@@ -204,6 +204,11 @@ func (_m *Engine) ParseConfigWithVariables(_a0 string, _a1 map[string]string, _a
 	return r0, r1
 }
 
+// SetParallelism provides a mock function with given fields: _a0
+func (_m *Engine) SetParallelism(_a0 int) {
+	_m.Called(_a0)
+}
+
 type mockConstructorTestingTNewEngine interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,53 @@
+package jumppad
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	sdk "github.com/jumppad-labs/plugin-sdk"
+)
+
+// PluginRegisterFunc is the signature of the function that an external plugin
+// must export with the name Register. Register is called when the plugin is
+// loaded and should call register for every custom resource type the plugin
+// defines.
+//
+// The provider passed to register must implement sdk.Provider:
+//
+//	Init(resource types.Resource, logger sdk.Logger) error
+//	Create(ctx context.Context) error
+//	Destroy(ctx context.Context, force bool) error
+//	Lookup() ([]string, error)
+//	Refresh(ctx context.Context) error
+//	Changed() (bool, error)
+//
+// Plugins are built with go build -buildmode=plugin from a main package and
+// can only be loaded when jumppad is built with cgo enabled. The Register
+// function in examples/plugins/example has this signature.
+type PluginRegisterFunc = func(register sdk.RegisterResourceFunc, loadState sdk.LoadStateFunc) error
+
+// LoadPlugins loads all the Go plugins, files with the extension .so, in the
+// given directory and registers the resources that they define
+func LoadPlugins(dir string, l logger.Logger) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.so"))
+	if err != nil {
+		return fmt.Errorf("unable to list plugins in %s: %w", dir, err)
+	}
+
+	for _, f := range files {
+		if fi, err := os.Stat(f); err != nil || fi.IsDir() {
+			continue
+		}
+
+		l.Debug("Loading plugin", "path", f)
+
+		err := loadPlugin(f)
+		if err != nil {
+			return fmt.Errorf("unable to load plugin %s: %w", f, err)
+		}
+	}
+
+	return nil
+}
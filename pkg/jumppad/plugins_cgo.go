@@ -0,0 +1,27 @@
+//go:build cgo
+
+package jumppad
+
+import (
+	"fmt"
+	"plugin"
+)
+
+func loadPlugin(path string) error {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return err
+	}
+
+	sym, err := p.Lookup("Register")
+	if err != nil {
+		return err
+	}
+
+	register, ok := sym.(PluginRegisterFunc)
+	if !ok {
+		return fmt.Errorf("plugin Register function has the wrong signature, expected %T, got %T", PluginRegisterFunc(nil), sym)
+	}
+
+	return register(PluginRegisterResource, PluginLoadState)
+}
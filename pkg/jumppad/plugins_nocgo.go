@@ -0,0 +1,11 @@
+//go:build !cgo
+
+package jumppad
+
+import "fmt"
+
+// loadPlugin returns an error as Go plugins can not be loaded by binaries
+// built with CGO_ENABLED=0
+func loadPlugin(path string) error {
+	return fmt.Errorf("this version of jumppad was built without cgo and can not load plugins, use jumppad build to build a version of jumppad that includes the plugin")
+}
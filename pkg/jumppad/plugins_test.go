@@ -0,0 +1,30 @@
+package jumppad
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumppad-labs/jumppad/pkg/clients/logger"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPluginsWithNoPluginsReturnsNil(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a plugin"), 0644)
+	require.NoError(t, err)
+
+	err = LoadPlugins(dir, logger.NewTestLogger(t))
+	require.NoError(t, err)
+}
+
+func TestLoadPluginsWithInvalidPluginReturnsError(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "invalid.so"), []byte("not a plugin"), 0644)
+	require.NoError(t, err)
+
+	err = LoadPlugins(dir, logger.NewTestLogger(t))
+	require.ErrorContains(t, err, "invalid.so")
+}
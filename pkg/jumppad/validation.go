@@ -0,0 +1,120 @@
+package jumppad
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/network"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/nomad"
+)
+
+// validateStaticIPs checks that any static ip addresses requested by resources
+// are within the subnet of the network they are attached to and that the same
+// address is not requested by more than one resource on the same network
+func validateStaticIPs(c *hclconfig.Config) error {
+	if c == nil {
+		return nil
+	}
+
+	// keyed by network id then ip address, value is the resource id
+	requested := map[string]map[string]string{}
+	subnets := map[string]*net.IPNet{}
+
+	for _, r := range c.Resources {
+		if r.GetDisabled() {
+			continue
+		}
+
+		var attachments []container.NetworkAttachment
+		switch v := r.(type) {
+		case *container.Container:
+			attachments = v.Networks
+		case *k8s.Cluster:
+			attachments = v.Networks
+		case *nomad.NomadCluster:
+			attachments = v.Networks
+		default:
+			continue
+		}
+
+		for _, a := range attachments {
+			if a.IPAddress == "" || a.ID == "" {
+				continue
+			}
+
+			ip := net.ParseIP(a.IPAddress)
+			if ip == nil {
+				return fmt.Errorf("invalid ip address %s for resource %s on network %s", a.IPAddress, r.Metadata().ID, a.ID)
+			}
+
+			if _, ok := subnets[a.ID]; !ok {
+				subnets[a.ID] = networkSubnet(c, a.ID)
+			}
+
+			subnet := subnets[a.ID]
+			if subnet != nil && ip.To4() != nil && !isAssignableIP(subnet, ip) {
+				return fmt.Errorf("ip address %s for resource %s is not a valid address for the subnet of network %s", a.IPAddress, r.Metadata().ID, a.ID)
+			}
+
+			if requested[a.ID] == nil {
+				requested[a.ID] = map[string]string{}
+			}
+
+			if other, ok := requested[a.ID][ip.String()]; ok && other != r.Metadata().ID {
+				return fmt.Errorf("ip address %s for resource %s conflicts with resource %s, both request the same address on network %s", a.IPAddress, r.Metadata().ID, other, a.ID)
+			}
+
+			requested[a.ID][ip.String()] = r.Metadata().ID
+		}
+	}
+
+	return nil
+}
+
+// networkSubnet returns the IPv4 subnet for the network with the given id,
+// if the network or the subnet is unknown nil is returned
+func networkSubnet(c *hclconfig.Config, id string) *net.IPNet {
+	subnet := ""
+
+	if r, err := c.FindResource(id); err == nil {
+		if n, ok := r.(*network.Network); ok {
+			subnet = n.Subnet
+		}
+	} else if id == network.DefaultNetworkID {
+		subnet = network.DefaultNetworkSubnet
+	}
+
+	_, cidr, err := net.ParseCIDR(subnet)
+	if err != nil || cidr.IP.To4() == nil {
+		return nil
+	}
+
+	return cidr
+}
+
+// isAssignableIP returns true when the ip is in the subnet and is not the
+// network or broadcast address
+func isAssignableIP(subnet *net.IPNet, ip net.IP) bool {
+	ip = ip.To4()
+	network := subnet.IP.To4()
+	if ip == nil || network == nil || !subnet.Contains(ip) {
+		return false
+	}
+
+	// subnets without room for a network and broadcast address have no
+	// assignable addresses
+	ones, bits := subnet.Mask.Size()
+	if bits-ones < 2 {
+		return false
+	}
+
+	broadcast := make(net.IP, len(network))
+	for i := range network {
+		broadcast[i] = network[i] | ^subnet.Mask[i]
+	}
+
+	return !ip.Equal(network) && !ip.Equal(broadcast)
+}
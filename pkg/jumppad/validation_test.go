@@ -0,0 +1,86 @@
+package jumppad
+
+import (
+	"testing"
+
+	"github.com/jumppad-labs/hclconfig"
+	"github.com/jumppad-labs/hclconfig/types"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/container"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/k8s"
+	"github.com/jumppad-labs/jumppad/pkg/config/resources/network"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStaticIPConfig(t *testing.T, ips ...string) *hclconfig.Config {
+	c := hclconfig.NewConfig()
+
+	n := &network.Network{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.network.cloud", Name: "cloud", Type: network.TypeNetwork}},
+		Subnet:       "10.5.0.0/24",
+	}
+	require.NoError(t, c.AppendResource(n))
+
+	for i, ip := range ips {
+		name := string(rune('a' + i))
+		ct := &container.Container{
+			ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.container." + name, Name: name, Type: container.TypeContainer}},
+			Networks:     []container.NetworkAttachment{{ID: "resource.network.cloud", IPAddress: ip}},
+		}
+		require.NoError(t, c.AppendResource(ct))
+	}
+
+	return c
+}
+
+func TestValidateStaticIPsWithUniqueAddressesReturnsNoError(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.5.0.10", "10.5.0.11", "")
+
+	err := validateStaticIPs(c)
+	require.NoError(t, err)
+}
+
+func TestValidateStaticIPsWithDuplicateAddressReturnsError(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.5.0.10")
+
+	kc := &k8s.Cluster{
+		ResourceBase: types.ResourceBase{Meta: types.Meta{ID: "resource.k8s_cluster.k3s", Name: "k3s", Type: k8s.TypeK8sCluster}},
+		Networks:     []container.NetworkAttachment{{ID: "resource.network.cloud", IPAddress: "10.5.0.10"}},
+	}
+	require.NoError(t, c.AppendResource(kc))
+
+	err := validateStaticIPs(c)
+	require.ErrorContains(t, err, "resource.container.a")
+	require.ErrorContains(t, err, "resource.k8s_cluster.k3s")
+}
+
+func TestValidateStaticIPsOutsideSubnetReturnsError(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.6.0.10")
+
+	err := validateStaticIPs(c)
+	require.ErrorContains(t, err, "not a valid address for the subnet")
+}
+
+func TestValidateStaticIPsWithBroadcastAddressReturnsError(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.5.0.255")
+
+	err := validateStaticIPs(c)
+	require.Error(t, err)
+}
+
+func TestValidateStaticIPsWithNetworkAddressReturnsError(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.5.0.0")
+
+	err := validateStaticIPs(c)
+	require.Error(t, err)
+}
+
+func TestValidateStaticIPsIgnoresDisabledResources(t *testing.T) {
+	c := setupStaticIPConfig(t, "10.5.0.10", "10.5.0.10")
+
+	r, err := c.FindResource("resource.container.b")
+	require.NoError(t, err)
+	r.SetDisabled(true)
+
+	err = validateStaticIPs(c)
+	require.NoError(t, err)
+}
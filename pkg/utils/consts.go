@@ -9,6 +9,10 @@ var ErrNameContainsInvalidCharacters = fmt.Errorf("name contains invalid charact
 // ImageVolumeName is the name of the volume which stores the images for clusters
 const ImageVolumeName string = "images"
 
+// ImageVolumeMountPath is the default path the image volume is mounted at
+// inside containers, images are stored in the images folder of the volume
+const ImageVolumeMountPath string = "/cache"
+
 // BuildImagePrefix is the default prefix added to any image built by jumppad
 const BuildImagePrefix = "jumppad.dev/localcache"
 
@@ -23,5 +27,10 @@ const ProxyBypass string = "localhost,127.0.0.1,cluster.local,jumppad.dev,jumpd.
 
 const LocalTLD = "jmpd.in"
 
+// MaxRandomPort is the default upper bound for randomly assigned ports, it can
+// be overridden with the JUMPPAD_MAX_PORT environment variable
 const MaxRandomPort = 32767
+
+// MinRandomPort is the default lower bound for randomly assigned ports, it can
+// be overridden with the JUMPPAD_MIN_PORT environment variable
 const MinRandomPort = 30000
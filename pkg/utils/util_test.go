@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 
 	"github.com/gosuri/uitable/util/strutil"
@@ -113,6 +114,83 @@ func TestHomeReturnsCorrectValue(t *testing.T) {
 	require.Equal(t, os.Getenv(HomeEnvName()), h)
 }
 
+func TestExpandPathReplacesHome(t *testing.T) {
+	h := HomeFolder()
+
+	require.Equal(t, filepath.Join(h, "config"), filepath.Clean(ExpandPath("~/config")))
+	require.Equal(t, filepath.Join(h, "config"), filepath.Clean(ExpandPath(fmt.Sprintf("${%s}/config", HomeEnvName()))))
+	require.Equal(t, filepath.Join(h, "config"), filepath.Clean(ExpandPath(fmt.Sprintf("$%s/config", HomeEnvName()))))
+}
+
+func TestExpandPathLeavesUnsetVariables(t *testing.T) {
+	require.Equal(t, "/data/${JUMPPAD_TEST_UNSET}/config", ExpandPath("/data/$JUMPPAD_TEST_UNSET/config"))
+	require.Equal(t, "./~config", ExpandPath("./~config"))
+}
+
+func TestDockerBindPathConvertsWindowsPaths(t *testing.T) {
+	require.Equal(t, "/c/Users/me/config", DockerBindPath(`C:\Users\me\config`, "docker"))
+	require.Equal(t, "/d/data", DockerBindPath(`D:/data`, "docker"))
+	require.Equal(t, "/c/", DockerBindPath(`C:\`, "docker"))
+}
+
+func TestDockerBindPathConvertsWindowsPathsForPodman(t *testing.T) {
+	require.Equal(t, "//c/Users/me/config", DockerBindPath(`C:\Users\me\config`, "podman"))
+}
+
+func TestDockerBindPathDoesNotChangeUnixPaths(t *testing.T) {
+	require.Equal(t, "/home/me/config", DockerBindPath("/home/me/config", "docker"))
+	require.Equal(t, "/var/run/docker.sock", DockerBindPath("/var/run/docker.sock", "podman"))
+}
+
+func TestRandomPortRangeReturnsDefaults(t *testing.T) {
+	t.Setenv("JUMPPAD_MIN_PORT", "")
+	t.Setenv("JUMPPAD_MAX_PORT", "")
+
+	min, max, err := RandomPortRange()
+	require.NoError(t, err)
+	require.Equal(t, MinRandomPort, min)
+	require.Equal(t, MaxRandomPort, max)
+}
+
+func TestRandomPortRangeReturnsEnvWhenSet(t *testing.T) {
+	t.Setenv("JUMPPAD_MIN_PORT", "40000")
+	t.Setenv("JUMPPAD_MAX_PORT", "40010")
+
+	min, max, err := RandomPortRange()
+	require.NoError(t, err)
+	require.Equal(t, 40000, min)
+	require.Equal(t, 40010, max)
+
+	p, err := RandomPort()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, p, 40000)
+	require.Less(t, p, 40010)
+}
+
+func TestRandomPortRangeReturnsErrorWhenInvalid(t *testing.T) {
+	tt := map[string][]string{
+		"not a number":  {"abc", "40000"},
+		"below 1024":    {"80", "40000"},
+		"above 65535":   {"40000", "70000"},
+		"min above max": {"40000", "30000"},
+	}
+
+	for name, r := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("JUMPPAD_MIN_PORT", r[0])
+			t.Setenv("JUMPPAD_MAX_PORT", r[1])
+
+			_, _, err := RandomPortRange()
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTailLinesReturnsLastLines(t *testing.T) {
+	require.Equal(t, "three\nfour", TailLines("one\ntwo\nthree\nfour\n", 2))
+	require.Equal(t, "one\ntwo", TailLines("one\ntwo", 5))
+}
+
 func TestStateReturnsCorrectValue(t *testing.T) {
 	h := StateDir()
 	expected := filepath.Join(os.Getenv(HomeEnvName()), ".jumppad/state")
@@ -162,6 +240,29 @@ func TestShipyardTempReturnsPath(t *testing.T) {
 	require.True(t, s.IsDir())
 }
 
+func TestSecretsDirReturnsPath(t *testing.T) {
+	home := os.Getenv(HomeEnvName())
+	tmp, _ := os.MkdirTemp("", "")
+	os.Setenv(HomeEnvName(), tmp)
+
+	t.Cleanup(func() {
+		os.Setenv(HomeEnvName(), home)
+		os.RemoveAll(tmp)
+	})
+
+	sd := SecretsDir("test")
+
+	require.Equal(t, filepath.Join(tmp, ".jumppad", "/tmp", "/secrets", "/test"), sd)
+
+	s, err := os.Stat(sd)
+	require.NoError(t, err)
+	require.True(t, s.IsDir())
+
+	if runtime.GOOS != "windows" {
+		require.Equal(t, os.FileMode(0700), s.Mode().Perm())
+	}
+}
+
 func TestShipyardDataReturnsPath(t *testing.T) {
 	home := os.Getenv(HomeEnvName())
 	tmp, _ := os.MkdirTemp("", "")
@@ -247,6 +348,22 @@ func TestDockerHostWithDefaultReturnsCorrectValue(t *testing.T) {
 	require.Equal(t, "/var/run/docker.sock", ds)
 }
 
+func TestDockerHostReturnsPodmanSocketWhenRuntimePodman(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("JUMPPAD_RUNTIME", "podman")
+	t.Setenv("XDG_RUNTIME_DIR", "/run/user/1000")
+
+	ds := GetDockerHost()
+	require.Equal(t, "/run/user/1000/podman/podman.sock", ds)
+}
+
+func TestDockerIPReturnsHostForSSHConnections(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "ssh://core@127.0.0.1:50346/run/user/501/podman/podman.sock")
+
+	ip := GetDockerIP()
+	require.Equal(t, "127.0.0.1", ip)
+}
+
 func TestGetLocalIPAndHostnameReturnsCorrectly(t *testing.T) {
 	ip, host := GetLocalIPAndHostname()
 
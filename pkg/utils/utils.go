@@ -14,7 +14,9 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jumppad-labs/jumppad/pkg/utils/dirhash"
 	"github.com/kennygrant/sanitize"
@@ -44,8 +46,8 @@ func EnsureAbsolute(path, file string) string {
 
 	baseDir := file
 	// check if the basepath is a file return its directory
-	s, _ := os.Stat(file)
-	if !s.IsDir() {
+	s, err := os.Stat(file)
+	if err != nil || !s.IsDir() {
 		baseDir = filepath.Dir(file)
 	}
 
@@ -54,6 +56,55 @@ func EnsureAbsolute(path, file string) string {
 	return filepath.Clean(fp)
 }
 
+var windowsDrivePath = regexp.MustCompile(`^([a-zA-Z]):([\\/]|$)`)
+
+// DockerBindPath converts a Windows host path such as C:\Users\me\config to
+// the form accepted as a bind mount source by the container engine,
+// /c/Users/me/config for Docker and //c/Users/me/config for Podman. Paths
+// that do not start with a drive letter are returned unchanged
+func DockerBindPath(path, engineType string) string {
+	m := windowsDrivePath.FindStringSubmatch(path)
+	if m == nil {
+		return path
+	}
+
+	rest := strings.ReplaceAll(path[len(m[1])+1:], `\`, "/")
+	p := "/" + strings.ToLower(m[1]) + "/" + strings.TrimPrefix(rest, "/")
+
+	if engineType == "podman" {
+		p = "/" + p
+	}
+
+	return p
+}
+
+// TailLines returns the last n lines of the given string
+func TailLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\r\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ExpandPath replaces a leading ~ with the users home folder and expands
+// any environment variables such as $HOME or ${HOME}. Variables that are
+// not set are left unchanged.
+func ExpandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		path = HomeFolder() + path[1:]
+	}
+
+	return os.Expand(path, func(name string) string {
+		if v, ok := os.LookupEnv(name); ok {
+			return v
+		}
+
+		return "${" + name + "}"
+	})
+}
+
 // Creates the required file structure in the users Home directory
 func CreateFolders() {
 	os.MkdirAll(ReleasesFolder(), os.FileMode(0755))
@@ -195,6 +246,18 @@ func CertsDir(name string) string {
 	return certs
 }
 
+// SecretsDir returns the location where secrets for the given resource
+// are written before being mounted into containers, usually rooted at
+// $HOME/.jumppad/tmp/secrets. The folder is only accessible by the current user.
+func SecretsDir(name string) string {
+	secrets := filepath.Join(JumppadTemp(), "/secrets", name)
+	secrets = filepath.FromSlash(secrets)
+
+	// create the folder if it does not exist
+	os.MkdirAll(secrets, 0700)
+	return secrets
+}
+
 // LogsDir returns the location of the logs
 // used to secure the Jumppad ingress, usually $HOME/.jumppad/logs
 func LogsDir() string {
@@ -332,22 +395,58 @@ func LibraryFolder(p string, perms os.FileMode) string {
 	return data
 }
 
-// GetDockerHost returns the location of the Docker API depending on the platform
+// RuntimePodman is the value of the JUMPPAD_RUNTIME environment variable
+// that selects the Podman socket
+const RuntimePodman = "podman"
+
+// GetDockerHost returns the location of the Docker API depending on the platform,
+// when Podman is used the location of the Podman socket is returned
 func GetDockerHost() string {
 	if dh := os.Getenv("DOCKER_HOST"); dh != "" {
 		return dh
 	}
 
+	if UsePodman() {
+		return GetPodmanSocket()
+	}
+
 	return "/var/run/docker.sock"
 }
 
+// GetPodmanSocket returns the location of the Docker compatible Podman socket,
+// rootless Podman creates the socket in XDG_RUNTIME_DIR
+func GetPodmanSocket() string {
+	if d := os.Getenv("XDG_RUNTIME_DIR"); d != "" {
+		return filepath.Join(d, "podman", "podman.sock")
+	}
+
+	return "/run/podman/podman.sock"
+}
+
+// UsePodman returns true when the JUMPPAD_RUNTIME environment variable is set
+// to podman, or when the Docker socket does not exist and the Podman socket does
+func UsePodman() bool {
+	if os.Getenv("JUMPPAD_RUNTIME") == RuntimePodman {
+		return true
+	}
+
+	if _, err := os.Stat("/var/run/docker.sock"); err == nil {
+		return false
+	}
+
+	_, err := os.Stat(GetPodmanSocket())
+
+	return err == nil
+}
+
 // GetDockerIP returns the location of the Docker Server IP address
 func GetDockerIP() string {
 	if dh := os.Getenv("DOCKER_HOST"); dh != "" {
-		if strings.HasPrefix(dh, "tcp://") {
+		// remote Podman machines are connected to over ssh
+		if strings.HasPrefix(dh, "tcp://") || strings.HasPrefix(dh, "ssh://") {
 			u, err := url.Parse(dh)
 			if err == nil {
-				host := strings.Split(u.Host, ":")[0]
+				host := u.Hostname()
 				ip, err := net.LookupHost(host)
 				if err == nil && len(ip) > 0 {
 					return ip[0]
@@ -361,6 +460,12 @@ func GetDockerIP() string {
 	return sp
 }
 
+// LockPath returns the location of the lock file that prevents concurrent
+// jumppad operations on the state
+func LockPath() string {
+	return filepath.Join(JumppadHome(), "jumppad.lock")
+}
+
 // GetConnectorPIDFile returns the connector PID file used by the connector
 func GetConnectorPIDFile() string {
 	return filepath.Join(JumppadHome(), "connector.pid")
@@ -435,6 +540,65 @@ func ImageCacheAddress() string {
 	return jumppadProxyAddress
 }
 
+// RandomPortRange returns the range used for randomly assigned ports, the
+// defaults MinRandomPort and MaxRandomPort can be overridden with the
+// JUMPPAD_MIN_PORT and JUMPPAD_MAX_PORT environment variables, the range must
+// be within 1024-65535
+func RandomPortRange() (int, int, error) {
+	minPort := MinRandomPort
+	maxPort := MaxRandomPort
+
+	if p := os.Getenv("JUMPPAD_MIN_PORT"); p != "" {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for JUMPPAD_MIN_PORT %s, must be a number", p)
+		}
+
+		minPort = v
+	}
+
+	if p := os.Getenv("JUMPPAD_MAX_PORT"); p != "" {
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid value for JUMPPAD_MAX_PORT %s, must be a number", p)
+		}
+
+		maxPort = v
+	}
+
+	if minPort < 1024 || maxPort > 65535 || minPort >= maxPort {
+		return 0, 0, fmt.Errorf("invalid random port range %d-%d, JUMPPAD_MIN_PORT and JUMPPAD_MAX_PORT must be a range within 1024-65535", minPort, maxPort)
+	}
+
+	return minPort, maxPort, nil
+}
+
+// RandomPort returns a random port within the range returned by RandomPortRange
+func RandomPort() (int, error) {
+	minPort, maxPort, err := RandomPortRange()
+	if err != nil {
+		return 0, err
+	}
+
+	return rand.Intn(maxPort-minPort) + minPort, nil
+}
+
+// PortsInUse returns the ports from the given list that are in use on the
+// local machine, a port is in use when a tcp connection can be established
+func PortsInUse(ports ...int) []int {
+	inUse := []int{}
+
+	for _, p := range ports {
+		c, err := net.DialTimeout("tcp", fmt.Sprintf("0.0.0.0:%d", p), 500*time.Millisecond)
+		if err == nil {
+			c.Close()
+			inUse = append(inUse, p)
+		}
+	}
+
+	return inUse
+}
+
 // get all ipaddresses in a subnet
 func SubnetIPs(subnet string) ([]string, error) {
 	_, ipnet, _ := net.ParseCIDR(subnet)